@@ -20,6 +20,9 @@ package cmd
 
 import (
 	"context"
+	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/spf13/cobra"
 
@@ -31,14 +34,13 @@ import (
 )
 
 // NewSchedulerCmd 用于创建定时任务调度器启动命令
-// 需要注意的是：为避免重复执行定时任务，需要确保同时只有一个 scheduler 正在运行
-// 如果希望同时启动多个 scheduler 启动，则需要添加诸如 redis / zk 这样的分布式锁
+// 支持多副本运行：副本间通过 Redis 租约（或 MySQL GET_LOCK）选主，只有 leader 会调度周期任务
 func NewSchedulerCmd() *cobra.Command {
 	var cfgFile string
 
 	schedulerCmd := cobra.Command{
 		Use:   "scheduler",
-		Short: "Execute tasks based on cron expressions, please ensure only one running scheduler.",
+		Short: "Execute tasks based on cron expressions, only the elected leader schedules tasks.",
 		Run: func(cmd *cobra.Command, args []string) {
 			ctx := context.Background()
 			// 加载配置
@@ -70,16 +72,18 @@ func NewSchedulerCmd() *cobra.Command {
 				}()
 			}
 
+			// 初始化异步任务 Broker
+			if err = async.InitBroker(ctx, &cfg.Service.Async); err != nil {
+				log.Fatalf("failed to init async task broker: %s", err)
+			}
+
 			// 初始化 task server
-			async.InitTaskScheduler(ctx)
+			async.InitTaskScheduler(ctx, &cfg.Service.Async)
 
-			srv := async.Scheduler()
-			// 加载周期任务
-			if err = srv.LoadTasks(); err != nil {
-				log.Fatal(err.Error())
-			}
-			// 启用调度服务器
-			srv.Run()
+			// 启用调度服务器：成为 leader 后加载周期任务并开始调度，收到中断信号后退出并释放 leader
+			runCtx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			async.Scheduler().Run(runCtx)
 		},
 	}
 
@@ -0,0 +1,80 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - Go 开发框架 (BlueKing - Go Framework) available.
+ * Copyright (C) 2017 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ *	https://opensource.org/licenses/MIT
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/TencentBlueKing/blueapps-go/pkg/async"
+	log "github.com/TencentBlueKing/blueapps-go/pkg/logging"
+)
+
+// NewSyncPeriodicTasksCmd 用于创建同步声明式周期任务定义的命令
+// 创建新增的、更新有变更的、禁用已移除的周期任务，scheduler 成为 leader 时也会自动同步
+func NewSyncPeriodicTasksCmd() *cobra.Command {
+	var cfgFile, file, operator string
+	var dryRun bool
+
+	syncCmd := cobra.Command{
+		Use:   "sync-periodic-tasks",
+		Short: "Sync periodic tasks from the declarative definition file (periodic_tasks.yaml).",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := context.Background()
+			cfg := initTasksCmdEnv(ctx, cfgFile)
+
+			if file == "" {
+				file = cfg.Service.Async.PeriodicTasksFile
+			}
+			if file == "" {
+				log.Fatal("periodic tasks file not specified, please use --file or set service.async.periodicTasksFile")
+			}
+			results, err := async.SyncPeriodicTasksFromFile(ctx, file, operator, dryRun)
+			if err != nil {
+				log.Fatalf("failed to sync periodic tasks: %s", err)
+			}
+
+			w := newTableWriter()
+			fmt.Fprintln(w, "KEY\tID\tNAME\tACTION")
+			for _, result := range results {
+				fmt.Fprintf(w, "%s\t%d\t%s\t%s\n", result.Key, result.ID, result.Name, result.Action)
+			}
+			_ = w.Flush()
+			if dryRun {
+				fmt.Println("dry run, nothing changed")
+			}
+		},
+	}
+
+	// 配置文件路径，如果未指定，会从环境变量读取各项配置
+	// 注意：目前平台未默认提供配置文件，需通过 `模块配置 - 挂载卷` 添加
+	syncCmd.Flags().StringVar(&cfgFile, "conf", "", "config file")
+	syncCmd.Flags().StringVar(&file, "file", "", "periodic tasks file, default to service.async.periodicTasksFile")
+	syncCmd.Flags().StringVar(&operator, "operator", "admin", "operator username")
+	syncCmd.Flags().BoolVar(&dryRun, "dry-run", false, "print the changes without applying them")
+
+	return &syncCmd
+}
+
+func init() {
+	rootCmd.AddCommand(NewSyncPeriodicTasksCmd())
+}
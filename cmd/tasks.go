@@ -0,0 +1,344 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - Go 开发框架 (BlueKing - Go Framework) available.
+ * Copyright (C) 2017 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ *	https://opensource.org/licenses/MIT
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/samber/lo"
+	"github.com/spf13/cast"
+	"github.com/spf13/cobra"
+
+	"github.com/TencentBlueKing/blueapps-go/pkg/async"
+	"github.com/TencentBlueKing/blueapps-go/pkg/async/progress"
+	"github.com/TencentBlueKing/blueapps-go/pkg/config"
+	"github.com/TencentBlueKing/blueapps-go/pkg/infras/database"
+	log "github.com/TencentBlueKing/blueapps-go/pkg/logging"
+	"github.com/TencentBlueKing/blueapps-go/pkg/model"
+)
+
+// NewTasksCmd 用于创建异步任务运维命令：查看已注册任务，同步执行 / 查询 / 重试任务，管理周期任务
+func NewTasksCmd() *cobra.Command {
+	var cfgFile, operator string
+
+	tasksCmd := cobra.Command{
+		Use:   "tasks",
+		Short: "Inspect and operate async tasks & periodic tasks.",
+	}
+
+	// 配置文件路径，如果未指定，会从环境变量读取各项配置
+	// 注意：目前平台未默认提供配置文件，需通过 `模块配置 - 挂载卷` 添加
+	tasksCmd.PersistentFlags().StringVar(&cfgFile, "conf", "", "config file")
+	// 操作人，记录为下发任务的用户 / 周期任务的更新人
+	tasksCmd.PersistentFlags().StringVar(&operator, "operator", "admin", "operator username")
+
+	periodicCmd := cobra.Command{
+		Use:   "periodic",
+		Short: "Manage periodic tasks.",
+	}
+	periodicCmd.AddCommand(
+		newPeriodicTasksListCmd(&cfgFile),
+		newPeriodicTaskToggleCmd(&cfgFile, &operator, true),
+		newPeriodicTaskToggleCmd(&cfgFile, &operator, false),
+		newPeriodicTaskTriggerCmd(&cfgFile, &operator),
+	)
+
+	tasksCmd.AddCommand(
+		newTasksListCmd(),
+		newTaskRunCmd(&cfgFile),
+		newTaskStatusCmd(&cfgFile),
+		newTaskRetryCmd(&cfgFile, &operator),
+		&periodicCmd,
+	)
+	return &tasksCmd
+}
+
+// 查看已注册的任务
+func newTasksListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List registered tasks and their signatures.",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			w := newTableWriter()
+			fmt.Fprintln(w, "NAME\tARGS\tARGS EXAMPLE\tRESULT\tQUEUE\tTIMEOUT\tMAX ATTEMPTS\tMAX CONCURRENCY\tUNIQUE")
+			for _, task := range async.RegisteredTasks() {
+				fmt.Fprintf(
+					w, "%s\t%s\t%s\t%s\t%s\t%s\t%d\t%s\t%s\n",
+					task.Name, task.ArgsType, task.ArgsExample, task.ResultType, task.Queue,
+					formatDuration(task.Timeout), task.MaxAttempts,
+					lo.Ternary(task.MaxConcurrency == 0, "-", cast.ToString(task.MaxConcurrency)),
+					formatDuration(task.UniqueTTL),
+				)
+			}
+			_ = w.Flush()
+		},
+	}
+}
+
+// 在当前进程中同步执行任务（不创建任务记录），用于调试
+func newTaskRunCmd(cfgFile *string) *cobra.Command {
+	var rawArgs string
+	var timeout time.Duration
+
+	runCmd := cobra.Command{
+		Use:   "run NAME",
+		Short: "Run a task synchronously in the current process and print the result.",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := context.Background()
+			initTasksCmdEnv(ctx, *cfgFile)
+
+			// 任务上报的进度输出到标准错误，不影响标准输出中的执行结果
+			ctx = progress.WithReporter(ctx, progress.ReporterFunc(func(percent float64, message string, _ any) {
+				fmt.Fprintf(os.Stderr, "[%6.2f%%] %s\n", percent, message)
+			}))
+			result, err := async.RunTask(ctx, args[0], json.RawMessage(rawArgs), timeout)
+			if err != nil {
+				log.Fatalf("failed to run task %s: %s", args[0], err)
+			}
+			data, _ := json.MarshalIndent(result, "", "  ")
+			fmt.Println(string(data))
+		},
+	}
+
+	runCmd.Flags().StringVar(&rawArgs, "args", "", "task args (JSON)")
+	runCmd.Flags().DurationVar(&timeout, "timeout", 0, "execution timeout, default to the task's default timeout")
+	return &runCmd
+}
+
+// 查看任务状态 & 执行记录
+func newTaskStatusCmd(cfgFile *string) *cobra.Command {
+	return &cobra.Command{
+		Use:   "status ID",
+		Short: "Show task status, result and attempt history.",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := context.Background()
+			initTasksCmdEnv(ctx, *cfgFile)
+
+			var task model.Task
+			if err := database.Client(ctx).First(&task, parseID(args[0])).Error; err != nil {
+				log.Fatalf("failed to get task %s: %s", args[0], err)
+			}
+			var attempts []model.TaskAttempt
+			if err := database.Client(ctx).Where("task_id = ?", task.ID).Order("attempt").Find(&attempts).Error; err != nil {
+				log.Fatalf("failed to get task %d attempts: %s", task.ID, err)
+			}
+
+			w := newTableWriter()
+			fmt.Fprintf(w, "ID:\t%d\n", task.ID)
+			fmt.Fprintf(w, "Kind:\t%s\n", task.Kind)
+			if task.ParentID != 0 {
+				fmt.Fprintf(w, "Parent ID:\t%d\n", task.ParentID)
+			}
+			fmt.Fprintf(w, "Name:\t%s\n", task.Name)
+			fmt.Fprintf(w, "Args:\t%s\n", task.Args)
+			fmt.Fprintf(w, "Status:\t%s\n", task.Status)
+			fmt.Fprintf(w, "Result:\t%s\n", task.Result)
+			fmt.Fprintf(w, "Error:\t%s\n", task.Error)
+			fmt.Fprintf(w, "Attempts:\t%d\n", task.Attempts)
+			fmt.Fprintf(w, "Creator:\t%s\n", task.Creator)
+			fmt.Fprintf(w, "Created At:\t%s\n", formatTime(task.CreatedAt))
+			fmt.Fprintf(w, "Started At:\t%s\n", formatTime(task.StartedAt))
+			fmt.Fprintf(w, "Finished At:\t%s\n", formatTime(task.FinishedAt))
+			fmt.Fprintf(w, "Duration:\t%s\n", formatDuration(task.Duration))
+			_ = w.Flush()
+
+			if len(attempts) == 0 {
+				return
+			}
+			fmt.Println()
+			w = newTableWriter()
+			fmt.Fprintln(w, "ATTEMPT\tSTATUS\tSTARTED AT\tDURATION\tERROR")
+			for _, attempt := range attempts {
+				fmt.Fprintf(
+					w, "%d\t%s\t%s\t%s\t%s\n", attempt.Attempt, attempt.Status,
+					formatTime(attempt.StartedAt), formatDuration(attempt.Duration), attempt.Error,
+				)
+			}
+			_ = w.Flush()
+		},
+	}
+}
+
+// 重新下发已结束但未成功的任务
+func newTaskRetryCmd(cfgFile, operator *string) *cobra.Command {
+	return &cobra.Command{
+		Use:   "retry ID",
+		Short: "Re-apply a failed / timeout / cancelled task with the same args.",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := context.Background()
+			cfg := initTasksCmdEnv(ctx, *cfgFile)
+			mustUsePersistentBroker(cfg)
+
+			taskID, err := async.RetryTask(ctx, parseID(args[0]), *operator)
+			if err != nil {
+				log.Fatalf("failed to retry task %s: %s", args[0], err)
+			}
+			fmt.Printf("task %s retried, new task id: %d\n", args[0], taskID)
+		},
+	}
+}
+
+// 查看周期任务
+func newPeriodicTasksListCmd(cfgFile *string) *cobra.Command {
+	return &cobra.Command{
+		Use:   "ls",
+		Short: "List periodic tasks.",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := context.Background()
+			initTasksCmdEnv(ctx, *cfgFile)
+
+			var periodicTasks []model.PeriodicTask
+			if err := database.Client(ctx).Order("id").Find(&periodicTasks).Error; err != nil {
+				log.Fatalf("failed to list periodic tasks: %s", err)
+			}
+
+			now := time.Now()
+			w := newTableWriter()
+			fmt.Fprintln(w, "ID\tNAME\tSCHEDULE\tTIMEZONE\tENABLED\tNEXT RUN AT\tARGS")
+			for _, task := range periodicTasks {
+				schedule := lo.Ternary(task.ETA.IsZero(), task.Cron, "eta: "+formatTime(task.ETA))
+				nextRunAt := "-"
+				if task.Enabled {
+					if next, err := async.NextRunAt(&task, now); err == nil {
+						nextRunAt = formatTime(next)
+					}
+				}
+				fmt.Fprintf(
+					w, "%d\t%s\t%s\t%s\t%t\t%s\t%s\n", task.ID, task.Name, schedule,
+					lo.Ternary(task.Timezone == "", "-", task.Timezone), task.Enabled, nextRunAt, task.Args,
+				)
+			}
+			_ = w.Flush()
+		},
+	}
+}
+
+// 启用 / 禁用周期任务
+func newPeriodicTaskToggleCmd(cfgFile, operator *string, enabled bool) *cobra.Command {
+	action := lo.Ternary(enabled, "enable", "disable")
+	return &cobra.Command{
+		Use:   action + " ID",
+		Short: strings.ToUpper(action[:1]) + action[1:] + " a periodic task.",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := context.Background()
+			initTasksCmdEnv(ctx, *cfgFile)
+
+			var periodicTask model.PeriodicTask
+			if err := database.Client(ctx).First(&periodicTask, parseID(args[0])).Error; err != nil {
+				log.Fatalf("failed to get periodic task %s: %s", args[0], err)
+			}
+			if periodicTask.Managed() {
+				log.Fatalf("periodic task %d is managed by the definition file (key: %s), please modify the file instead",
+					periodicTask.ID, periodicTask.ManagedKey)
+			}
+			err := database.Client(ctx).Model(&periodicTask).Updates(map[string]any{
+				"enabled": enabled,
+				"updater": *operator,
+			}).Error
+			if err != nil {
+				log.Fatalf("failed to %s periodic task %d: %s", action, periodicTask.ID, err)
+			}
+			// 通知 scheduler 立即注册 / 注销该周期任务
+			async.NotifyPeriodicTaskChanged(ctx, periodicTask.ID)
+			fmt.Printf("periodic task %d %sd\n", periodicTask.ID, action)
+		},
+	}
+}
+
+// 立即触发一次周期任务
+func newPeriodicTaskTriggerCmd(cfgFile, operator *string) *cobra.Command {
+	return &cobra.Command{
+		Use:   "trigger ID",
+		Short: "Trigger a periodic task immediately.",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := context.Background()
+			cfg := initTasksCmdEnv(ctx, *cfgFile)
+			mustUsePersistentBroker(cfg)
+
+			taskID, err := async.TriggerPeriodicTask(ctx, parseID(args[0]), *operator)
+			if err != nil {
+				log.Fatalf("failed to trigger periodic task %s: %s", args[0], err)
+			}
+			fmt.Printf("periodic task %s triggered, task id: %d\n", args[0], taskID)
+		},
+	}
+}
+
+// 加载配置 & 初始化日志、增强服务客户端及异步任务 Broker（与其他命令一致）
+func initTasksCmdEnv(ctx context.Context, cfgFile string) *config.Config {
+	cfg, err := config.Load(ctx, cfgFile)
+	if err != nil {
+		log.Fatalf("failed to load config: %s", err)
+	}
+	if err = initLogger(&cfg.Service.Log); err != nil {
+		log.Fatalf("failed to init logging: %s", err)
+	}
+	if err = initAddons(ctx, cfg); err != nil {
+		log.Fatalf("failed to init addons: %s", err)
+	}
+	if err = async.InitBroker(ctx, &cfg.Service.Async); err != nil {
+		log.Fatalf("failed to init async task broker: %s", err)
+	}
+	return cfg
+}
+
+// 下发任务需要使用持久化队列：local 模式下任务在当前进程中执行，命令退出后任务即丢失
+func mustUsePersistentBroker(cfg *config.Config) {
+	if broker := cfg.Service.Async.Broker; broker == "" || broker == async.BrokerLocal {
+		log.Fatal("async task broker is local, tasks applied by the command would be lost after exit, " +
+			"please use redis / rabbitmq broker or operate via the web page")
+	}
+}
+
+func parseID(s string) int64 {
+	id, err := cast.ToInt64E(s)
+	if err != nil || id <= 0 {
+		log.Fatalf("invalid id: %s", s)
+	}
+	return id
+}
+
+func newTableWriter() *tabwriter.Writer {
+	return tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+}
+
+func formatTime(t time.Time) string {
+	return lo.Ternary(t.IsZero(), "-", t.Local().Format(time.RFC3339))
+}
+
+func formatDuration(d time.Duration) string {
+	return lo.Ternary(d == 0, "-", d.String())
+}
+
+func init() {
+	rootCmd.AddCommand(NewTasksCmd())
+}
@@ -28,6 +28,7 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/TencentBlueKing/blueapps-go/pkg/async"
 	"github.com/TencentBlueKing/blueapps-go/pkg/config"
 	"github.com/TencentBlueKing/blueapps-go/pkg/i18n"
 	"github.com/TencentBlueKing/blueapps-go/pkg/infras/otel"
@@ -73,6 +74,11 @@ func NewWebServerCmd() *cobra.Command {
 				}()
 			}
 
+			// 初始化异步任务 Broker
+			if err = async.InitBroker(ctx, &cfg.Service.Async); err != nil {
+				log.Fatalf("failed to init async task broker: %s", err)
+			}
+
 			// 启动 Web 服务
 			log.Infof(ctx, "Starting server at http://0.0.0.0:%d", config.G.Service.Server.Port)
 			srv := &http.Server{
@@ -0,0 +1,116 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - Go 开发框架 (BlueKing - Go Framework) available.
+ * Copyright (C) 2017 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ *	https://opensource.org/licenses/MIT
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cmd
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/TencentBlueKing/blueapps-go/pkg/async"
+	"github.com/TencentBlueKing/blueapps-go/pkg/config"
+	"github.com/TencentBlueKing/blueapps-go/pkg/i18n"
+	"github.com/TencentBlueKing/blueapps-go/pkg/infras/otel"
+	log "github.com/TencentBlueKing/blueapps-go/pkg/logging"
+)
+
+// NewWorkerCmd 用于创建异步任务消费者（worker）启动命令
+// 注意：仅当异步任务 Broker 为持久化队列（如 redis）时，worker 才有任务可消费，支持多副本运行
+func NewWorkerCmd() *cobra.Command {
+	var cfgFile string
+
+	workerCmd := cobra.Command{
+		Use:   "worker",
+		Short: "Consume and execute async tasks from the task broker.",
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := context.Background()
+			// 加载配置
+			cfg, err := config.Load(ctx, cfgFile)
+			if err != nil {
+				log.Fatalf("failed to load config: %s", err)
+			}
+
+			// 初始化 i18n
+			i18n.InitMsgMap()
+			// 初始化 Logger
+			if err = initLogger(&cfg.Service.Log); err != nil {
+				log.Fatalf("failed to init logging: %s", err)
+			}
+			// 初始化增强服务客户端
+			if err = initAddons(ctx, cfg); err != nil {
+				log.Fatalf("failed to init addons: %s", err)
+			}
+			// 初始化 OpenTelemetry
+			if cfg.Platform.Addons.BkOtel != nil {
+				shutdown, sErr := otel.InitTracer(ctx, cfg.Platform.Addons.BkOtel, otel.GenServiceName("worker"))
+				if sErr != nil {
+					log.Fatalf("failed to init OpenTelemetry: %s", sErr)
+				}
+				defer func() {
+					if err = shutdown(ctx); err != nil {
+						log.Fatalf("failed to shutdown OpenTelemetry: %s", err)
+					}
+				}()
+			}
+
+			// 初始化异步任务 Broker
+			if err = async.InitBroker(ctx, &cfg.Service.Async); err != nil {
+				log.Fatalf("failed to init async task broker: %s", err)
+			}
+
+			// 启动 worker，收到中断信号后停止拉取新任务
+			workerCtx, cancel := context.WithCancel(ctx)
+			done := make(chan struct{})
+			go func() {
+				async.NewWorker(&cfg.Service.Async).Run(workerCtx)
+				close(done)
+			}()
+
+			// 等待中断信号以优雅地关闭 worker
+			quit := make(chan os.Signal, 1)
+			signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
+			<-quit
+
+			log.Info(ctx, "Shutdown worker ...")
+			cancel()
+
+			// 等待执行中的任务完成，超时未完成的任务会在可见性超时后被其他 worker 重新执行
+			select {
+			case <-done:
+				log.Info(ctx, "Worker exiting")
+			case <-time.After(time.Duration(cfg.Service.Server.GraceTimeout) * time.Second):
+				log.Warn(ctx, "Worker exiting with unfinished tasks")
+			}
+		},
+	}
+
+	// 配置文件路径，如果未指定，会从环境变量读取各项配置
+	// 注意：目前平台未默认提供配置文件，需通过 `模块配置 - 挂载卷` 添加
+	workerCmd.Flags().StringVar(&cfgFile, "conf", "", "config file")
+
+	return &workerCmd
+}
+
+func init() {
+	rootCmd.AddCommand(NewWorkerCmd())
+}
@@ -0,0 +1,80 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - Go 开发框架 (BlueKing - Go Framework) available.
+ * Copyright (C) 2017 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ *	https://opensource.org/licenses/MIT
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	"github.com/samber/lo"
+
+	"github.com/TencentBlueKing/blueapps-go/pkg/apis/asynctask/serializer"
+	"github.com/TencentBlueKing/blueapps-go/pkg/async"
+	"github.com/TencentBlueKing/blueapps-go/pkg/config"
+	"github.com/TencentBlueKing/blueapps-go/pkg/i18n"
+	"github.com/TencentBlueKing/blueapps-go/pkg/utils/ginx"
+)
+
+// PreviewCron ...
+//
+//	@Summary	预览 cron 表达式（接下来的触发时间 & 自然语言描述）
+//	@Tags		async-task
+//	@Param		expr	query		string	true	"cron 表达式"
+//	@Param		tz		query		string	false	"IANA 时区，如 Asia/Shanghai"
+//	@Param		count	query		int		false	"预览的触发次数，默认 5 次"
+//	@Success	200		{object}	ginx.Response{data=serializer.CronPreviewResponse}
+//	@Router		/api/cron/preview [get]
+func PreviewCron(c *gin.Context) {
+	var req serializer.CronPreviewRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		ginx.SetErrResp(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := req.Validate(c); err != nil {
+		ginx.SetErrResp(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx := c.Request.Context()
+	preview, err := async.PreviewCron(req.Expr, req.TZ, req.Count, time.Now())
+	if err != nil {
+		ginx.SetErrResp(c, http.StatusBadRequest, errors.Wrap(err, i18n.T(ctx, "cron invalid")).Error())
+		return
+	}
+	description, err := async.DescribeCron(ctx, req.Expr)
+	if err != nil {
+		ginx.SetErrResp(c, http.StatusBadRequest, errors.Wrap(err, i18n.T(ctx, "cron invalid")).Error())
+		return
+	}
+
+	minAllowedInterval := config.G.Service.Async.MinCronInterval
+	respData := serializer.CronPreviewResponse{
+		Description: description,
+		NextRunAt: lo.Map(preview.NextRunAt, func(t time.Time, _ int) string {
+			return t.Format(time.RFC3339)
+		}),
+		MinInterval:        preview.MinInterval.Seconds(),
+		MinAllowedInterval: minAllowedInterval,
+		TooFrequent: preview.MinInterval > 0 &&
+			preview.MinInterval < time.Duration(minAllowedInterval)*time.Second,
+	}
+	ginx.SetResp(c, http.StatusOK, respData)
+}
@@ -0,0 +1,234 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - Go 开发框架 (BlueKing - Go Framework) available.
+ * Copyright (C) 2017 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ *	https://opensource.org/licenses/MIT
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/samber/lo"
+	"github.com/spf13/cast"
+
+	"github.com/TencentBlueKing/blueapps-go/pkg/apis/asynctask/serializer"
+	"github.com/TencentBlueKing/blueapps-go/pkg/async"
+	"github.com/TencentBlueKing/blueapps-go/pkg/i18n"
+	"github.com/TencentBlueKing/blueapps-go/pkg/infras/database"
+	"github.com/TencentBlueKing/blueapps-go/pkg/model"
+	"github.com/TencentBlueKing/blueapps-go/pkg/utils/ginx"
+)
+
+// ListDeadLetters ...
+//
+//	@Summary	获取死信列表
+//	@Tags		async-task
+//	@Param		name		query		string	false	"任务名称"
+//	@Param		replayed	query		string	false	"是否已重新下发"	Enums(true, false)
+//	@Success	200			{object}	ginx.Response{data=ginx.PaginatedResp{results=[]serializer.DeadLetterListResponse}}
+//	@Router		/api/dead-letters [get]
+func ListDeadLetters(c *gin.Context) {
+	var req serializer.DeadLetterListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		ginx.SetErrResp(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	tx := database.Client(c.Request.Context()).Order("id desc").Model(&model.DeadLetter{})
+	if req.Name != "" {
+		tx = tx.Where("name = ?", req.Name)
+	}
+	if req.Replayed != "" {
+		tx = tx.Where(lo.Ternary(cast.ToBool(req.Replayed), "replay_count > 0", "replay_count = 0"))
+	}
+
+	// 总条目数量
+	var total int64
+	if err := tx.Count(&total).Error; err != nil {
+		ginx.SetErrResp(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	var letters []model.DeadLetter
+	if err := tx.Offset(ginx.GetOffset(c)).Limit(ginx.GetLimit(c)).Find(&letters).Error; err != nil {
+		ginx.SetErrResp(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respData := []serializer.DeadLetterListResponse{}
+	for _, letter := range letters {
+		respData = append(respData, serializer.DeadLetterListResponse{
+			ID:             letter.ID,
+			TaskID:         letter.TaskID,
+			Name:           letter.Name,
+			Args:           string(letter.Args),
+			Status:         string(letter.Status),
+			Error:          letter.Error,
+			Attempts:       letter.Attempts,
+			Panicked:       letter.Stack != "",
+			FailedAt:       letter.FailedAt.Format(time.RFC3339),
+			ReplayCount:    letter.ReplayCount,
+			ReplayedTaskID: letter.ReplayedTaskID,
+			ReplayedAt:     lo.Ternary(letter.ReplayedAt.IsZero(), "", letter.ReplayedAt.Format(time.RFC3339)),
+		})
+	}
+	ginx.SetResp(c, http.StatusOK, ginx.NewPaginatedRespData(total, respData))
+}
+
+// RetrieveDeadLetter ...
+//
+//	@Summary	获取单个死信
+//	@Tags		async-task
+//	@Param		id	path		int	true	"死信 ID"
+//	@Success	200	{object}	ginx.Response{data=serializer.DeadLetterRetrieveResponse}
+//	@Router		/api/dead-letters/{id} [get]
+func RetrieveDeadLetter(c *gin.Context) {
+	var letter model.DeadLetter
+	if err := database.Client(c.Request.Context()).Where("id = ?", c.Param("id")).First(&letter).Error; err != nil {
+		ginx.SetErrResp(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	var attempts []model.TaskAttempt
+	if len(letter.AttemptHistory) != 0 {
+		if err := json.Unmarshal(letter.AttemptHistory, &attempts); err != nil {
+			ginx.SetErrResp(c, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+	attemptHistory := []serializer.TaskAttemptResponse{}
+	for _, attempt := range attempts {
+		attemptHistory = append(attemptHistory, serializer.TaskAttemptResponse{
+			Attempt:    attempt.Attempt,
+			Status:     string(attempt.Status),
+			Error:      attempt.Error,
+			StartedAt:  attempt.StartedAt.Format(time.RFC3339),
+			FinishedAt: lo.Ternary(attempt.FinishedAt.IsZero(), "", attempt.FinishedAt.Format(time.RFC3339)),
+			Duration:   attempt.Duration.Seconds(),
+		})
+	}
+
+	ginx.SetResp(c, http.StatusOK, serializer.DeadLetterRetrieveResponse{
+		ID:             letter.ID,
+		TaskID:         letter.TaskID,
+		Name:           letter.Name,
+		Args:           string(letter.Args),
+		Queue:          letter.Queue,
+		Status:         string(letter.Status),
+		Error:          letter.Error,
+		Stack:          letter.Stack,
+		Attempts:       letter.Attempts,
+		RequestID:      letter.RequestID,
+		TraceID:        letter.TraceID,
+		Creator:        letter.Creator,
+		FailedAt:       letter.FailedAt.Format(time.RFC3339),
+		ReplayCount:    letter.ReplayCount,
+		ReplayedBy:     lo.Ternary(letter.ReplayCount == 0, "", letter.Updater),
+		ReplayedTaskID: letter.ReplayedTaskID,
+		ReplayedAt:     lo.Ternary(letter.ReplayedAt.IsZero(), "", letter.ReplayedAt.Format(time.RFC3339)),
+		AttemptHistory: attemptHistory,
+	})
+}
+
+// ReplayDeadLetter ...
+//
+//	@Summary	重新下发死信中的任务（可修正任务参数）
+//	@Tags		async-task
+//	@Param		id		path		int									true	"死信 ID"
+//	@Param		body	body		serializer.DeadLetterReplayRequest	true	"修正后的任务参数"
+//	@Success	201		{object}	ginx.Response{data=serializer.DeadLetterReplayResponse}
+//	@Router		/api/dead-letters/{id}/replay [post]
+func ReplayDeadLetter(c *gin.Context) {
+	var req serializer.DeadLetterReplayRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ginx.SetErrResp(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var letter model.DeadLetter
+	tx := database.Client(c.Request.Context()).Select("id", "name").Where("id = ?", c.Param("id")).First(&letter)
+	if tx.Error != nil {
+		ginx.SetErrResp(c, http.StatusNotFound, tx.Error.Error())
+		return
+	}
+	if err := serializer.ValidateReplayArgs(c, letter.Name, req.Args); err != nil {
+		ginx.SetErrResp(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// 异步任务执行，不使用 c.Request.Context() 以避免提前 cancel（保留 RequestID 以便串联日志）
+	ctx := context.WithoutCancel(c.Request.Context())
+	taskID, err := async.ReplayDeadLetter(ctx, letter.ID, req.Args, ginx.GetUserID(c))
+	if err != nil {
+		status, msg := applyTaskErrResp(c, taskID, err)
+		ginx.SetErrResp(c, status, msg)
+		return
+	}
+	ginx.SetResp(c, http.StatusCreated, serializer.DeadLetterReplayResponse{ID: letter.ID, TaskID: taskID})
+}
+
+// BulkReplayDeadLetters ...
+//
+//	@Summary	批量重新下发死信中的任务（可逐个修正任务参数），返回每个死信的下发结果
+//	@Tags		async-task
+//	@Param		body	body		serializer.DeadLetterBulkReplayRequest	true	"待重新下发的死信"
+//	@Success	200		{object}	ginx.Response{data=[]serializer.DeadLetterReplayResponse}
+//	@Router		/api/dead-letters/replay [post]
+func BulkReplayDeadLetters(c *gin.Context) {
+	var req serializer.DeadLetterBulkReplayRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ginx.SetErrResp(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ids := lo.Map(req.Items, func(item serializer.DeadLetterReplayItem, _ int) int64 { return item.ID })
+	var letters []model.DeadLetter
+	if err := database.Client(c.Request.Context()).Select("id", "name").Where("id IN ?", ids).Find(&letters).Error; err != nil {
+		ginx.SetErrResp(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	names := lo.SliceToMap(letters, func(l model.DeadLetter) (int64, string) { return l.ID, l.Name })
+
+	// 逐个下发，单个死信下发失败不影响其他死信
+	ctx := context.WithoutCancel(c.Request.Context())
+	respData := []serializer.DeadLetterReplayResponse{}
+	for _, item := range req.Items {
+		result := serializer.DeadLetterReplayResponse{ID: item.ID}
+		name, ok := names[item.ID]
+		if !ok {
+			result.Error = i18n.T(c.Request.Context(), "Dead letter not found")
+			respData = append(respData, result)
+			continue
+		}
+		if err := serializer.ValidateReplayArgs(c, name, item.Args); err != nil {
+			result.Error = err.Error()
+			respData = append(respData, result)
+			continue
+		}
+		taskID, err := async.ReplayDeadLetter(ctx, item.ID, item.Args, ginx.GetUserID(c))
+		if err != nil {
+			_, result.Error = applyTaskErrResp(c, taskID, err)
+		} else {
+			result.TaskID = taskID
+		}
+		respData = append(respData, result)
+	}
+	ginx.SetResp(c, http.StatusOK, respData)
+}
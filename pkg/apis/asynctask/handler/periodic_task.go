@@ -20,12 +20,18 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/samber/lo"
 
 	"github.com/TencentBlueKing/blueapps-go/pkg/apis/asynctask/serializer"
+	"github.com/TencentBlueKing/blueapps-go/pkg/async"
+	"github.com/TencentBlueKing/blueapps-go/pkg/i18n"
 	"github.com/TencentBlueKing/blueapps-go/pkg/infras/database"
 	"github.com/TencentBlueKing/blueapps-go/pkg/model"
 	"github.com/TencentBlueKing/blueapps-go/pkg/utils/ginx"
@@ -44,20 +50,126 @@ func ListPeriodicTasks(c *gin.Context) {
 		return
 	}
 
+	// 各周期任务最近一次的触发记录
+	var lastRuns []model.PeriodicTaskRun
+	if err := database.Client(c.Request.Context()).
+		Where("id IN (?)", database.Client(c.Request.Context()).
+			Model(&model.PeriodicTaskRun{}).
+			Select("MAX(id)").
+			Group("periodic_task_id")).
+		Find(&lastRuns).Error; err != nil {
+		ginx.SetErrResp(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	lastRunMap := lo.SliceToMap(lastRuns, func(run model.PeriodicTaskRun) (int64, model.PeriodicTaskRun) {
+		return run.PeriodicTaskID, run
+	})
+	taskStatuses, err := getTaskStatuses(c.Request.Context(), lastRuns)
+	if err != nil {
+		ginx.SetErrResp(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	now := time.Now()
 	respData := []serializer.PeriodicTaskListResponse{}
 	for _, task := range periodicTasks {
-		respData = append(respData, serializer.PeriodicTaskListResponse{
-			ID:      task.ID,
-			Cron:    task.Cron,
-			Name:    task.Name,
-			Args:    string(task.Args),
-			Enabled: task.Enabled,
-			Creator: task.Creator,
-		})
+		data := serializer.PeriodicTaskListResponse{
+			ID:          task.ID,
+			Cron:        task.Cron,
+			Timezone:    task.Timezone,
+			StartAt:     lo.Ternary(task.StartAt.IsZero(), "", task.StartAt.Format(time.RFC3339)),
+			EndAt:       lo.Ternary(task.EndAt.IsZero(), "", task.EndAt.Format(time.RFC3339)),
+			ETA:         lo.Ternary(task.ETA.IsZero(), "", task.ETA.Format(time.RFC3339)),
+			CompletedAt: lo.Ternary(task.CompletedAt.IsZero(), "", task.CompletedAt.Format(time.RFC3339)),
+
+			MisfirePolicy: string(task.MisfirePolicy),
+			MisfireLimit:  task.MisfireLimit,
+			Name:          task.Name,
+			Args:          string(task.Args),
+			Enabled:       task.Enabled,
+			Creator:       task.Creator,
+			ManagedKey:    task.ManagedKey,
+		}
+		if run, ok := lastRunMap[task.ID]; ok {
+			data.LastRunAt = run.ScheduledAt.Format(time.RFC3339)
+			data.LastStatus = async.PeriodicTaskRunOutcome(&run, taskStatuses)
+		}
+		if task.Enabled {
+			if nextRunAt, nErr := async.NextRunAt(&task, now); nErr == nil && !nextRunAt.IsZero() {
+				data.NextRunAt = nextRunAt.Format(time.RFC3339)
+			}
+		}
+		respData = append(respData, data)
 	}
 	ginx.SetResp(c, http.StatusOK, respData)
 }
 
+// ListPeriodicTaskRuns ...
+//
+//	@Summary	获取定时任务触发记录
+//	@Tags		async-task
+//	@Param		id	path		int	true	"定时任务 ID"
+//	@Success	200	{object}	ginx.Response{data=ginx.PaginatedResp{results=[]serializer.PeriodicTaskRunListResponse}}
+//	@Router		/api/periodic-tasks/{id}/runs [get]
+func ListPeriodicTaskRuns(c *gin.Context) {
+	ctx := c.Request.Context()
+	tx := database.Client(ctx).
+		Model(&model.PeriodicTaskRun{}).
+		Where("periodic_task_id = ?", c.Param("id")).
+		Order("scheduled_at DESC")
+
+	// 总条目数量
+	var total int64
+	if err := tx.Count(&total).Error; err != nil {
+		ginx.SetErrResp(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	var runs []model.PeriodicTaskRun
+	if err := tx.Offset(ginx.GetOffset(c)).Limit(ginx.GetLimit(c)).Find(&runs).Error; err != nil {
+		ginx.SetErrResp(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	taskStatuses, err := getTaskStatuses(ctx, runs)
+	if err != nil {
+		ginx.SetErrResp(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respData := []serializer.PeriodicTaskRunListResponse{}
+	for _, run := range runs {
+		respData = append(respData, serializer.PeriodicTaskRunListResponse{
+			ID:          run.ID,
+			ScheduledAt: run.ScheduledAt.Format(time.RFC3339),
+			StartedAt:   lo.Ternary(run.StartedAt.IsZero(), "", run.StartedAt.Format(time.RFC3339)),
+			TaskID:      run.TaskID,
+			Status:      async.PeriodicTaskRunOutcome(&run, taskStatuses),
+			Error:       run.Error,
+			CatchUp:     run.CatchUp,
+			Manual:      run.Manual,
+		})
+	}
+	ginx.SetResp(c, http.StatusOK, ginx.NewPaginatedRespData(total, respData))
+}
+
+// 获取触发记录关联任务的状态：任务 ID -> 任务状态
+func getTaskStatuses(ctx context.Context, runs []model.PeriodicTaskRun) (map[int64]model.TaskStatus, error) {
+	taskIDs := lo.FilterMap(runs, func(run model.PeriodicTaskRun, _ int) (int64, bool) {
+		return run.TaskID, run.TaskID != 0
+	})
+	if len(taskIDs) == 0 {
+		return map[int64]model.TaskStatus{}, nil
+	}
+
+	var tasks []model.Task
+	if err := database.Client(ctx).Select("id", "status").Where("id IN ?", taskIDs).Find(&tasks).Error; err != nil {
+		return nil, err
+	}
+	return lo.SliceToMap(tasks, func(task model.Task) (int64, model.TaskStatus) {
+		return task.ID, task.Status
+	}), nil
+}
+
 // CreatePeriodicTask ...
 //
 //	@Summary	创建定时任务
@@ -79,9 +191,16 @@ func CreatePeriodicTask(c *gin.Context) {
 
 	args, _ := json.Marshal(req.Args)
 	periodicTask := model.PeriodicTask{
-		Cron: req.Cron,
-		Name: req.Name,
-		Args: args,
+		Cron:     req.Cron,
+		Timezone: req.Timezone,
+		StartAt:  req.StartAt,
+		EndAt:    req.EndAt,
+		ETA:      req.ETA,
+		// 为空时使用默认值（跳过 / 最多补跑 10 次）
+		MisfirePolicy: model.MisfirePolicy(req.MisfirePolicy),
+		MisfireLimit:  req.MisfireLimit,
+		Name:          req.Name,
+		Args:          args,
 		BaseModel: model.BaseModel{
 			Creator: ginx.GetUserID(c),
 			Updater: ginx.GetUserID(c),
@@ -91,6 +210,8 @@ func CreatePeriodicTask(c *gin.Context) {
 		ginx.SetErrResp(c, http.StatusInternalServerError, err.Error())
 		return
 	}
+	// 通知 scheduler 立即注册该周期任务
+	async.NotifyPeriodicTaskChanged(c.Request.Context(), periodicTask.ID)
 
 	ginx.SetResp(c, http.StatusCreated, nil)
 }
@@ -103,11 +224,23 @@ func CreatePeriodicTask(c *gin.Context) {
 //	@Success	204	"No Content"
 //	@Router		/api/periodic-tasks/{id} [delete]
 func DeletePeriodicTask(c *gin.Context) {
-	tx := database.Client(c.Request.Context()).Where("id = ?", c.Param("id")).Delete(&model.PeriodicTask{})
-	if tx.Error != nil {
-		ginx.SetErrResp(c, http.StatusInternalServerError, tx.Error.Error())
+	var periodicTask model.PeriodicTask
+	ctx := c.Request.Context()
+	if err := database.Client(ctx).Where("id = ?", c.Param("id")).First(&periodicTask).Error; err != nil {
+		ginx.SetErrResp(c, http.StatusNotFound, err.Error())
+		return
+	}
+	if periodicTask.Managed() {
+		ginx.SetErrResp(c, http.StatusConflict, managedPeriodicTaskErrMsg(c, &periodicTask))
 		return
 	}
+
+	if err := database.Client(ctx).Delete(&periodicTask).Error; err != nil {
+		ginx.SetErrResp(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	// 通知 scheduler 立即注销该周期任务
+	async.NotifyPeriodicTaskChanged(ctx, periodicTask.ID)
 	ginx.SetResp(c, http.StatusNoContent, nil)
 }
 
@@ -126,6 +259,10 @@ func TogglePeriodicTaskEnabled(c *gin.Context) {
 		ginx.SetErrResp(c, http.StatusNotFound, tx.Error.Error())
 		return
 	}
+	if periodicTask.Managed() {
+		ginx.SetErrResp(c, http.StatusConflict, managedPeriodicTaskErrMsg(c, &periodicTask))
+		return
+	}
 
 	periodicTask.Enabled = !periodicTask.Enabled
 	periodicTask.Updater = ginx.GetUserID(c)
@@ -135,5 +272,15 @@ func TogglePeriodicTaskEnabled(c *gin.Context) {
 		ginx.SetErrResp(c, http.StatusInternalServerError, tx.Error.Error())
 		return
 	}
+	// 通知 scheduler 立即注册 / 注销该周期任务
+	async.NotifyPeriodicTaskChanged(ctx, periodicTask.ID)
 	ginx.SetResp(c, http.StatusOK, serializer.TogglePeriodicTaskEnabledResponse{Enabled: periodicTask.Enabled})
 }
+
+// 由声明式定义管理的周期任务只能通过修改定义文件变更
+func managedPeriodicTaskErrMsg(c *gin.Context, periodicTask *model.PeriodicTask) string {
+	return fmt.Sprintf(
+		i18n.T(c.Request.Context(), "Periodic task %d is managed by the definition file (key: %s), please modify the file instead"),
+		periodicTask.ID, periodicTask.ManagedKey,
+	)
+}
@@ -19,54 +19,124 @@
 package handler
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
 	"github.com/samber/lo"
 
 	"github.com/TencentBlueKing/blueapps-go/pkg/apis/asynctask/serializer"
 	"github.com/TencentBlueKing/blueapps-go/pkg/async"
+	"github.com/TencentBlueKing/blueapps-go/pkg/i18n"
 	"github.com/TencentBlueKing/blueapps-go/pkg/infras/database"
 	"github.com/TencentBlueKing/blueapps-go/pkg/model"
 	"github.com/TencentBlueKing/blueapps-go/pkg/utils/ginx"
 )
 
+const (
+	// 任务事件流轮询任务记录的间隔
+	taskEventsPollInterval = time.Second
+	// 任务事件流无事件时发送心跳的间隔
+	taskEventsKeepAliveInterval = 15 * time.Second
+)
+
 // ListTasks ...
 //
 //	@Summary	获取任务列表
 //	@Tags		async-task
+//	@Param		name	query		string	false	"任务名称"
+//	@Param		status	query		string	false	"任务状态"	Enums(pending, running, succeeded, failed, cancelled, timeout)
+//	@Param		page	query		int		false	"页码"
+//	@Param		limit	query		int		false	"每页数量"
+//	@Param		cursor	query		string	false	"游标（首页传空值，之后传响应中的 next / prev），携带时使用游标分页，响应为 ginx.CursorPaginatedResp"
 //	@Success	200	{object}	ginx.Response{data=ginx.PaginatedResp{results=[]serializer.TaskListResponse}}
 //	@Router		/api/tasks [get]
 func ListTasks(c *gin.Context) {
-	tx := database.Client(c.Request.Context()).Order("created_at desc").Model(&model.Task{})
+	var req serializer.TaskListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		ginx.SetErrResp(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	tx := database.Client(c.Request.Context()).Model(&model.Task{})
+	if req.Name != "" {
+		tx = tx.Where("name = ?", req.Name)
+	}
+	if req.Status != "" {
+		tx = tx.Where("status = ?", req.Status)
+	}
+
+	// 任务记录较多，支持游标分页（无需 COUNT 及 OFFSET 扫描）
+	if ginx.UseCursorPagination(c) {
+		paginator, err := ginx.NewCursorPaginator(c, taskSortKeys)
+		if err != nil {
+			statusCode := lo.Ternary(
+				errors.Is(err, ginx.ErrCursorSecretNotConfigured), http.StatusInternalServerError, http.StatusBadRequest,
+			)
+			ginx.SetErrResp(c, statusCode, err.Error())
+			return
+		}
+		var tasks []model.Task
+		if err = tx.Scopes(paginator.Scope).Find(&tasks).Error; err != nil {
+			ginx.SetErrResp(c, http.StatusInternalServerError, err.Error())
+			return
+		}
+		tasks, next, prev := ginx.CursorPaginate(paginator, tasks, func(task model.Task) []any {
+			return []any{task.CreatedAt, task.ID}
+		})
+		ginx.SetResp(c, http.StatusOK, ginx.NewCursorPaginatedRespData(toTaskListResponse(tasks), next, prev))
+		return
+	}
 
 	// 总条目数量
 	var total int64
 	if err := tx.Count(&total).Error; err != nil {
 		ginx.SetErrResp(c, http.StatusInternalServerError, err.Error())
+		return
 	}
 
 	var executedTasks []model.Task
-	if err := tx.Offset(ginx.GetOffset(c)).Limit(ginx.GetLimit(c)).Find(&executedTasks).Error; err != nil {
+	err := tx.Order("created_at desc").Offset(ginx.GetOffset(c)).Limit(ginx.GetLimit(c)).Find(&executedTasks).Error
+	if err != nil {
 		ginx.SetErrResp(c, http.StatusInternalServerError, err.Error())
 		return
 	}
+	ginx.SetResp(c, http.StatusOK, ginx.NewPaginatedRespData(total, toTaskListResponse(executedTasks)))
+}
 
+// 任务列表的游标分页排序键（按创建时间倒序）
+var taskSortKeys = []ginx.SortKey{
+	{Column: "created_at", Type: ginx.FieldTypeTime, Desc: true},
+	{Column: "id", Type: ginx.FieldTypeInt, Desc: true},
+}
+
+// 将任务转换为列表响应
+func toTaskListResponse(tasks []model.Task) []serializer.TaskListResponse {
 	respData := []serializer.TaskListResponse{}
-	for _, task := range executedTasks {
+	for _, task := range tasks {
 		respData = append(respData, serializer.TaskListResponse{
 			ID:        task.ID,
+			Kind:      string(task.Kind),
+			ParentID:  task.ParentID,
 			Name:      task.Name,
 			Args:      string(task.Args),
 			Result:    string(task.Result),
+			Status:    string(task.Status),
 			Creator:   task.Creator,
 			StartedAt: lo.Ternary(task.StartedAt.IsZero(), "", task.StartedAt.Format(time.RFC3339)),
 			Duration:  task.Duration.Seconds(),
+
+			Progress:        task.Progress,
+			ProgressMessage: task.ProgressMessage,
 		})
 	}
-	ginx.SetResp(c, http.StatusOK, ginx.NewPaginatedRespData(total, respData))
+	return respData
 }
 
 // CreateTask ...
@@ -74,7 +144,7 @@ func ListTasks(c *gin.Context) {
 //	@Summary	创建异步任务
 //	@Tags		async-task
 //	@Param		body	body		serializer.TaskCreateRequest	true	"异步任务配置"
-//	@Success	201		{object}	ginx.Response{data=nil}
+//	@Success	201		{object}	ginx.Response{data=serializer.TaskCreateResponse}
 //	@Router		/api/tasks [post]
 func CreateTask(c *gin.Context) {
 	var req serializer.TaskCreateRequest
@@ -88,7 +158,241 @@ func CreateTask(c *gin.Context) {
 		return
 	}
 
-	// 异步任务执行，不使用 c.Request.Context() 以避免提前 cancel
-	async.ApplyTask(context.Background(), req.Name, req.Args)
-	ginx.SetResp(c, http.StatusCreated, nil)
+	// 异步任务执行，不使用 c.Request.Context() 以避免提前 cancel（保留 RequestID 以便串联日志）
+	ctx := context.WithoutCancel(c.Request.Context())
+	opts := []async.TaskOption{async.WithCreator(ginx.GetUserID(c))}
+	if req.Timeout > 0 {
+		opts = append(opts, async.WithTimeout(time.Duration(req.Timeout)*time.Second))
+	}
+	if req.Queue != "" {
+		opts = append(opts, async.WithQueue(req.Queue))
+	}
+	if req.Priority != 0 {
+		opts = append(opts, async.WithPriority(req.Priority))
+	}
+	if req.DedupKey != "" {
+		opts = append(opts, async.WithDedupKey(req.DedupKey, time.Duration(req.DedupTTL)*time.Second))
+	}
+	taskID, err := async.ApplyTask(ctx, req.Name, req.Args, opts...)
+	if err != nil {
+		status, msg := applyTaskErrResp(c, taskID, err)
+		ginx.SetErrResp(c, status, msg)
+		return
+	}
+	ginx.SetResp(c, http.StatusCreated, serializer.TaskCreateResponse{ID: taskID})
+}
+
+// 下发任务失败时的响应状态码 & 错误信息（taskID 为 ApplyTask 返回的任务 ID）
+func applyTaskErrResp(c *gin.Context, taskID int64, err error) (int, string) {
+	switch {
+	case errors.Is(err, async.ErrQueueNotFound):
+		return http.StatusBadRequest, err.Error()
+	case errors.Is(err, async.ErrQueueFull):
+		// 任务队列已满（背压），提示稍后重试
+		return http.StatusTooManyRequests, i18n.T(c.Request.Context(), "Task queue is full, please try again later")
+	case errors.Is(err, async.ErrTaskLocked):
+		// 任务声明了执行期间唯一，已有相同参数的任务未结束
+		return http.StatusConflict, fmt.Sprintf(
+			i18n.T(c.Request.Context(), "Task with the same args is already pending or running (ID: %d)"), taskID,
+		)
+	default:
+		return http.StatusInternalServerError, err.Error()
+	}
+}
+
+// RetrieveTask ...
+//
+//	@Summary	获取单个任务
+//	@Tags		async-task
+//	@Param		id	path		int	true	"任务 ID"
+//	@Success	200	{object}	ginx.Response{data=serializer.TaskRetrieveResponse}
+//	@Router		/api/tasks/{id} [get]
+func RetrieveTask(c *gin.Context) {
+	var task model.Task
+
+	tx := database.Client(c.Request.Context()).Where("id = ?", c.Param("id")).First(&task)
+	if tx.Error != nil {
+		ginx.SetErrResp(c, http.StatusNotFound, tx.Error.Error())
+		return
+	}
+
+	var attempts []model.TaskAttempt
+	tx = database.Client(c.Request.Context()).Where("task_id = ?", task.ID).Order("attempt").Find(&attempts)
+	if tx.Error != nil {
+		ginx.SetErrResp(c, http.StatusInternalServerError, tx.Error.Error())
+		return
+	}
+
+	attemptHistory := []serializer.TaskAttemptResponse{}
+	for _, attempt := range attempts {
+		attemptHistory = append(attemptHistory, serializer.TaskAttemptResponse{
+			Attempt:    attempt.Attempt,
+			Status:     string(attempt.Status),
+			Error:      attempt.Error,
+			StartedAt:  attempt.StartedAt.Format(time.RFC3339),
+			FinishedAt: lo.Ternary(attempt.FinishedAt.IsZero(), "", attempt.FinishedAt.Format(time.RFC3339)),
+			Duration:   attempt.Duration.Seconds(),
+		})
+	}
+
+	// 工作流节点：加载整个工作流的任务记录，构建以当前任务为根的子任务树
+	children := []serializer.TaskNodeResponse{}
+	if task.Kind != model.TaskKindTask && task.RootID != 0 {
+		var workflowTasks []model.Task
+		tx = database.Client(c.Request.Context()).Where("root_id = ?", task.RootID).Order("position").Find(&workflowTasks)
+		if tx.Error != nil {
+			ginx.SetErrResp(c, http.StatusInternalServerError, tx.Error.Error())
+			return
+		}
+		children = buildTaskTree(lo.GroupBy(workflowTasks, func(t model.Task) int64 { return t.ParentID }), task.ID)
+	}
+
+	ginx.SetResp(c, http.StatusOK, serializer.TaskRetrieveResponse{
+		ID:         task.ID,
+		Kind:       string(task.Kind),
+		ParentID:   task.ParentID,
+		Name:       task.Name,
+		Args:       string(task.Args),
+		Result:     string(task.Result),
+		Status:     string(task.Status),
+		Error:      task.Error,
+		Attempts:   task.Attempts,
+		Creator:    task.Creator,
+		CreatedAt:  task.CreatedAt.Format(time.RFC3339),
+		StartedAt:  lo.Ternary(task.StartedAt.IsZero(), "", task.StartedAt.Format(time.RFC3339)),
+		FinishedAt: lo.Ternary(task.FinishedAt.IsZero(), "", task.FinishedAt.Format(time.RFC3339)),
+		Duration:   task.Duration.Seconds(),
+
+		Progress:        task.Progress,
+		ProgressMessage: task.ProgressMessage,
+		ProgressData:    string(task.ProgressData),
+
+		AttemptHistory: attemptHistory,
+		Children:       children,
+	})
+}
+
+// 构建任务树：tasksByParent 为父任务 ID -> 子任务列表（按位置排序）
+func buildTaskTree(tasksByParent map[int64][]model.Task, parentID int64) []serializer.TaskNodeResponse {
+	nodes := []serializer.TaskNodeResponse{}
+	for _, task := range tasksByParent[parentID] {
+		nodes = append(nodes, serializer.TaskNodeResponse{
+			ID:         task.ID,
+			Kind:       string(task.Kind),
+			Name:       task.Name,
+			Args:       string(task.Args),
+			Result:     string(task.Result),
+			Status:     string(task.Status),
+			Error:      task.Error,
+			StartedAt:  lo.Ternary(task.StartedAt.IsZero(), "", task.StartedAt.Format(time.RFC3339)),
+			FinishedAt: lo.Ternary(task.FinishedAt.IsZero(), "", task.FinishedAt.Format(time.RFC3339)),
+			Children:   buildTaskTree(tasksByParent, task.ID),
+		})
+	}
+	return nodes
+}
+
+// TaskEvents ...
+//
+//	@Summary	订阅任务执行进度 & 状态变更（Server-Sent Events）
+//	@Description	事件类型：progress（data 为 serializer.TaskProgressEvent），status（data 为 serializer.TaskStatusEvent）；
+//	@Description	连接建立后会先推送当前的状态 & 进度，任务结束后事件流随之结束
+//	@Tags		async-task
+//	@Produce	text/event-stream
+//	@Param		id	path		int	true	"任务 ID"
+//	@Success	200	{object}	serializer.TaskProgressEvent
+//	@Router		/api/tasks/{id}/events [get]
+func TaskEvents(c *gin.Context) {
+	ctx := c.Request.Context()
+	var task model.Task
+	if err := database.Client(ctx).Where("id = ?", c.Param("id")).First(&task).Error; err != nil {
+		ginx.SetErrResp(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	// 禁用反向代理（如 nginx）的缓冲，确保事件及时推送
+	c.Header("X-Accel-Buffering", "no")
+
+	// 任务进度由执行任务的进程（可能是其他 worker）写入 DB，这里轮询任务记录，有变更时推送
+	var sent *model.Task
+	ticker := time.NewTicker(taskEventsPollInterval)
+	defer ticker.Stop()
+	keepAliveAt := time.Now().Add(taskEventsKeepAliveInterval)
+	c.Stream(func(w io.Writer) bool {
+		if sent != nil {
+			select {
+			case <-ctx.Done():
+				return false
+			case <-ticker.C:
+			}
+			var latest model.Task
+			if err := database.Client(ctx).Where("id = ?", task.ID).First(&latest).Error; err != nil {
+				c.SSEvent("error", err.Error())
+				return false
+			}
+			task = latest
+		}
+
+		if sent == nil || task.Status != sent.Status {
+			c.SSEvent("status", serializer.TaskStatusEvent{
+				Status:     string(task.Status),
+				Result:     rawJSONOrNull(task.Result),
+				Error:      task.Error,
+				StartedAt:  lo.Ternary(task.StartedAt.IsZero(), "", task.StartedAt.Format(time.RFC3339)),
+				FinishedAt: lo.Ternary(task.FinishedAt.IsZero(), "", task.FinishedAt.Format(time.RFC3339)),
+			})
+		}
+		if sent == nil || task.Progress != sent.Progress || task.ProgressMessage != sent.ProgressMessage ||
+			!bytes.Equal(task.ProgressData, sent.ProgressData) {
+			c.SSEvent("progress", serializer.TaskProgressEvent{
+				Progress:        task.Progress,
+				ProgressMessage: task.ProgressMessage,
+				ProgressData:    rawJSONOrNull(task.ProgressData),
+			})
+			keepAliveAt = time.Now().Add(taskEventsKeepAliveInterval)
+		} else if time.Now().After(keepAliveAt) {
+			// 定期发送注释行，避免长时间无事件时连接被代理断开
+			_, _ = io.WriteString(w, ": keep-alive\n\n")
+			keepAliveAt = time.Now().Add(taskEventsKeepAliveInterval)
+		}
+		sent = lo.ToPtr(task)
+		return !task.Status.IsFinished()
+	})
+}
+
+// 空的 JSON 字段（如任务尚未有结果）序列化为 null
+func rawJSONOrNull(data []byte) json.RawMessage {
+	if len(data) == 0 {
+		return json.RawMessage("null")
+	}
+	return data
+}
+
+// CancelTask ...
+//
+//	@Summary	取消任务（等待执行 / 执行中）
+//	@Tags		async-task
+//	@Param		id	path	int	true	"任务 ID"
+//	@Success	204	"No Content"
+//	@Router		/api/tasks/{id}/cancel [post]
+func CancelTask(c *gin.Context) {
+	var task model.Task
+	ctx := c.Request.Context()
+	tx := database.Client(ctx).Where("id = ?", c.Param("id")).First(&task)
+	if tx.Error != nil {
+		ginx.SetErrResp(c, http.StatusNotFound, tx.Error.Error())
+		return
+	}
+
+	if err := async.CancelTask(ctx, task.ID, ginx.GetUserID(c)); err != nil {
+		if errors.Is(err, async.ErrInvalidTransition) {
+			ginx.SetErrResp(c, http.StatusBadRequest, i18n.T(ctx, "Task already finished"))
+			return
+		}
+		ginx.SetErrResp(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	ginx.SetResp(c, http.StatusNoContent, nil)
 }
@@ -31,6 +31,9 @@ func Register(rg *gin.RouterGroup) {
 	taskRouter := rg.Group("/tasks")
 	taskRouter.GET("", handler.ListTasks)
 	taskRouter.POST("", handler.CreateTask)
+	taskRouter.GET("/:id", handler.RetrieveTask)
+	taskRouter.GET("/:id/events", handler.TaskEvents)
+	taskRouter.POST("/:id/cancel", handler.CancelTask)
 
 	// periodic task
 	periodicTaskRouter := rg.Group("/periodic-tasks")
@@ -38,4 +41,16 @@ func Register(rg *gin.RouterGroup) {
 	periodicTaskRouter.POST("", handler.CreatePeriodicTask)
 	periodicTaskRouter.DELETE("/:id", handler.DeletePeriodicTask)
 	periodicTaskRouter.PUT("/:id/enabled", handler.TogglePeriodicTaskEnabled)
+	periodicTaskRouter.GET("/:id/runs", handler.ListPeriodicTaskRuns)
+
+	// dead letter
+	deadLetterRouter := rg.Group("/dead-letters")
+	deadLetterRouter.GET("", handler.ListDeadLetters)
+	deadLetterRouter.POST("/replay", handler.BulkReplayDeadLetters)
+	deadLetterRouter.GET("/:id", handler.RetrieveDeadLetter)
+	deadLetterRouter.POST("/:id/replay", handler.ReplayDeadLetter)
+
+	// cron
+	cronRouter := rg.Group("/cron")
+	cronRouter.GET("/preview", handler.PreviewCron)
 }
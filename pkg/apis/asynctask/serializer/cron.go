@@ -0,0 +1,65 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - Go 开发框架 (BlueKing - Go Framework) available.
+ * Copyright (C) 2017 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ *	https://opensource.org/licenses/MIT
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package serializer
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+
+	"github.com/TencentBlueKing/blueapps-go/pkg/i18n"
+)
+
+// CronPreviewRequest Preview Cron API 请求结构
+type CronPreviewRequest struct {
+	// cron 表达式，支持可选的秒级字段（6 位）及 @daily 等描述符
+	Expr string `form:"expr" binding:"required"`
+	// IANA 时区（如 Asia/Shanghai），为空表示使用 scheduler 所在机器时区
+	TZ string `form:"tz"`
+	// 预览的触发次数，为 0 则默认 5 次
+	Count int `form:"count" binding:"omitempty,gte=0,lte=50"`
+}
+
+// Validate ...
+func (r *CronPreviewRequest) Validate(c *gin.Context) error {
+	if r.TZ != "" {
+		if _, err := time.LoadLocation(r.TZ); err != nil {
+			return errors.Wrap(err, i18n.T(c.Request.Context(), "timezone invalid"))
+		}
+	}
+	if r.Count == 0 {
+		r.Count = 5
+	}
+	return nil
+}
+
+// CronPreviewResponse Preview Cron API 返回结构
+type CronPreviewResponse struct {
+	// 自然语言描述（按用户语言国际化）
+	Description string `json:"description"`
+	// 接下来的触发时间（RFC3339 格式，位于表达式的时区）
+	NextRunAt []string `json:"nextRunAt"`
+	// 最小触发间隔（单位：s），不会重复触发时为 0
+	MinInterval float64 `json:"minInterval"`
+	// 配置的最小触发间隔（单位：s）
+	MinAllowedInterval int `json:"minAllowedInterval"`
+	// 是否触发过于频繁（最小触发间隔小于配置值）
+	TooFrequent bool `json:"tooFrequent"`
+}
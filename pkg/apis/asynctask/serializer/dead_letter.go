@@ -0,0 +1,117 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - Go 开发框架 (BlueKing - Go Framework) available.
+ * Copyright (C) 2017 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ *	https://opensource.org/licenses/MIT
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package serializer
+
+import (
+	"encoding/json"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+
+	"github.com/TencentBlueKing/blueapps-go/pkg/async"
+	"github.com/TencentBlueKing/blueapps-go/pkg/i18n"
+)
+
+// DeadLetterListRequest List DeadLetter API 输入结构
+type DeadLetterListRequest struct {
+	Name string `form:"name" binding:"omitempty"`
+	// 是否已重新下发，为空表示不过滤
+	Replayed string `form:"replayed" binding:"omitempty,oneof=true false"`
+}
+
+// DeadLetterListResponse List DeadLetter API 返回结构
+type DeadLetterListResponse struct {
+	ID       int64  `json:"id"`
+	TaskID   int64  `json:"taskID"`
+	Name     string `json:"name"`
+	Args     string `json:"args"`
+	Status   string `json:"status"`
+	Error    string `json:"error"`
+	Attempts int    `json:"attempts"`
+	// 是否因 panic 失败（记录了调用栈）
+	Panicked bool   `json:"panicked"`
+	FailedAt string `json:"failedAt"`
+	// 重新下发的次数，及最近一次下发的任务 ID & 时间
+	ReplayCount    int    `json:"replayCount"`
+	ReplayedTaskID int64  `json:"replayedTaskID"`
+	ReplayedAt     string `json:"replayedAt"`
+}
+
+// DeadLetterRetrieveResponse Retrieve DeadLetter API 返回结构
+type DeadLetterRetrieveResponse struct {
+	ID       int64  `json:"id"`
+	TaskID   int64  `json:"taskID"`
+	Name     string `json:"name"`
+	Args     string `json:"args"`
+	Queue    string `json:"queue"`
+	Status   string `json:"status"`
+	Error    string `json:"error"`
+	Stack    string `json:"stack"`
+	Attempts int    `json:"attempts"`
+	// 下发任务的请求 ID 及执行任务的 trace ID
+	RequestID string `json:"requestID"`
+	TraceID   string `json:"traceID"`
+	Creator   string `json:"creator"`
+	FailedAt  string `json:"failedAt"`
+	// 重新下发的次数，及最近一次下发的操作人、任务 ID & 时间
+	ReplayCount    int    `json:"replayCount"`
+	ReplayedBy     string `json:"replayedBy"`
+	ReplayedTaskID int64  `json:"replayedTaskID"`
+	ReplayedAt     string `json:"replayedAt"`
+	// 每次执行（包括重试）的记录
+	AttemptHistory []TaskAttemptResponse `json:"attemptHistory"`
+}
+
+// DeadLetterReplayRequest Replay DeadLetter API 请求结构
+type DeadLetterReplayRequest struct {
+	// 修正后的任务参数，为空（或 null）则使用原参数
+	Args json.RawMessage `json:"args" swaggertype:"object"`
+}
+
+// DeadLetterBulkReplayRequest Bulk Replay DeadLetter API 请求结构
+type DeadLetterBulkReplayRequest struct {
+	Items []DeadLetterReplayItem `json:"items" binding:"required,min=1,max=100,dive"`
+}
+
+// DeadLetterReplayItem 批量重新下发的死信
+type DeadLetterReplayItem struct {
+	ID int64 `json:"id" binding:"required"`
+	// 修正后的任务参数，为空（或 null）则使用原参数
+	Args json.RawMessage `json:"args" swaggertype:"object"`
+}
+
+// DeadLetterReplayResponse Replay DeadLetter API 返回结构
+type DeadLetterReplayResponse struct {
+	ID int64 `json:"id"`
+	// 重新下发的任务 ID，下发失败为 0
+	TaskID int64 `json:"taskID"`
+	// 下发失败的原因
+	Error string `json:"error"`
+}
+
+// ValidateReplayArgs 校验修正后的任务参数（为空或 null 表示使用原参数，无需校验）
+func ValidateReplayArgs(c *gin.Context, name string, args json.RawMessage) error {
+	if async.ReplayArgsOmitted(args) {
+		return nil
+	}
+	if err := async.ValidateArgs(name, args); err != nil {
+		return errors.Wrap(err, i18n.T(c.Request.Context(), "Task args invalid"))
+	}
+	return nil
+}
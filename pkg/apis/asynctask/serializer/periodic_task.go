@@ -20,29 +20,75 @@
 package serializer
 
 import (
+	"encoding/json"
+	"time"
+
 	"github.com/gin-gonic/gin"
 	"github.com/pkg/errors"
-	"github.com/robfig/cron/v3"
 
 	"github.com/TencentBlueKing/blueapps-go/pkg/async"
 	"github.com/TencentBlueKing/blueapps-go/pkg/i18n"
+	"github.com/TencentBlueKing/blueapps-go/pkg/model"
 )
 
 // PeriodicTaskListResponse List PeriodicTask API 返回结构
 type PeriodicTaskListResponse struct {
-	ID      int64  `json:"id"`
-	Cron    string `json:"cron"`
-	Name    string `json:"name"`
-	Args    string `json:"args"`
-	Enabled bool   `json:"enabled"`
-	Creator string `json:"creator"`
+	ID       int64  `json:"id"`
+	Cron     string `json:"cron"`
+	Timezone string `json:"timezone"`
+	StartAt  string `json:"startAt"`
+	EndAt    string `json:"endAt"`
+	// 一次性任务的执行 & 完成时间
+	ETA         string `json:"eta"`
+	CompletedAt string `json:"completedAt"`
+	// 错过触发的处理策略
+	MisfirePolicy string `json:"misfirePolicy"`
+	MisfireLimit  int    `json:"misfireLimit"`
+	Name          string `json:"name"`
+	Args          string `json:"args"`
+	Enabled       bool   `json:"enabled"`
+	Creator       string `json:"creator"`
+	// 声明式定义（periodic_tasks.yaml）中的 key，不为空时只能通过修改定义文件变更
+	ManagedKey string `json:"managedKey"`
+	// 最近一次触发时间 & 结果
+	LastRunAt  string `json:"lastRunAt"`
+	LastStatus string `json:"lastStatus"`
+	// 下次触发时间（未启用 / 不再触发则为空）
+	NextRunAt string `json:"nextRunAt"`
+}
+
+// PeriodicTaskRunListResponse List PeriodicTaskRuns API 返回结构
+type PeriodicTaskRunListResponse struct {
+	ID          int64  `json:"id"`
+	ScheduledAt string `json:"scheduledAt"`
+	StartedAt   string `json:"startedAt"`
+	TaskID      int64  `json:"taskID"`
+	// 触发结果：已下发的为关联任务的状态，否则为 firing / skipped / failed
+	Status string `json:"status"`
+	Error  string `json:"error"`
+	// 是否为 scheduler 启动时对错过触发的补跑
+	CatchUp bool `json:"catchUp"`
+	// 是否为手动触发
+	Manual bool `json:"manual"`
 }
 
 // PeriodicTaskCreateRequest Create PeriodicTask API 请求结构
 type PeriodicTaskCreateRequest struct {
 	Name string `json:"name"`
+	// cron 表达式，支持可选的秒级字段（6 位），与 eta 二选一
 	Cron string `json:"cron"`
-	Args []any  `json:"args"`
+	// IANA 时区（如 Asia/Shanghai），为空表示使用 scheduler 所在机器时区
+	Timezone string `json:"timezone"`
+	// 生效时间窗口（RFC3339 格式），为空表示不限制
+	StartAt time.Time `json:"startAt"`
+	EndAt   time.Time `json:"endAt"`
+	// 一次性任务的执行时间（RFC3339 格式），与 cron 二选一
+	ETA time.Time `json:"eta"`
+	// scheduler 停机期间错过触发的处理策略：skip（默认）/ run_once / run_all，及 run_all 最多补跑次数（默认 10）
+	MisfirePolicy string `json:"misfirePolicy" binding:"omitempty,oneof=skip run_once run_all"`
+	MisfireLimit  int    `json:"misfireLimit" binding:"omitempty,gte=1,lte=100"`
+	// 任务参数，需符合任务声明的参数类型
+	Args json.RawMessage `json:"args" swaggertype:"object"`
 }
 
 // Validate ...
@@ -52,14 +98,36 @@ func (r *PeriodicTaskCreateRequest) Validate(c *gin.Context) error {
 	if r.Name == "" {
 		return errors.New(i18n.T(ctx, "Task name required"))
 	}
-	if _, ok := async.RegisteredTasks[r.Name]; !ok {
+	if !async.IsRegistered(r.Name) {
 		return errors.Errorf(i18n.T(ctx, "Task name %s invalid"), r.Name)
 	}
-	// 检查 cron 表达式是否合法
+	// 检查任务参数是否符合任务声明的参数类型
+	if err := async.ValidateArgs(r.Name, r.Args); err != nil {
+		return errors.Wrap(err, i18n.T(ctx, "Task args invalid"))
+	}
+	// 一次性任务：仅检查执行时间
+	if !r.ETA.IsZero() {
+		if r.Cron != "" {
+			return errors.New(i18n.T(ctx, "cron and eta cannot be set at the same time"))
+		}
+		if !r.ETA.After(time.Now()) {
+			return errors.New(i18n.T(ctx, "eta must be in the future"))
+		}
+		return nil
+	}
+	// 检查 cron 表达式 & 时区 & 生效时间窗口是否合法
 	if r.Cron == "" {
 		return errors.New(i18n.T(ctx, "cron required"))
 	}
-	if _, err := cron.ParseStandard(r.Cron); err != nil {
+	if r.Timezone != "" {
+		if _, err := time.LoadLocation(r.Timezone); err != nil {
+			return errors.Wrap(err, i18n.T(ctx, "timezone invalid"))
+		}
+	}
+	if !r.StartAt.IsZero() && !r.EndAt.IsZero() && !r.EndAt.After(r.StartAt) {
+		return errors.New(i18n.T(ctx, "endAt must be after startAt"))
+	}
+	if _, err := async.ParseSchedule(&model.PeriodicTask{Cron: r.Cron, Timezone: r.Timezone}); err != nil {
 		return errors.Wrap(err, i18n.T(ctx, "cron invalid"))
 	}
 	return nil
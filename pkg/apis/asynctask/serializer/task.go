@@ -19,6 +19,8 @@
 package serializer
 
 import (
+	"encoding/json"
+
 	"github.com/gin-gonic/gin"
 	"github.com/pkg/errors"
 
@@ -26,30 +28,129 @@ import (
 	"github.com/TencentBlueKing/blueapps-go/pkg/i18n"
 )
 
+// TaskListRequest List Task API 输入结构
+type TaskListRequest struct {
+	Name   string `form:"name" binding:"omitempty"`
+	Status string `form:"status" binding:"omitempty,oneof=pending running succeeded failed cancelled timeout"`
+}
+
 // TaskListResponse List Task API 返回结构
 type TaskListResponse struct {
 	ID        int64   `json:"id"`
+	Kind      string  `json:"kind"`
+	ParentID  int64   `json:"parentID"`
 	Name      string  `json:"name"`
 	Args      string  `json:"args"`
 	Result    string  `json:"result"`
+	Status    string  `json:"status"`
 	Creator   string  `json:"creator"`
 	StartedAt string  `json:"startedAt"`
 	Duration  float64 `json:"duration"`
+	// 执行进度（百分比）及进度说明
+	Progress        float64 `json:"progress"`
+	ProgressMessage string  `json:"progressMessage"`
+}
+
+// TaskRetrieveResponse Retrieve Task API 返回结构
+type TaskRetrieveResponse struct {
+	ID int64 `json:"id"`
+	// 任务类型：task（单个任务）/ chain / group / chord（工作流节点）
+	Kind       string  `json:"kind"`
+	ParentID   int64   `json:"parentID"`
+	Name       string  `json:"name"`
+	Args       string  `json:"args"`
+	Result     string  `json:"result"`
+	Status     string  `json:"status"`
+	Error      string  `json:"error"`
+	Attempts   int     `json:"attempts"`
+	Creator    string  `json:"creator"`
+	CreatedAt  string  `json:"createdAt"`
+	StartedAt  string  `json:"startedAt"`
+	FinishedAt string  `json:"finishedAt"`
+	Duration   float64 `json:"duration"`
+	// 执行进度（百分比），进度说明及阶段性数据（JSON）
+	Progress        float64 `json:"progress"`
+	ProgressMessage string  `json:"progressMessage"`
+	ProgressData    string  `json:"progressData"`
+	// 每次执行（包括重试）的记录
+	AttemptHistory []TaskAttemptResponse `json:"attemptHistory"`
+	// 工作流节点的子任务树
+	Children []TaskNodeResponse `json:"children"`
+}
+
+// TaskNodeResponse 工作流任务树节点
+type TaskNodeResponse struct {
+	ID         int64              `json:"id"`
+	Kind       string             `json:"kind"`
+	Name       string             `json:"name"`
+	Args       string             `json:"args"`
+	Result     string             `json:"result"`
+	Status     string             `json:"status"`
+	Error      string             `json:"error"`
+	StartedAt  string             `json:"startedAt"`
+	FinishedAt string             `json:"finishedAt"`
+	Children   []TaskNodeResponse `json:"children"`
+}
+
+// TaskProgressEvent 任务进度事件（SSE event: progress）
+type TaskProgressEvent struct {
+	Progress        float64         `json:"progress"`
+	ProgressMessage string          `json:"progressMessage"`
+	ProgressData    json.RawMessage `json:"progressData" swaggertype:"object"`
+}
+
+// TaskStatusEvent 任务状态变更事件（SSE event: status），任务结束后事件流随之结束
+type TaskStatusEvent struct {
+	Status     string          `json:"status"`
+	Result     json.RawMessage `json:"result" swaggertype:"object"`
+	Error      string          `json:"error"`
+	StartedAt  string          `json:"startedAt"`
+	FinishedAt string          `json:"finishedAt"`
+}
+
+// TaskAttemptResponse 任务单次执行记录
+type TaskAttemptResponse struct {
+	Attempt    int     `json:"attempt"`
+	Status     string  `json:"status"`
+	Error      string  `json:"error"`
+	StartedAt  string  `json:"startedAt"`
+	FinishedAt string  `json:"finishedAt"`
+	Duration   float64 `json:"duration"`
 }
 
 // TaskCreateRequest Create Task API 请求结构
 type TaskCreateRequest struct {
 	Name string `json:"name"`
-	Args []any  `json:"args"`
+	// 任务参数，需符合任务声明的参数类型
+	Args json.RawMessage `json:"args" swaggertype:"object"`
+	// 单次执行超时时间（单位：s），为 0 则使用任务默认的超时时间
+	Timeout int `json:"timeout" binding:"omitempty,gte=0"`
+	// 任务队列（需在配置中声明），为空则使用任务默认的队列
+	Queue string `json:"queue"`
+	// 优先级，同一队列中数值越大越优先执行
+	Priority int `json:"priority"`
+	// 去重 key，有效期内以相同 key 下发同名任务时，返回首次下发的任务 ID（如避免重复点击）
+	DedupKey string `json:"dedupKey" binding:"omitempty,max=128"`
+	// 去重有效期（单位：s），为 0 则默认 60s
+	DedupTTL int `json:"dedupTTL" binding:"omitempty,gte=0,lte=86400"`
 }
 
-// Validate ...`
+// Validate ...
 func (r *TaskCreateRequest) Validate(c *gin.Context) error {
+	ctx := c.Request.Context()
 	if r.Name == "" {
-		return errors.New(i18n.T(c.Request.Context(), "Task name required"))
+		return errors.New(i18n.T(ctx, "Task name required"))
+	}
+	if !async.IsRegistered(r.Name) {
+		return errors.Errorf(i18n.T(ctx, "Task name %s invalid"), r.Name)
 	}
-	if _, ok := async.RegisteredTasks[r.Name]; !ok {
-		return errors.Errorf("Task name %s invalid", r.Name)
+	if err := async.ValidateArgs(r.Name, r.Args); err != nil {
+		return errors.Wrap(err, i18n.T(ctx, "Task args invalid"))
 	}
 	return nil
 }
+
+// TaskCreateResponse Create Task API 返回结构
+type TaskCreateResponse struct {
+	ID int64 `json:"id"`
+}
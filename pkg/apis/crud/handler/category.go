@@ -24,48 +24,106 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	"github.com/samber/lo"
+	"gorm.io/gorm"
 
 	"github.com/TencentBlueKing/blueapps-go/pkg/apis/crud/serializer"
+	"github.com/TencentBlueKing/blueapps-go/pkg/crud"
+	"github.com/TencentBlueKing/blueapps-go/pkg/i18n"
 	"github.com/TencentBlueKing/blueapps-go/pkg/infras/database"
 	"github.com/TencentBlueKing/blueapps-go/pkg/model"
 	"github.com/TencentBlueKing/blueapps-go/pkg/utils/ginx"
 )
 
+// CategoryResource 分类资源
+var CategoryResource = &crud.Resource[
+	model.Category, serializer.CategoryCreateRequest, serializer.CategoryUpdateRequest, serializer.CategoryResponse,
+]{
+	NewModel: func(req *serializer.CategoryCreateRequest) *model.Category {
+		return &model.Category{Name: req.Name}
+	},
+	ApplyUpdate: func(category *model.Category, req *serializer.CategoryUpdateRequest) {
+		category.Name = req.Name
+	},
+	ToResponse: func(category *model.Category) serializer.CategoryResponse {
+		return serializer.CategoryResponse{
+			ID:        category.ID,
+			Name:      category.Name,
+			Creator:   category.Creator,
+			Updater:   category.Updater,
+			CreatedAt: category.CreatedAt.Format(time.RFC3339),
+			UpdatedAt: category.UpdatedAt.Format(time.RFC3339),
+			DeletedAt: lo.Ternary(category.IsDeleted(), category.DeletedAt.Time.Format(time.RFC3339), ""),
+			DeletedBy: category.DeletedBy,
+		}
+	},
+	Validate: validateCategory,
+	// 分类数量较少，列表不分页（用于展示及条目的分类下拉框）
+	Unpaginated: true,
+	CreateResponse: func(category *model.Category) any {
+		return serializer.CategoryCreateResponse{ID: category.ID}
+	},
+	UpdateNoContent: true,
+	// 删除分类时一并（软）删除其下的条目，恢复分类时一并恢复随之删除的条目（此前单独删除的条目仍在回收站中）
+	Dependents: []crud.Dependent{
+		{Model: &model.Entry{}, ForeignKey: "category_id", Policy: crud.DeleteCascade},
+	},
+	Query: &ginx.QuerySchema{
+		Fields: []ginx.QueryField{
+			{Name: "id", Column: "id", Type: ginx.FieldTypeInt, Lookups: ginx.ComparisonLookups, Sortable: true},
+			{
+				Name: "name", Column: "name",
+				Lookups: []ginx.Lookup{ginx.LookupExact, ginx.LookupContains}, Sortable: true,
+			},
+			{Name: "creator", Column: "creator", Lookups: []ginx.Lookup{ginx.LookupExact, ginx.LookupIn}},
+			{Name: "updater", Column: "updater", Lookups: []ginx.Lookup{ginx.LookupExact, ginx.LookupIn}},
+			{
+				Name: "createdAt", Column: "created_at", Type: ginx.FieldTypeTime,
+				Lookups: ginx.ComparisonLookups, Sortable: true,
+			},
+			{
+				Name: "updatedAt", Column: "updated_at", Type: ginx.FieldTypeTime,
+				Lookups: ginx.ComparisonLookups, Sortable: true,
+			},
+		},
+		SearchColumns:   []string{"name", "updater"},
+		DefaultOrdering: "id",
+	},
+}
+
+// 检查分类名称是否已被其他分类（包括回收站中的）使用
+func validateCategory(c *gin.Context, category *model.Category) error {
+	ctx := c.Request.Context()
+	var existing model.Category
+	err := database.Client(ctx).Unscoped().
+		Where("name = ? AND id <> ?", category.Name, category.ID).
+		First(&existing).Error
+	if err == nil && existing.IsDeleted() {
+		return errors.Errorf(i18n.T(ctx, "category name `%s` already used by a deleted category"), category.Name)
+	} else if err == nil {
+		return errors.Errorf(i18n.T(ctx, "category name `%s` already used"), category.Name)
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	return crud.NewError(http.StatusInternalServerError, err.Error())
+}
+
 // ListCategories ...
 //
 //	@Summary	获取分类列表
 //	@Tags		crud
-//	@Success	200	{object}	ginx.Response{data=[]serializer.CategoryListResponse}
+//	@Param		keyword		query		string	false	"关键字（名称 / 更新者）"
+//	@Param		name		query		string	false	"名称，支持 name__contains"
+//	@Param		updatedAt	query		string	false	"更新时间，支持 updatedAt__{gt,gte,lt,lte}"
+//	@Param		ordering	query		string	false	"排序字段（逗号分隔，- 表示降序），可选 id / name / createdAt / updatedAt"
+//	@Param		fields		query		string	false	"返回字段（逗号分隔），默认返回全部字段"
+//	@Param		trashed		query		bool	false	"为 true 时获取回收站（已删除）中的分类"
+//	@Success	200			{object}	ginx.Response{data=[]serializer.CategoryResponse}
 //	@Router		/api/categories [get]
 func ListCategories(c *gin.Context) {
-	var req serializer.CategoryListRequest
-	if err := c.ShouldBindQuery(&req); err != nil {
-		ginx.SetErrResp(c, http.StatusBadRequest, err.Error())
-		return
-	}
-
-	tx := database.Client(c.Request.Context()).Model(&model.Category{})
-	if req.Keyword != "" {
-		keyword := "%" + req.Keyword + "%"
-		tx = tx.Where("LOWER(name) LIKE ?", keyword).Or("LOWER(updater) LIKE ?", keyword)
-	}
-
-	var categories []model.Category
-	if err := tx.Find(&categories).Error; err != nil {
-		ginx.SetErrResp(c, http.StatusInternalServerError, err.Error())
-		return
-	}
-
-	respData := []serializer.CategoryListResponse{}
-	for _, category := range categories {
-		respData = append(respData, serializer.CategoryListResponse{
-			ID:        category.ID,
-			Name:      category.Name,
-			Updater:   category.Updater,
-			UpdatedAt: category.UpdatedAt.Format(time.RFC3339),
-		})
-	}
-	ginx.SetResp(c, http.StatusOK, respData)
+	CategoryResource.List(c)
 }
 
 // CreateCategory ...
@@ -73,33 +131,10 @@ func ListCategories(c *gin.Context) {
 //	@Summary	创建分类
 //	@Tags		crud
 //	@Param		body	body		serializer.CategoryCreateRequest	true	"创建分类请求体"
-//	@Success	201		{object}	ginx.Response{data=nil}
+//	@Success	201		{object}	ginx.Response{data=serializer.CategoryCreateResponse}
 //	@Router		/api/categories [post]
 func CreateCategory(c *gin.Context) {
-	var req serializer.CategoryCreateRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		ginx.SetErrResp(c, http.StatusBadRequest, err.Error())
-		return
-	}
-
-	if err := req.Validate(c); err != nil {
-		ginx.SetErrResp(c, http.StatusBadRequest, err.Error())
-		return
-	}
-
-	category := model.Category{
-		Name: req.Name,
-		BaseModel: model.BaseModel{
-			Creator: ginx.GetUserID(c),
-			Updater: ginx.GetUserID(c),
-		},
-	}
-	if err := database.Client(c.Request.Context()).Create(&category).Error; err != nil {
-		ginx.SetErrResp(c, http.StatusInternalServerError, err.Error())
-		return
-	}
-
-	ginx.SetResp(c, http.StatusCreated, serializer.CategoryCreateResponse{ID: category.ID})
+	CategoryResource.Create(c)
 }
 
 // RetrieveCategory ...
@@ -107,26 +142,10 @@ func CreateCategory(c *gin.Context) {
 //	@Summary	获取单个分类
 //	@Tags		crud
 //	@Param		id	path		int	true	"分类 ID"
-//	@Success	200	{object}	ginx.Response{data=serializer.CategoryRetrieveResponse}
+//	@Success	200	{object}	ginx.Response{data=serializer.CategoryResponse}
 //	@Router		/api/categories/{id} [get]
 func RetrieveCategory(c *gin.Context) {
-	var category model.Category
-
-	tx := database.Client(c.Request.Context()).Where("id = ?", c.Param("id")).First(&category)
-	if tx.Error != nil {
-		ginx.SetErrResp(c, http.StatusNotFound, tx.Error.Error())
-		return
-	}
-
-	respData := serializer.CategoryRetrieveResponse{
-		ID:        category.ID,
-		Name:      category.Name,
-		Creator:   category.Creator,
-		Updater:   category.Updater,
-		CreatedAt: category.CreatedAt.Format(time.RFC3339),
-		UpdatedAt: category.UpdatedAt.Format(time.RFC3339),
-	}
-	ginx.SetResp(c, http.StatusOK, respData)
+	CategoryResource.Retrieve(c)
 }
 
 // UpdateCategory ...
@@ -138,49 +157,27 @@ func RetrieveCategory(c *gin.Context) {
 //	@Success	204		"No Content"
 //	@Router		/api/categories/{id} [put]
 func UpdateCategory(c *gin.Context) {
-	var req serializer.CategoryUpdateRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		ginx.SetErrResp(c, http.StatusBadRequest, err.Error())
-		return
-	}
-
-	if err := req.Validate(c); err != nil {
-		ginx.SetErrResp(c, http.StatusBadRequest, err.Error())
-		return
-	}
-
-	var category model.Category
-	ctx := c.Request.Context()
-	tx := database.Client(ctx).Where("id = ?", c.Param("id")).First(&category)
-	if tx.Error != nil {
-		ginx.SetErrResp(c, http.StatusNotFound, tx.Error.Error())
-		return
-	}
-
-	// 更新 DB 模型字段
-	category.Name = req.Name
-	category.Updater = ginx.GetUserID(c)
-	tx = database.Client(ctx).Save(&category)
-	if tx.Error != nil {
-		ginx.SetErrResp(c, http.StatusInternalServerError, tx.Error.Error())
-		return
-	}
-
-	ginx.SetResp(c, http.StatusNoContent, nil)
+	CategoryResource.Update(c)
 }
 
 // DestroyCategory ...
 //
-//	@Summary	删除分类
+//	@Summary	删除分类（连同其下的条目移入回收站）
 //	@Tags		crud
 //	@Param		id	path	int	true	"分类 ID"
 //	@Success	204	"No Content"
 //	@Router		/api/categories/{id} [delete]
 func DestroyCategory(c *gin.Context) {
-	tx := database.Client(c.Request.Context()).Where("id = ?", c.Param("id")).Delete(&model.Category{})
-	if tx.Error != nil {
-		ginx.SetErrResp(c, http.StatusInternalServerError, tx.Error.Error())
-		return
-	}
-	ginx.SetResp(c, http.StatusNoContent, nil)
+	CategoryResource.Destroy(c)
+}
+
+// RestoreCategory ...
+//
+//	@Summary	恢复回收站中的分类（连同随之删除的条目）
+//	@Tags		crud
+//	@Param		id	path		int	true	"分类 ID"
+//	@Success	200	{object}	ginx.Response{data=serializer.CategoryResponse}
+//	@Router		/api/categories/{id}/restore [post]
+func RestoreCategory(c *gin.Context) {
+	CategoryResource.Restore(c)
 }
@@ -25,66 +25,138 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/pkg/errors"
+	"github.com/samber/lo"
 	"gorm.io/gorm"
 
 	"github.com/TencentBlueKing/blueapps-go/pkg/apis/crud/serializer"
+	"github.com/TencentBlueKing/blueapps-go/pkg/crud"
 	"github.com/TencentBlueKing/blueapps-go/pkg/i18n"
 	"github.com/TencentBlueKing/blueapps-go/pkg/infras/database"
 	"github.com/TencentBlueKing/blueapps-go/pkg/model"
 	"github.com/TencentBlueKing/blueapps-go/pkg/utils/ginx"
 )
 
-// ListEntries ...
-//
-//	@Summary	获取条目列表
-//	@Tags		crud
-//	@Success	200	{object}	ginx.Response{data=ginx.PaginatedResp{results=[]serializer.EntryListResponse}}
-//	@Router		/api/entries [get]
-func ListEntries(c *gin.Context) {
-	var req serializer.EntryListRequest
-	if err := c.ShouldBindQuery(&req); err != nil {
-		ginx.SetErrResp(c, http.StatusBadRequest, err.Error())
-		return
-	}
+// EntryResource 条目资源
+var EntryResource = &crud.Resource[
+	model.Entry, serializer.EntryCreateRequest, serializer.EntryUpdateRequest, serializer.EntryResponse,
+]{
+	NewModel: func(req *serializer.EntryCreateRequest) *model.Entry {
+		return &model.Entry{CategoryID: req.CategoryID, Name: req.Name, Desc: req.Desc, Price: req.Price}
+	},
+	ApplyUpdate: func(entry *model.Entry, req *serializer.EntryUpdateRequest) {
+		entry.CategoryID = req.CategoryID
+		entry.Name = req.Name
+		entry.Desc = req.Desc
+		entry.Price = req.Price
+	},
+	ToResponse: func(entry *model.Entry) serializer.EntryResponse {
+		return serializer.EntryResponse{
+			// 分类属性
+			CategoryID:   entry.CategoryID,
+			CategoryName: entry.Category.Name,
+			// 条目属性
+			ID:        entry.ID,
+			Name:      entry.Name,
+			Desc:      entry.Desc,
+			Price:     entry.Price,
+			Creator:   entry.Creator,
+			Updater:   entry.Updater,
+			CreatedAt: entry.CreatedAt.Format(time.RFC3339),
+			UpdatedAt: entry.UpdatedAt.Format(time.RFC3339),
+			DeletedAt: lo.Ternary(entry.IsDeleted(), entry.DeletedAt.Time.Format(time.RFC3339), ""),
+			DeletedBy: entry.DeletedBy,
+		}
+	},
+	Validate: validateEntry,
+	// 所属分类在回收站中时，需先恢复分类
+	Parents: []crud.Parent{
+		{Model: &model.Category{}, ForeignKey: "category_id"},
+	},
+	CreateResponse: func(entry *model.Entry) any {
+		return serializer.EntryCreateResponse{ID: entry.ID}
+	},
+	UpdateNoContent: true,
+	Scope: func(_ *gin.Context, tx *gorm.DB) *gorm.DB {
+		return tx.Preload("Category")
+	},
+	// 条目数量较多，支持游标分页
+	CursorPagination: true,
+	Query: &ginx.QuerySchema{
+		Fields: []ginx.QueryField{
+			{
+				Name: "categoryID", Column: "category_id", Type: ginx.FieldTypeInt,
+				Lookups: []ginx.Lookup{ginx.LookupExact, ginx.LookupIn},
+			},
+			{Name: "categoryName"},
+			{Name: "id", Column: "id", Type: ginx.FieldTypeInt, Lookups: ginx.ComparisonLookups, Sortable: true},
+			{
+				Name: "name", Column: "name",
+				Lookups: []ginx.Lookup{ginx.LookupExact, ginx.LookupContains}, Sortable: true,
+			},
+			{Name: "desc", Column: "desc", Lookups: []ginx.Lookup{ginx.LookupContains}},
+			{
+				Name: "price", Column: "price", Type: ginx.FieldTypeFloat,
+				Lookups: ginx.ComparisonLookups, Sortable: true,
+			},
+			{Name: "creator", Column: "creator", Lookups: []ginx.Lookup{ginx.LookupExact, ginx.LookupIn}},
+			{Name: "updater", Column: "updater", Lookups: []ginx.Lookup{ginx.LookupExact, ginx.LookupIn}},
+			{
+				Name: "createdAt", Column: "created_at", Type: ginx.FieldTypeTime,
+				Lookups: ginx.ComparisonLookups, Sortable: true,
+			},
+			{
+				Name: "updatedAt", Column: "updated_at", Type: ginx.FieldTypeTime,
+				Lookups: ginx.ComparisonLookups, Sortable: true,
+			},
+		},
+		SearchColumns:   []string{"name", "desc", "updater"},
+		DefaultOrdering: "id",
+	},
+}
 
-	tx := database.Client(c.Request.Context()).Model(&model.Entry{}).Preload("Category")
-	if req.CategoryID != 0 {
-		tx = tx.Where("category_id = ?", req.CategoryID)
-	}
-	if req.Keyword != "" {
-		keyword := "%" + req.Keyword + "%"
-		tx = tx.Where("LOWER(name) LIKE ?", keyword).
-			Or("LOWER(`desc`) LIKE ?", keyword).
-			Or("LOWER(updater) LIKE ?", keyword)
+// 检查条目所属分类是否存在，条目名称是否已被其他条目使用
+func validateEntry(c *gin.Context, entry *model.Entry) error {
+	ctx := c.Request.Context()
+	err := database.Client(ctx).Where("id = ?", entry.CategoryID).First(&model.Category{}).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return crud.NewError(http.StatusNotFound, fmt.Sprintf(i18n.T(ctx, "category %d not found"), entry.CategoryID))
+	} else if err != nil {
+		return crud.NewError(http.StatusInternalServerError, err.Error())
 	}
 
-	// 总条目数量
-	var total int64
-	if err := tx.Count(&total).Error; err != nil {
-		ginx.SetErrResp(c, http.StatusInternalServerError, err.Error())
+	// 名称唯一（包括回收站中的条目）
+	var existing model.Entry
+	err = database.Client(ctx).Unscoped().Where("name = ? AND id <> ?", entry.Name, entry.ID).First(&existing).Error
+	if err == nil && existing.IsDeleted() {
+		return errors.Errorf(i18n.T(ctx, "entry name `%s` already used by a deleted entry"), entry.Name)
+	} else if err == nil {
+		return errors.Errorf(i18n.T(ctx, "entry name `%s` already used"), entry.Name)
 	}
-
-	// 分页对应数据
-	var entries []model.Entry
-	if err := tx.Offset(ginx.GetOffset(c)).Limit(ginx.GetLimit(c)).Find(&entries).Error; err != nil {
-		ginx.SetErrResp(c, http.StatusInternalServerError, err.Error())
-		return
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
 	}
+	return crud.NewError(http.StatusInternalServerError, err.Error())
+}
 
-	respData := []serializer.EntryListResponse{}
-	for _, entry := range entries {
-		respData = append(respData, serializer.EntryListResponse{
-			CategoryID:   entry.CategoryID,
-			CategoryName: entry.Category.Name,
-			ID:           entry.ID,
-			Name:         entry.Name,
-			Desc:         entry.Desc,
-			Price:        entry.Price,
-			Updater:      entry.Updater,
-			UpdatedAt:    entry.UpdatedAt.Format(time.RFC3339),
-		})
-	}
-	ginx.SetResp(c, http.StatusOK, ginx.NewPaginatedRespData(total, respData))
+// ListEntries ...
+//
+//	@Summary	获取条目列表
+//	@Tags		crud
+//	@Param		categoryID	query		int		false	"分类 ID，支持 categoryID__in（逗号分隔）"
+//	@Param		keyword		query		string	false	"关键字（名称 / 描述 / 更新者）"
+//	@Param		name		query		string	false	"名称，支持 name__contains"
+//	@Param		price		query		number	false	"价格，支持 price__{gt,gte,lt,lte,in}"
+//	@Param		updatedAt	query		string	false	"更新时间，支持 updatedAt__{gt,gte,lt,lte}"
+//	@Param		ordering	query		string	false	"排序字段（逗号分隔，- 表示降序），可选 id / name / price / createdAt / updatedAt"
+//	@Param		fields		query		string	false	"返回字段（逗号分隔），默认返回全部字段"
+//	@Param		trashed		query		bool	false	"为 true 时获取回收站（已删除）中的条目"
+//	@Param		page		query		int		false	"页码"
+//	@Param		limit		query		int		false	"每页数量"
+//	@Param		cursor		query		string	false	"游标（首页传空值，之后传响应中的 next / prev），携带时使用游标分页，响应为 ginx.CursorPaginatedResp（不支持按 price 排序）"
+//	@Success	200			{object}	ginx.Response{data=ginx.PaginatedResp{results=[]serializer.EntryResponse}}
+//	@Router		/api/entries [get]
+func ListEntries(c *gin.Context) {
+	EntryResource.List(c)
 }
 
 // CreateEntry ...
@@ -92,53 +164,10 @@ func ListEntries(c *gin.Context) {
 //	@Summary	创建条目
 //	@Tags		crud
 //	@Param		body	body		serializer.EntryCreateRequest	true	"创建条目请求体"
-//	@Success	201		{object}	ginx.Response{data=nil}
+//	@Success	201		{object}	ginx.Response{data=serializer.EntryCreateResponse}
 //	@Router		/api/entries [post]
 func CreateEntry(c *gin.Context) {
-	var req serializer.EntryCreateRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		ginx.SetErrResp(c, http.StatusBadRequest, err.Error())
-		return
-	}
-
-	if err := req.Validate(c); err != nil {
-		ginx.SetErrResp(c, http.StatusBadRequest, err.Error())
-		return
-	}
-
-	// 检查分类是否存在
-	var category model.Category
-	ctx := c.Request.Context()
-	tx := database.Client(ctx).Where("id = ?", req.CategoryID).First(&category)
-	if tx.Error != nil {
-		if errors.Is(tx.Error, gorm.ErrRecordNotFound) {
-			ginx.SetErrResp(
-				c,
-				http.StatusNotFound,
-				fmt.Sprintf(i18n.T(ctx, "category %d not found"), req.CategoryID),
-			)
-			return
-		}
-		ginx.SetErrResp(c, http.StatusInternalServerError, tx.Error.Error())
-		return
-	}
-
-	entry := model.Entry{
-		Name:       req.Name,
-		Desc:       req.Desc,
-		Price:      req.Price,
-		CategoryID: req.CategoryID,
-		BaseModel: model.BaseModel{
-			Creator: ginx.GetUserID(c),
-			Updater: ginx.GetUserID(c),
-		},
-	}
-	if err := database.Client(ctx).Create(&entry).Error; err != nil {
-		ginx.SetErrResp(c, http.StatusInternalServerError, err.Error())
-		return
-	}
-
-	ginx.SetResp(c, http.StatusCreated, serializer.EntryCreateResponse{ID: entry.ID})
+	EntryResource.Create(c)
 }
 
 // RetrieveEntry ...
@@ -146,89 +175,42 @@ func CreateEntry(c *gin.Context) {
 //	@Summary	获取单个条目
 //	@Tags		crud
 //	@Param		id	path		int	true	"条目 ID"
-//	@Success	200	{object}	ginx.Response{data=serializer.EntryRetrieveResponse}
+//	@Success	200	{object}	ginx.Response{data=serializer.EntryResponse}
 //	@Router		/api/entries/{id} [get]
 func RetrieveEntry(c *gin.Context) {
-	var entry model.Entry
-
-	tx := database.Client(c.Request.Context()).Preload("Category").Where("id = ?", c.Param("id")).First(&entry)
-	if tx.Error != nil {
-		ginx.SetErrResp(c, http.StatusNotFound, tx.Error.Error())
-		return
-	}
-
-	respData := serializer.EntryRetrieveResponse{
-		// 分类属性
-		CategoryID:   entry.CategoryID,
-		CategoryName: entry.Category.Name,
-		// 条目属性
-		ID:        entry.ID,
-		Name:      entry.Name,
-		Desc:      entry.Desc,
-		Price:     entry.Price,
-		Creator:   entry.Creator,
-		Updater:   entry.Updater,
-		CreatedAt: entry.CreatedAt.Format(time.RFC3339),
-		UpdatedAt: entry.UpdatedAt.Format(time.RFC3339),
-	}
-	ginx.SetResp(c, http.StatusOK, respData)
+	EntryResource.Retrieve(c)
 }
 
 // UpdateEntry ...
 //
 //	@Summary	更新条目
 //	@Tags		crud
-//	@Param		id		path	int								true	"条目 ID"
+//	@Param		id		path	int							true	"条目 ID"
 //	@Param		body	body	serializer.EntryUpdateRequest	true	"更新条目请求体"
 //	@Success	204		"No Content"
 //	@Router		/api/entries/{id} [put]
 func UpdateEntry(c *gin.Context) {
-	var req serializer.EntryUpdateRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		ginx.SetErrResp(c, http.StatusBadRequest, err.Error())
-		return
-	}
-
-	if err := req.Validate(c); err != nil {
-		ginx.SetErrResp(c, http.StatusBadRequest, err.Error())
-		return
-	}
-
-	var entry model.Entry
-	ctx := c.Request.Context()
-	tx := database.Client(ctx).Where("id = ?", c.Param("id")).First(&entry)
-	if tx.Error != nil {
-		ginx.SetErrResp(c, http.StatusNotFound, tx.Error.Error())
-		return
-	}
-
-	// 更新 DB 模型字段
-	entry.CategoryID = req.CategoryID
-	entry.Name = req.Name
-	entry.Desc = req.Desc
-	entry.Price = req.Price
-	entry.Updater = ginx.GetUserID(c)
-	tx = database.Client(ctx).Save(&entry)
-	if tx.Error != nil {
-		ginx.SetErrResp(c, http.StatusInternalServerError, tx.Error.Error())
-		return
-	}
-
-	ginx.SetResp(c, http.StatusNoContent, nil)
+	EntryResource.Update(c)
 }
 
 // DestroyEntry ...
 //
-//	@Summary	删除条目
+//	@Summary	删除条目（移入回收站）
 //	@Tags		crud
 //	@Param		id	path	int	true	"条目 ID"
 //	@Success	204	"No Content"
 //	@Router		/api/entries/{id} [delete]
 func DestroyEntry(c *gin.Context) {
-	tx := database.Client(c.Request.Context()).Where("id = ?", c.Param("id")).Delete(&model.Entry{})
-	if tx.Error != nil {
-		ginx.SetErrResp(c, http.StatusInternalServerError, tx.Error.Error())
-		return
-	}
-	ginx.SetResp(c, http.StatusNoContent, nil)
+	EntryResource.Destroy(c)
+}
+
+// RestoreEntry ...
+//
+//	@Summary	恢复回收站中的条目（所属分类需不在回收站中）
+//	@Tags		crud
+//	@Param		id	path		int	true	"条目 ID"
+//	@Success	200	{object}	ginx.Response{data=serializer.EntryResponse}
+//	@Router		/api/entries/{id}/restore [post]
+func RestoreEntry(c *gin.Context) {
+	EntryResource.Restore(c)
 }
@@ -27,6 +27,10 @@ import (
 
 // Register ...
 func Register(rg *gin.RouterGroup) {
+	// 逐个注册具名 handler（以生成 swagger 文档），需先校验资源声明
+	handler.CategoryResource.MustCheck()
+	handler.EntryResource.MustCheck()
+
 	// category
 	categoryRouter := rg.Group("/categories")
 	categoryRouter.GET("", handler.ListCategories)
@@ -34,6 +38,7 @@ func Register(rg *gin.RouterGroup) {
 	categoryRouter.GET("/:id", handler.RetrieveCategory)
 	categoryRouter.PUT("/:id", handler.UpdateCategory)
 	categoryRouter.DELETE("/:id", handler.DestroyCategory)
+	categoryRouter.POST("/:id/restore", handler.RestoreCategory)
 
 	// entry
 	entryRouter := rg.Group("/entries")
@@ -42,4 +47,5 @@ func Register(rg *gin.RouterGroup) {
 	entryRouter.GET("/:id", handler.RetrieveEntry)
 	entryRouter.PUT("/:id", handler.UpdateEntry)
 	entryRouter.DELETE("/:id", handler.DestroyEntry)
+	entryRouter.POST("/:id/restore", handler.RestoreEntry)
 }
@@ -19,79 +19,30 @@
 // Package serializer ...
 package serializer
 
-import (
-	"github.com/gin-gonic/gin"
-	"github.com/pkg/errors"
-	"gorm.io/gorm"
-
-	"github.com/TencentBlueKing/blueapps-go/pkg/i18n"
-	"github.com/TencentBlueKing/blueapps-go/pkg/infras/database"
-	"github.com/TencentBlueKing/blueapps-go/pkg/model"
-)
-
-// CategoryListRequest List Categories API 输入结构
-type CategoryListRequest struct {
-	Keyword string `form:"keyword" binding:"omitempty"`
-}
-
-// CategoryListResponse List Categories API 返回结构
-type CategoryListResponse struct {
-	ID        int64  `json:"id"`
-	Name      string `json:"name"`
-	Updater   string `json:"updater"`
-	UpdatedAt string `json:"updatedAt"`
-}
-
 // CategoryCreateRequest Create Category API 输入结构
 type CategoryCreateRequest struct {
 	Name string `json:"name" binding:"required,min=1,max=32"`
 }
 
-// Validate ...
-func (req *CategoryCreateRequest) Validate(c *gin.Context) error {
-	ctx := c.Request.Context()
-	tx := database.Client(ctx).Where("name = ?", req.Name).First(&model.Category{})
-	if tx.Error == nil {
-		return errors.Errorf(i18n.T(ctx, "category name `%s` already used"), req.Name)
-	}
-	if errors.Is(tx.Error, gorm.ErrRecordNotFound) {
-		return nil
-	}
-	return errors.New(tx.Error.Error())
-}
-
 // CategoryCreateResponse Create Category API 输出结构
 type CategoryCreateResponse struct {
 	ID int64 `json:"id"`
 }
 
-// CategoryRetrieveResponse Retrieve Category API 返回结构
-type CategoryRetrieveResponse struct {
+// CategoryUpdateRequest Update Category API 输入结构
+type CategoryUpdateRequest struct {
+	Name string `json:"name" binding:"required,min=1,max=32"`
+}
+
+// CategoryResponse Category API（列表 / 详情 / 恢复）返回结构
+type CategoryResponse struct {
 	ID        int64  `json:"id"`
 	Name      string `json:"name"`
 	Creator   string `json:"creator"`
 	Updater   string `json:"updater"`
 	CreatedAt string `json:"createdAt"`
 	UpdatedAt string `json:"updatedAt"`
-}
-
-// CategoryUpdateRequest Update Category API 输入结构
-type CategoryUpdateRequest struct {
-	Name string `json:"name" binding:"required,min=1,max=32"`
-}
-
-// Validate ...
-func (req *CategoryUpdateRequest) Validate(c *gin.Context) error {
-	ctx := c.Request.Context()
-	tx := database.Client(ctx).
-		Not("id = ?", c.Param("id")).
-		Where("name = ?", req.Name).
-		First(&model.Category{})
-	if tx.Error == nil {
-		return errors.Errorf(i18n.T(ctx, "category name `%s` already used"), req.Name)
-	}
-	if errors.Is(tx.Error, gorm.ErrRecordNotFound) {
-		return nil
-	}
-	return errors.New(tx.Error.Error())
+	// 删除时间 & 删除者（仅回收站中的分类有值）
+	DeletedAt string `json:"deletedAt"`
+	DeletedBy string `json:"deletedBy"`
 }
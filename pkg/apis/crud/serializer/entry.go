@@ -18,35 +18,6 @@
 
 package serializer
 
-import (
-	"github.com/gin-gonic/gin"
-	"github.com/pkg/errors"
-	"gorm.io/gorm"
-
-	"github.com/TencentBlueKing/blueapps-go/pkg/i18n"
-	"github.com/TencentBlueKing/blueapps-go/pkg/infras/database"
-	"github.com/TencentBlueKing/blueapps-go/pkg/model"
-)
-
-// EntryListRequest List Entries API 输入结构
-type EntryListRequest struct {
-	CategoryID int64  `form:"categoryID" binding:"omitempty,gt=0"`
-	Keyword    string `form:"keyword" binding:"omitempty"`
-}
-
-// EntryListResponse List Entries API 返回结构
-type EntryListResponse struct {
-	CategoryID   int64  `json:"categoryID"`
-	CategoryName string `json:"categoryName"`
-
-	ID        int64   `json:"id"`
-	Name      string  `json:"name"`
-	Desc      string  `json:"desc"`
-	Price     float32 `json:"price"`
-	Updater   string  `json:"updater"`
-	UpdatedAt string  `json:"updatedAt"`
-}
-
 // EntryCreateRequest Create Entry API 输入结构
 type EntryCreateRequest struct {
 	CategoryID int64   `json:"categoryID" binding:"required,gt=0"`
@@ -55,26 +26,22 @@ type EntryCreateRequest struct {
 	Price      float32 `json:"price" binding:"required,gt=0"`
 }
 
-// Validate ...
-func (req *EntryCreateRequest) Validate(c *gin.Context) error {
-	ctx := c.Request.Context()
-	tx := database.Client(ctx).Where("name = ?", req.Name).First(&model.Entry{})
-	if tx.Error == nil {
-		return errors.Errorf(i18n.T(ctx, "entry name `%s` already used"), req.Name)
-	}
-	if errors.Is(tx.Error, gorm.ErrRecordNotFound) {
-		return nil
-	}
-	return errors.New(tx.Error.Error())
-}
-
 // EntryCreateResponse Create Entry API 输出结构
 type EntryCreateResponse struct {
 	ID int64 `json:"id"`
 }
 
-// EntryRetrieveResponse Retrieve Entry API 返回结构
-type EntryRetrieveResponse struct {
+// EntryUpdateRequest Update Entry API 输入结构
+type EntryUpdateRequest struct {
+	CategoryID int64 `json:"categoryID"`
+
+	Name  string  `json:"name" binding:"required,min=1,max=32"`
+	Desc  string  `json:"desc" binding:"omitempty"`
+	Price float32 `json:"price" binding:"required,gt=0"`
+}
+
+// EntryResponse Entry API（列表 / 详情 / 恢复）返回结构
+type EntryResponse struct {
 	CategoryID   int64  `json:"categoryID"`
 	CategoryName string `json:"categoryName"`
 
@@ -86,29 +53,7 @@ type EntryRetrieveResponse struct {
 	Updater   string  `json:"updater"`
 	CreatedAt string  `json:"createdAt"`
 	UpdatedAt string  `json:"updatedAt"`
-}
-
-// EntryUpdateRequest Update Entry API 输入结构
-type EntryUpdateRequest struct {
-	CategoryID int64 `json:"categoryID"`
-
-	Name  string  `json:"name" binding:"required,min=1,max=32"`
-	Desc  string  `json:"desc" binding:"omitempty"`
-	Price float32 `json:"price" binding:"required,gt=0"`
-}
-
-// Validate ...
-func (req *EntryUpdateRequest) Validate(c *gin.Context) error {
-	ctx := c.Request.Context()
-	tx := database.Client(ctx).
-		Not("id = ?", c.Param("id")).
-		Where("name = ?", req.Name).
-		First(&model.Entry{})
-	if tx.Error == nil {
-		return errors.Errorf(i18n.T(ctx, "entry name `%s` already used"), req.Name)
-	}
-	if errors.Is(tx.Error, gorm.ErrRecordNotFound) {
-		return nil
-	}
-	return errors.New(tx.Error.Error())
+	// 删除时间 & 删除者（仅回收站中的条目有值）
+	DeletedAt string `json:"deletedAt"`
+	DeletedBy string `json:"deletedBy"`
 }
@@ -92,7 +92,7 @@ func DeleteDir(c *gin.Context) {
 //	@Summary	获取已上传对象列表
 //	@Tags		object-storage
 //	@Param		query	query		serializer.ListObjectsRequest	true	"获取对象列表请求体"
-//	@Success	200		{object}	ginx.Response{data=ginx.PaginatedResp{results=[]serializer.CategoryListResponse}}
+//	@Success	200		{object}	ginx.Response{data=ginx.PaginatedResp{results=[]serializer.ListObjectsResponse}}
 //	@Router		/api/obj-storage/objects [get]
 func ListObjects(c *gin.Context) {
 	var req serializer.ListObjectsRequest
@@ -0,0 +1,228 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - Go 开发框架 (BlueKing - Go Framework) available.
+ * Copyright (C) 2017 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ *	https://opensource.org/licenses/MIT
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package async
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+
+	"github.com/TencentBlueKing/blueapps-go/pkg/common"
+	"github.com/TencentBlueKing/blueapps-go/pkg/config"
+	log "github.com/TencentBlueKing/blueapps-go/pkg/logging"
+	"github.com/TencentBlueKing/blueapps-go/pkg/utils/uuidx"
+)
+
+const (
+	// BrokerLocal 进程内 goroutine 执行任务（不持久化）
+	BrokerLocal = "local"
+	// BrokerRedis 基于 Redis Stream 的持久化任务队列
+	BrokerRedis = "redis"
+	// BrokerRabbitMQ 基于 RabbitMQ 的持久化任务队列
+	BrokerRabbitMQ = "rabbitmq"
+)
+
+// Message 任务消息，由 ApplyTask 投递到 Broker，并由 worker 消费执行
+type Message struct {
+	// 消息 ID，每次投递唯一
+	ID string `json:"id"`
+	// 任务记录 ID（model.Task）
+	TaskID int64 `json:"taskID"`
+	// 任务名称（需通过 Register 注册）
+	Name string `json:"name"`
+	// 任务参数（JSON）
+	Args json.RawMessage `json:"args"`
+	// 下发任务的请求 ID，用于串联日志
+	RequestID string `json:"requestID"`
+	// 下发时间
+	EnqueuedAt time.Time `json:"enqueuedAt"`
+	// 第几次执行（从 1 开始），重试时递增
+	Attempt int `json:"attempt"`
+	// 预计执行时间，为空表示立即执行
+	ETA time.Time `json:"eta,omitempty"`
+	// 单次执行超时时间，为 0 表示不限制
+	Timeout time.Duration `json:"timeout,omitempty"`
+	// 任务队列，为空表示默认队列
+	Queue string `json:"queue,omitempty"`
+	// 优先级，同一队列中数值越大越优先执行
+	Priority int `json:"priority,omitempty"`
+	// 所属工作流节点的任务 ID，不属于工作流的任务为 0
+	ParentID int64 `json:"parentID,omitempty"`
+	// 下发任务时的 trace 上下文（W3C Trace Context），执行任务的 span 会链接到下发任务的 span
+	TraceContext map[string]string `json:"traceContext,omitempty"`
+	// 消息处理失败（如 DB 不可用）后被 Broker 重新投递的次数（Redis Broker 使用，RabbitMQ 记录在 Header 中）
+	Requeues int `json:"requeues,omitempty"`
+}
+
+// 任务消息所在的队列
+func (m *Message) queue() string {
+	if m.Queue == "" {
+		return DefaultQueue
+	}
+	return m.Queue
+}
+
+// 任务消息还需等待多久才能执行
+func (m *Message) delay() time.Duration {
+	if m.ETA.IsZero() {
+		return 0
+	}
+	return max(time.Until(m.ETA), 0)
+}
+
+// 构建重试任务消息（新的消息 ID，执行次数 +1，延迟 backoff 后执行）
+func (m *Message) retry(backoff time.Duration) *Message {
+	msg := *m
+	msg.ID = uuidx.New()
+	msg.Attempt = m.Attempt + 1
+	msg.EnqueuedAt = time.Now()
+	msg.ETA = msg.EnqueuedAt.Add(backoff)
+	return &msg
+}
+
+// 构建任务消息
+func newMessage(ctx context.Context, taskID int64, name string, args json.RawMessage) *Message {
+	requestID, _ := ctx.Value(common.RequestIDCtxKey).(string)
+	traceContext := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, traceContext)
+	return &Message{
+		ID:           uuidx.New(),
+		TaskID:       taskID,
+		Name:         name,
+		Args:         args,
+		RequestID:    requestID,
+		EnqueuedAt:   time.Now(),
+		Attempt:      1,
+		TraceContext: traceContext,
+	}
+}
+
+// Handler 任务消息处理函数，返回 nil 表示确认（ack），否则消息会被重新投递（requeue）
+type Handler func(ctx context.Context, msg *Message) error
+
+// Broker 任务消息队列
+type Broker interface {
+	// Name 消息队列类型
+	Name() string
+	// Publish 投递任务消息，若消息指定了 ETA，则需在 ETA 之后才能被消费
+	Publish(ctx context.Context, msg *Message) error
+	// Consume 阻塞消费指定队列的任务消息，直到 ctx 被取消
+	Consume(ctx context.Context, queue string, handler Handler) error
+}
+
+var (
+	broker         Broker
+	brokerInitOnce sync.Once
+	// 异步任务配置（任务队列等）
+	asyncConfig *config.AsyncConfig
+
+	// 未调用 InitBroker 时使用的进程内 Broker
+	fallbackBroker     Broker
+	fallbackBrokerOnce sync.Once
+)
+
+// InitBroker 根据配置初始化任务消息队列
+func InitBroker(ctx context.Context, cfg *config.AsyncConfig) error {
+	var err error
+	brokerInitOnce.Do(func() {
+		asyncConfig = cfg
+		switch cfg.Broker {
+		case "", BrokerLocal:
+			broker = newLocalBroker(cfg, handleMessage)
+		case BrokerRedis:
+			broker, err = newRedisBroker(cfg)
+		case BrokerRabbitMQ:
+			broker, err = newRabbitMQBroker(ctx, cfg)
+		default:
+			err = errors.Errorf("unsupported async task broker: %s", cfg.Broker)
+		}
+		if err == nil {
+			log.Infof(ctx, "async task broker: %s initialized", broker.Name())
+		}
+	})
+	return err
+}
+
+// 获取任务消息队列，未初始化时退化为进程内执行（兼容未调用 InitBroker 的场景，如单元测试）
+func getBroker() Broker {
+	if broker == nil {
+		fallbackBrokerOnce.Do(func() {
+			fallbackBroker = newLocalBroker(&config.AsyncConfig{Concurrency: 10}, handleMessage)
+		})
+		return fallbackBroker
+	}
+	return broker
+}
+
+// 任务队列是否已配置
+func isQueueConfigured(queue string) bool {
+	for _, queueCfg := range queueConfigs(asyncConfig) {
+		if queueCfg.Name == queue {
+			return true
+		}
+	}
+	return false
+}
+
+// 进程内 Broker：投递到当前进程的 worker pool 中执行，队列满时拒绝投递，进程重启 / 崩溃会导致任务丢失
+type localBroker struct {
+	pool *workerPool
+	// 延迟任务到期后无法提交到 worker pool 时的处理
+	onSubmitFailed func(ctx context.Context, msg *Message, err error)
+}
+
+func newLocalBroker(cfg *config.AsyncConfig, handler Handler) *localBroker {
+	pool := newWorkerPool(cfg, handler)
+	pool.start()
+	return &localBroker{pool: pool, onSubmitFailed: failMessage}
+}
+
+// Name ...
+func (b *localBroker) Name() string {
+	return BrokerLocal
+}
+
+// Publish ...
+func (b *localBroker) Publish(ctx context.Context, msg *Message) error {
+	// 异步执行，不应受调用方 context 取消的影响
+	ctx = context.WithoutCancel(ctx)
+	if msg.delay() <= 0 {
+		return b.pool.submit(ctx, msg)
+	}
+	// 延迟任务到期时队列已满，任务无法执行，需要按执行失败处理（标记失败 & 死信 & 释放锁 & 推进工作流）
+	time.AfterFunc(msg.delay(), func() {
+		if err := b.pool.submit(ctx, msg); err != nil {
+			log.Errorf(ctx, "submit delayed task message %s (%s) error: %s", msg.ID, msg.Name, err)
+			b.onSubmitFailed(ctx, msg, err)
+		}
+	})
+	return nil
+}
+
+// Consume 进程内 Broker 在投递时已执行任务，无需消费
+func (b *localBroker) Consume(ctx context.Context, _ string, _ Handler) error {
+	log.Warn(ctx, "local broker executes tasks in publisher process, worker has nothing to consume")
+	<-ctx.Done()
+	return nil
+}
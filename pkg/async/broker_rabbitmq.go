@@ -0,0 +1,317 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - Go 开发框架 (BlueKing - Go Framework) available.
+ * Copyright (C) 2017 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ *	https://opensource.org/licenses/MIT
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package async
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	amqp "github.com/rabbitmq/amqp091-go"
+
+	"github.com/TencentBlueKing/blueapps-go/pkg/config"
+	"github.com/TencentBlueKing/blueapps-go/pkg/infras/rabbitmq"
+	log "github.com/TencentBlueKing/blueapps-go/pkg/logging"
+)
+
+const (
+	// 默认任务队列名称
+	rabbitMQTaskQueue = "blueapps-go.async.tasks"
+	// 其他任务队列名称前缀
+	rabbitMQQueuePrefix = "blueapps-go.async.queues."
+	// 死信交换机名称
+	rabbitMQDeadLetterExchange = "blueapps-go.async.dlx"
+	// 死信队列名称
+	rabbitMQDeadLetterQueue = "blueapps-go.async.tasks.dead"
+	// 延迟队列名称模板（参数为任务队列名称 & 延迟秒数）
+	rabbitMQDelayQueueTmpl = "%s.delay.%d"
+	// 延迟队列空闲多久后自动删除
+	rabbitMQDelayQueueExpires = time.Minute
+	// 记录消息被重新投递次数的 Header
+	rabbitMQRequeueCountHeader = "x-requeue-count"
+	// 消息最多被重新投递的次数，超过后进入死信队列
+	rabbitMQMaxRequeueCount = 5
+	// 连接 / 通道断开后的重连间隔
+	rabbitMQReconnectInterval = 3 * time.Second
+)
+
+// 基于 RabbitMQ 实现的持久化 Broker，提供 at-least-once 投递语义：
+// - 任务消息投递到持久化队列，并通过 publisher confirm 确保消息已被 RabbitMQ 接收
+// - worker 使用手动确认（manual ack），连接断开时未确认的消息会由 RabbitMQ 重新投递
+// - 处理失败的消息会被重新投递，超过最大次数 / 无法解析的消息会进入死信队列，便于排查
+// - 延迟消息（如重试）投递到按延迟秒数划分的延迟队列（同一队列 TTL 一致，不会出现队头阻塞），过期后转发到任务队列
+// - 每个任务队列对应一个 RabbitMQ 队列，共用死信队列
+type rabbitMQBroker struct {
+	consumerTag string
+	prefetch    int
+
+	// 投递消息使用的通道（开启 confirm 模式），通道非并发安全，需加锁使用
+	pubChannel *amqp.Channel
+	pubLock    sync.Mutex
+}
+
+func newRabbitMQBroker(ctx context.Context, cfg *config.AsyncConfig) (*rabbitMQBroker, error) {
+	if config.G == nil || config.G.Platform.Addons.RabbitMQ == nil {
+		return nil, errors.New("rabbitmq broker requires rabbitmq addon")
+	}
+	rabbitmq.InitRabbitMQClient(ctx, config.G.Platform.Addons.RabbitMQ)
+
+	hostname, _ := os.Hostname()
+	b := &rabbitMQBroker{
+		consumerTag: fmt.Sprintf("%s-%d", hostname, os.Getpid()),
+		prefetch:    max(cfg.Prefetch, 1),
+	}
+	// 提前声明队列 & 死信交换机，确保 webserver 先于 worker 启动时消息不会丢失
+	ch, err := b.openChannel()
+	if err != nil {
+		return nil, err
+	}
+	defer ch.Close()
+
+	return b, nil
+}
+
+// Name ...
+func (b *rabbitMQBroker) Name() string {
+	return BrokerRabbitMQ
+}
+
+// 任务队列对应的 RabbitMQ 队列名称（默认队列沿用原有的名称）
+func rabbitMQQueueName(queue string) string {
+	if queue == DefaultQueue {
+		return rabbitMQTaskQueue
+	}
+	return rabbitMQQueuePrefix + queue
+}
+
+// 打开通道并声明所有任务队列 & 死信队列
+func (b *rabbitMQBroker) openChannel() (*amqp.Channel, error) {
+	conn, err := rabbitmq.Client()
+	if err != nil {
+		return nil, err
+	}
+	ch, err := conn.Channel()
+	if err != nil {
+		return nil, errors.Wrap(err, "open rabbitmq channel")
+	}
+
+	// 死信交换机 & 队列
+	err = ch.ExchangeDeclare(rabbitMQDeadLetterExchange, amqp.ExchangeDirect, true, false, false, false, nil)
+	if err != nil {
+		_ = ch.Close()
+		return nil, errors.Wrapf(err, "declare exchange %s", rabbitMQDeadLetterExchange)
+	}
+	if _, err = ch.QueueDeclare(rabbitMQDeadLetterQueue, true, false, false, false, nil); err != nil {
+		_ = ch.Close()
+		return nil, errors.Wrapf(err, "declare queue %s", rabbitMQDeadLetterQueue)
+	}
+	for _, queueCfg := range queueConfigs(asyncConfig) {
+		if err = declareTaskQueue(ch, rabbitMQQueueName(queueCfg.Name)); err != nil {
+			_ = ch.Close()
+			return nil, err
+		}
+	}
+	return ch, nil
+}
+
+// 声明任务队列，被拒绝（nack & 不重新入队）的消息会转发到死信交换机，并路由到死信队列
+func declareTaskQueue(ch *amqp.Channel, queue string) error {
+	_, err := ch.QueueDeclare(queue, true, false, false, false, amqp.Table{
+		"x-dead-letter-exchange":    rabbitMQDeadLetterExchange,
+		"x-dead-letter-routing-key": queue,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "declare queue %s", queue)
+	}
+	if err = ch.QueueBind(rabbitMQDeadLetterQueue, queue, rabbitMQDeadLetterExchange, false, nil); err != nil {
+		return errors.Wrapf(err, "bind queue %s", rabbitMQDeadLetterQueue)
+	}
+	return nil
+}
+
+// 声明延迟队列：消息过期后通过默认交换机转发到任务队列，队列空闲一段时间后自动删除
+func declareDelayQueue(ch *amqp.Channel, taskQueue string, delaySeconds int64) (string, error) {
+	queue := fmt.Sprintf(rabbitMQDelayQueueTmpl, taskQueue, delaySeconds)
+	ttl := delaySeconds * int64(time.Second/time.Millisecond)
+	_, err := ch.QueueDeclare(queue, true, false, false, false, amqp.Table{
+		"x-message-ttl":             ttl,
+		"x-expires":                 ttl + rabbitMQDelayQueueExpires.Milliseconds(),
+		"x-dead-letter-exchange":    "",
+		"x-dead-letter-routing-key": taskQueue,
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "declare queue %s", queue)
+	}
+	return queue, nil
+}
+
+// 获取投递消息使用的通道（需持有 pubLock）
+func (b *rabbitMQBroker) publishChannel() (*amqp.Channel, error) {
+	if b.pubChannel != nil && !b.pubChannel.IsClosed() {
+		return b.pubChannel, nil
+	}
+	ch, err := b.openChannel()
+	if err != nil {
+		return nil, err
+	}
+	if err = ch.Confirm(false); err != nil {
+		_ = ch.Close()
+		return nil, errors.Wrap(err, "enable publisher confirm")
+	}
+	b.pubChannel = ch
+	return ch, nil
+}
+
+// Publish ...
+func (b *rabbitMQBroker) Publish(ctx context.Context, msg *Message) error {
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return errors.Wrapf(err, "marshal task message %s", msg.ID)
+	}
+	queue := rabbitMQQueueName(msg.queue())
+	// 延迟时间向上取整到秒，避免产生过多的延迟队列
+	if delay := msg.delay(); delay > 0 {
+		return b.publish(ctx, queue, int64((delay+time.Second-1)/time.Second), payload, nil)
+	}
+	return b.publish(ctx, queue, 0, payload, nil)
+}
+
+// 投递消息到任务队列（delaySeconds > 0 时投递到对应的延迟队列），并等待 RabbitMQ 确认
+func (b *rabbitMQBroker) publish(
+	ctx context.Context, taskQueue string, delaySeconds int64, payload []byte, headers amqp.Table,
+) error {
+	b.pubLock.Lock()
+	defer b.pubLock.Unlock()
+
+	ch, err := b.publishChannel()
+	if err != nil {
+		return err
+	}
+	queue := taskQueue
+	if delaySeconds > 0 {
+		if queue, err = declareDelayQueue(ch, taskQueue, delaySeconds); err != nil {
+			return err
+		}
+	}
+	confirm, err := ch.PublishWithDeferredConfirmWithContext(ctx, "", queue, false, false, amqp.Publishing{
+		ContentType:  "application/json",
+		DeliveryMode: amqp.Persistent,
+		Headers:      headers,
+		Body:         payload,
+	})
+	if err != nil {
+		return errors.Wrap(err, "publish task message")
+	}
+	acked, err := confirm.WaitContext(ctx)
+	if err != nil {
+		return errors.Wrap(err, "wait publisher confirm")
+	}
+	if !acked {
+		return errors.New("task message nacked by rabbitmq")
+	}
+	return nil
+}
+
+// Consume ...
+func (b *rabbitMQBroker) Consume(ctx context.Context, queue string, handler Handler) error {
+	for ctx.Err() == nil {
+		if err := b.consume(ctx, rabbitMQQueueName(queue), handler); err != nil && ctx.Err() == nil {
+			log.Errorf(ctx, "consume rabbitmq task queue error: %s, reconnect after %s", err, rabbitMQReconnectInterval)
+			time.Sleep(rabbitMQReconnectInterval)
+		}
+	}
+	return nil
+}
+
+// 在单个通道上消费消息，直到通道关闭或 ctx 被取消
+func (b *rabbitMQBroker) consume(ctx context.Context, queue string, handler Handler) error {
+	ch, err := b.openChannel()
+	if err != nil {
+		return err
+	}
+	defer ch.Close()
+
+	// 限制未确认的消息数量，避免单个 worker 积压过多消息
+	if err = ch.Qos(b.prefetch, 0, false); err != nil {
+		return errors.Wrap(err, "set rabbitmq channel qos")
+	}
+	deliveries, err := ch.ConsumeWithContext(ctx, queue, b.consumerTag, false, false, false, false, nil)
+	if err != nil {
+		return errors.Wrapf(err, "consume queue %s", queue)
+	}
+
+	for d := range deliveries {
+		b.process(ctx, queue, d, handler)
+	}
+	if ctx.Err() != nil {
+		return nil
+	}
+	return errors.New("rabbitmq delivery channel closed")
+}
+
+// 处理单条消息：成功后确认，失败则重新投递，超过最大重新投递次数后进入死信队列
+func (b *rabbitMQBroker) process(ctx context.Context, queue string, d amqp.Delivery, handler Handler) {
+	// 确认 / 拒绝消息不应受 worker 退出的影响
+	ackCtx := context.WithoutCancel(ctx)
+
+	var msg Message
+	if err := json.Unmarshal(d.Body, &msg); err != nil {
+		log.Errorf(ctx, "dead letter invalid task message %d: %s", d.DeliveryTag, err)
+		b.nack(ackCtx, d)
+		return
+	}
+
+	err := handler(ctx, &msg)
+	if err == nil {
+		if err = d.Ack(false); err != nil {
+			log.Errorf(ackCtx, "ack task message %s error: %s", msg.ID, err)
+		}
+		return
+	}
+
+	count, _ := d.Headers[rabbitMQRequeueCountHeader].(int32)
+	if count >= rabbitMQMaxRequeueCount {
+		log.Errorf(ackCtx, "dead letter task message %s (%s) after %d requeues: %s", msg.ID, msg.Name, count, err)
+		b.nack(ackCtx, d)
+		return
+	}
+
+	log.Warnf(ackCtx, "requeue task message %s (%s): %s", msg.ID, msg.Name, err)
+	// 重新投递到队列尾部并累加次数，投递成功后再确认原消息，保证消息不丢失
+	if err = b.publish(ackCtx, queue, 0, d.Body, amqp.Table{rabbitMQRequeueCountHeader: count + 1}); err != nil {
+		log.Errorf(ackCtx, "requeue task message %s error: %s", msg.ID, err)
+		// 重新投递失败则交由 RabbitMQ 重新入队
+		_ = d.Nack(false, true)
+		return
+	}
+	if err = d.Ack(false); err != nil {
+		log.Errorf(ackCtx, "ack task message %s error: %s", msg.ID, err)
+	}
+}
+
+// 拒绝消息且不重新入队，消息会被转发到死信交换机
+func (b *rabbitMQBroker) nack(ctx context.Context, d amqp.Delivery) {
+	if err := d.Nack(false, false); err != nil {
+		log.Errorf(ctx, "nack task message %d error: %s", d.DeliveryTag, err)
+	}
+}
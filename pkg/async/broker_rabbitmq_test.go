@@ -0,0 +1,139 @@
+//go:build integration
+
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - Go 开发框架 (BlueKing - Go Framework) available.
+ * Copyright (C) 2017 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ *	https://opensource.org/licenses/MIT
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package async
+
+// RabbitMQ Broker 集成测试，需要可用的 RabbitMQ（连接信息同 RABBITMQ_* 环境变量）：
+// RABBITMQ_HOST=127.0.0.1 RABBITMQ_PORT=5672 go test -tags integration -run TestRabbitMQBroker ./pkg/async/
+
+import (
+	"context"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/TencentBlueKing/blueapps-go/pkg/config"
+	"github.com/TencentBlueKing/blueapps-go/pkg/infras/rabbitmq"
+	"github.com/TencentBlueKing/blueapps-go/pkg/utils/envx"
+)
+
+func newTestRabbitMQBroker(t *testing.T) (*rabbitMQBroker, *amqp.Channel) {
+	port, _ := strconv.Atoi(envx.Get("RABBITMQ_PORT", "5672"))
+	rabbitmq.InitRabbitMQClient(context.Background(), &config.RabbitMQConfig{
+		Host:     envx.Get("RABBITMQ_HOST", "127.0.0.1"),
+		Port:     port,
+		User:     envx.Get("RABBITMQ_USER", "guest"),
+		Password: envx.Get("RABBITMQ_PASSWORD", "guest"),
+		Vhost:    envx.Get("RABBITMQ_VHOST", ""),
+	})
+	b := &rabbitMQBroker{consumerTag: "test", prefetch: 1}
+	ch, err := b.openChannel()
+	assert.NoError(t, err)
+	t.Cleanup(func() { _ = ch.Close() })
+
+	// 清空测试前残留的消息
+	for _, queue := range []string{rabbitMQTaskQueue, rabbitMQDeadLetterQueue} {
+		_, err = ch.QueuePurge(queue, false)
+		assert.NoError(t, err)
+	}
+	return b, ch
+}
+
+// 在 timeout 内从队列中获取一条消息
+func getMessage(t *testing.T, ch *amqp.Channel, queue string, timeout time.Duration) (amqp.Delivery, bool) {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		d, ok, err := ch.Get(queue, true)
+		assert.NoError(t, err)
+		if ok {
+			return d, true
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return amqp.Delivery{}, false
+}
+
+func TestRabbitMQBrokerDeadLetter(t *testing.T) {
+	b, ch := newTestRabbitMQBroker(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	assert.NoError(t, b.Publish(ctx, &Message{ID: "a", Name: "greet"}))
+
+	// 始终处理失败：重新投递 rabbitMQMaxRequeueCount 次后进入死信队列
+	var attempts atomic.Int32
+	go func() {
+		_ = b.Consume(ctx, DefaultQueue, func(context.Context, *Message) error {
+			attempts.Add(1)
+			return errors.New("failed")
+		})
+	}()
+
+	d, ok := getMessage(t, ch, rabbitMQDeadLetterQueue, 10*time.Second)
+	assert.True(t, ok)
+	// 经死信交换机按原队列名称路由
+	assert.Equal(t, rabbitMQDeadLetterExchange, d.Exchange)
+	assert.Equal(t, rabbitMQTaskQueue, d.RoutingKey)
+	assert.EqualValues(t, rabbitMQMaxRequeueCount, d.Headers[rabbitMQRequeueCountHeader])
+	cancel()
+	assert.EqualValues(t, rabbitMQMaxRequeueCount+1, attempts.Load())
+}
+
+func TestRabbitMQBrokerDeadLetterInvalidMessage(t *testing.T) {
+	b, ch := newTestRabbitMQBroker(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// 无法解析的消息直接进入死信队列
+	assert.NoError(t, b.publish(ctx, rabbitMQTaskQueue, 0, []byte("invalid"), nil))
+	go func() {
+		_ = b.Consume(ctx, DefaultQueue, func(context.Context, *Message) error { return nil })
+	}()
+
+	d, ok := getMessage(t, ch, rabbitMQDeadLetterQueue, 5*time.Second)
+	assert.True(t, ok)
+	assert.Equal(t, "invalid", string(d.Body))
+}
+
+func TestRabbitMQBrokerDelay(t *testing.T) {
+	b, ch := newTestRabbitMQBroker(t)
+	ctx := context.Background()
+	assert.NoError(t, b.Publish(ctx, &Message{ID: "a", Name: "greet", ETA: time.Now().Add(1500 * time.Millisecond)}))
+
+	// 延迟时间向上取整到秒，投递到对应的延迟队列
+	delayQueue, err := ch.QueueDeclarePassive(rabbitMQTaskQueue+".delay.2", true, false, false, false, amqp.Table{
+		"x-message-ttl":             int64(2000),
+		"x-expires":                 int64(2000) + rabbitMQDelayQueueExpires.Milliseconds(),
+		"x-dead-letter-exchange":    "",
+		"x-dead-letter-routing-key": rabbitMQTaskQueue,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, delayQueue.Messages)
+
+	// 到期前不会出现在任务队列中，过期后转发到任务队列
+	_, ok := getMessage(t, ch, rabbitMQTaskQueue, time.Second)
+	assert.False(t, ok)
+	d, ok := getMessage(t, ch, rabbitMQTaskQueue, 3*time.Second)
+	assert.True(t, ok)
+	assert.Equal(t, rabbitMQTaskQueue, d.RoutingKey)
+}
@@ -0,0 +1,318 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - Go 开发框架 (BlueKing - Go Framework) available.
+ * Copyright (C) 2017 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ *	https://opensource.org/licenses/MIT
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package async
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+	goredis "github.com/redis/go-redis/v9"
+
+	"github.com/TencentBlueKing/blueapps-go/pkg/config"
+	"github.com/TencentBlueKing/blueapps-go/pkg/infras/redis"
+	log "github.com/TencentBlueKing/blueapps-go/pkg/logging"
+)
+
+const (
+	// 默认任务队列（Redis Stream）的 key
+	redisTaskStreamKey = "blueapps-go:async:tasks"
+	// 其他任务队列（Redis Stream）的 key 前缀
+	redisQueueStreamKeyPrefix = "blueapps-go:async:queues:"
+	// 延迟任务（Sorted Set，score 为预计执行时间戳）的 key 后缀，每个队列各自一个
+	redisDelayedSetKeySuffix = ":delayed"
+	// 消费者组名称
+	redisConsumerGroup = "workers"
+	// Stream 中存放消息体的字段
+	redisPayloadField = "payload"
+	// 单次阻塞读取的等待时间
+	redisReadBlockTime = 2 * time.Second
+	// 单次重新认领超时消息的数量
+	redisReclaimCount = 10
+	// 单次转移到期延迟消息的数量
+	redisPromoteCount = 100
+	// 消息最多被重新投递的次数，超过后标记任务失败并记录死信
+	redisMaxRequeueCount = 5
+)
+
+// 将到期的延迟消息原子地从 Sorted Set 转移到 Stream 中，避免多个 worker 重复转移
+var redisPromoteScript = goredis.NewScript(`
+local msgs = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'LIMIT', 0, ARGV[2])
+for _, payload in ipairs(msgs) do
+	redis.call('ZREM', KEYS[1], payload)
+	redis.call('XADD', KEYS[2], '*', ARGV[3], payload)
+end
+return #msgs
+`)
+
+// 基于 Redis Stream + 消费者组实现的持久化 Broker，提供 at-least-once 投递语义：
+// - 消息被 worker 读取后进入 PEL（Pending Entries List），直到被确认（XACK）
+// - 处理中的消息会定期续期，worker 崩溃后，超过可见性超时的消息会被其他 worker 重新认领（XAUTOCLAIM）
+// - 处理失败的消息会被重新投递到队列尾部，超过最大重新投递次数后标记任务失败并记录死信
+// - 延迟消息（如重试）先存放在 Sorted Set 中，到期后由 worker 转移到 Stream 中
+// - 每个任务队列对应一个 Stream & Sorted Set
+type redisBroker struct {
+	client *goredis.Client
+	group  string
+	// 消费者名称前缀（hostname-pid），每个 Consume 调用（消费协程）各自使用 {前缀}-{序号} 作为消费者名称，
+	// 以便区分 PEL 中消息的归属，续期 & 重新认领时不会误操作其他协程的消息
+	consumerPrefix    string
+	consumerSeq       atomic.Int64
+	visibilityTimeout time.Duration
+	// 消息超过最大重新投递次数后的处理
+	onRequeueExhausted func(ctx context.Context, msg *Message, err error)
+}
+
+func newRedisBroker(cfg *config.AsyncConfig) (*redisBroker, error) {
+	if config.G == nil || config.G.Platform.Addons.Redis == nil {
+		return nil, errors.New("redis broker requires redis addon")
+	}
+	hostname, _ := os.Hostname()
+	return &redisBroker{
+		client:             redis.Client(),
+		group:              redisConsumerGroup,
+		consumerPrefix:     fmt.Sprintf("%s-%d", hostname, os.Getpid()),
+		visibilityTimeout:  time.Duration(max(cfg.VisibilityTimeout, 1)) * time.Second,
+		onRequeueExhausted: failMessage,
+	}, nil
+}
+
+// Name ...
+func (b *redisBroker) Name() string {
+	return BrokerRedis
+}
+
+// 任务队列对应的 Stream key（默认队列沿用原有的 key）
+func redisStreamKey(queue string) string {
+	if queue == DefaultQueue {
+		return redisTaskStreamKey
+	}
+	return redisQueueStreamKeyPrefix + queue
+}
+
+// 任务队列对应的延迟任务 Sorted Set key
+func redisDelayedSetKey(queue string) string {
+	return redisStreamKey(queue) + redisDelayedSetKeySuffix
+}
+
+// Publish ...
+func (b *redisBroker) Publish(ctx context.Context, msg *Message) error {
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return errors.Wrapf(err, "marshal task message %s", msg.ID)
+	}
+	if msg.delay() > 0 {
+		return b.client.ZAdd(ctx, redisDelayedSetKey(msg.queue()), goredis.Z{
+			Score:  float64(msg.ETA.UnixMilli()),
+			Member: payload,
+		}).Err()
+	}
+	return b.client.XAdd(ctx, &goredis.XAddArgs{
+		Stream: redisStreamKey(msg.queue()),
+		Values: map[string]any{redisPayloadField: payload},
+	}).Err()
+}
+
+// Consume ...
+func (b *redisBroker) Consume(ctx context.Context, queue string, handler Handler) error {
+	stream := redisStreamKey(queue)
+	consumer := b.newConsumer()
+	if err := b.ensureGroup(ctx, stream); err != nil {
+		return err
+	}
+
+	for ctx.Err() == nil {
+		// 将到期的延迟消息转移到队列中
+		if err := b.promote(ctx, queue); err != nil && ctx.Err() == nil {
+			log.Errorf(ctx, "promote delayed task messages error: %s", err)
+		}
+		// 优先认领超过可见性超时仍未确认的消息（如 worker 崩溃时正在执行的任务）
+		msgs, _, err := b.client.XAutoClaim(ctx, &goredis.XAutoClaimArgs{
+			Stream:   stream,
+			Group:    b.group,
+			Consumer: consumer,
+			MinIdle:  b.visibilityTimeout,
+			Start:    "0-0",
+			Count:    redisReclaimCount,
+		}).Result()
+		if err != nil && ctx.Err() == nil {
+			log.Errorf(ctx, "reclaim pending task messages error: %s", err)
+		}
+		// 读取新消息
+		if len(msgs) == 0 {
+			msgs, err = b.read(ctx, stream, consumer)
+			if err != nil && ctx.Err() == nil {
+				log.Errorf(ctx, "read task messages error: %s", err)
+				time.Sleep(redisReadBlockTime)
+				continue
+			}
+		}
+
+		for _, m := range msgs {
+			b.process(ctx, stream, consumer, m, handler)
+		}
+	}
+	return nil
+}
+
+// 生成消费者名称（同一进程内各消费协程唯一）
+func (b *redisBroker) newConsumer() string {
+	return fmt.Sprintf("%s-%d", b.consumerPrefix, b.consumerSeq.Add(1))
+}
+
+// 确保 Stream & 消费者组存在
+func (b *redisBroker) ensureGroup(ctx context.Context, stream string) error {
+	err := b.client.XGroupCreateMkStream(ctx, stream, b.group, "0").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return errors.Wrapf(err, "create consumer group %s for stream %s", b.group, stream)
+	}
+	return nil
+}
+
+// 将到期的延迟消息转移到队列中
+func (b *redisBroker) promote(ctx context.Context, queue string) error {
+	return redisPromoteScript.Run(
+		ctx, b.client, []string{redisDelayedSetKey(queue), redisStreamKey(queue)},
+		time.Now().UnixMilli(), redisPromoteCount, redisPayloadField,
+	).Err()
+}
+
+// 阻塞读取一条新消息
+func (b *redisBroker) read(ctx context.Context, stream, consumer string) ([]goredis.XMessage, error) {
+	streams, err := b.client.XReadGroup(ctx, &goredis.XReadGroupArgs{
+		Group:    b.group,
+		Consumer: consumer,
+		Streams:  []string{stream, ">"},
+		Count:    1,
+		Block:    redisReadBlockTime,
+	}).Result()
+	if errors.Is(err, goredis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var msgs []goredis.XMessage
+	for _, s := range streams {
+		msgs = append(msgs, s.Messages...)
+	}
+	return msgs, nil
+}
+
+// 处理单条消息：执行期间定期续期，成功后确认，失败则重新投递，超过最大重新投递次数后标记任务失败并确认
+func (b *redisBroker) process(
+	ctx context.Context, stream, consumer string, m goredis.XMessage, handler Handler,
+) {
+	payload, _ := m.Values[redisPayloadField].(string)
+
+	var msg Message
+	if err := json.Unmarshal([]byte(payload), &msg); err != nil {
+		// 无法解析的消息重试也没有意义，直接确认丢弃以避免反复投递
+		log.Errorf(ctx, "drop invalid task message %s: %s", m.ID, err)
+		b.ack(ctx, stream, m.ID)
+		return
+	}
+
+	stopHeartbeat := b.heartbeat(ctx, stream, consumer, m.ID)
+	err := handler(ctx, &msg)
+	stopHeartbeat()
+
+	if err == nil {
+		b.ack(ctx, stream, m.ID)
+		return
+	}
+	if msg.Requeues >= redisMaxRequeueCount {
+		log.Errorf(ctx, "dead letter task message %s (%s) after %d requeues: %s", msg.ID, msg.Name, msg.Requeues, err)
+		// 标记任务失败 & 记录死信不应受 worker 退出的影响
+		b.onRequeueExhausted(context.WithoutCancel(ctx), &msg, errors.Wrapf(err, "requeued %d times", msg.Requeues))
+		b.ack(ctx, stream, m.ID)
+		return
+	}
+
+	log.Warnf(ctx, "requeue task message %s (%s): %s", msg.ID, msg.Name, err)
+	msg.Requeues++
+	requeued, mErr := json.Marshal(&msg)
+	if mErr != nil {
+		// 消息刚从 payload 解析而来，通常不会序列化失败，失败时按原消息重新投递
+		log.Errorf(ctx, "marshal task message %s error: %s", msg.ID, mErr)
+		requeued = []byte(payload)
+	}
+	b.requeue(ctx, stream, m.ID, string(requeued))
+}
+
+// 定期重置消息的空闲时间，避免长时间运行的任务被其他 worker 重新认领
+func (b *redisBroker) heartbeat(ctx context.Context, stream, consumer, id string) (stop func()) {
+	hbCtx, cancel := context.WithCancel(ctx)
+	go func() {
+		ticker := time.NewTicker(b.visibilityTimeout / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-hbCtx.Done():
+				return
+			case <-ticker.C:
+				err := b.client.XClaimJustID(hbCtx, &goredis.XClaimArgs{
+					Stream:   stream,
+					Group:    b.group,
+					Consumer: consumer,
+					Messages: []string{id},
+				}).Err()
+				if err != nil && hbCtx.Err() == nil {
+					log.Warnf(hbCtx, "extend visibility of task message %s error: %s", id, err)
+				}
+			}
+		}
+	}()
+	return cancel
+}
+
+// 确认并删除消息
+func (b *redisBroker) ack(ctx context.Context, stream, id string) {
+	// 即便 worker 正在退出，也需要完成确认，否则消息会被重复执行
+	ctx = context.WithoutCancel(ctx)
+	_, err := b.client.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+		pipe.XAck(ctx, stream, b.group, id)
+		pipe.XDel(ctx, stream, id)
+		return nil
+	})
+	if err != nil {
+		log.Errorf(ctx, "ack task message %s error: %s", id, err)
+	}
+}
+
+// 将消息重新投递到队列尾部，并确认原消息
+func (b *redisBroker) requeue(ctx context.Context, stream, id, payload string) {
+	ctx = context.WithoutCancel(ctx)
+	_, err := b.client.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+		pipe.XAdd(ctx, &goredis.XAddArgs{Stream: stream, Values: map[string]any{redisPayloadField: payload}})
+		pipe.XAck(ctx, stream, b.group, id)
+		pipe.XDel(ctx, stream, id)
+		return nil
+	})
+	if err != nil {
+		// 重新投递失败时，消息仍在 PEL 中，超过可见性超时后会被重新认领
+		log.Errorf(ctx, "requeue task message %s error: %s", id, err)
+	}
+}
@@ -0,0 +1,201 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - Go 开发框架 (BlueKing - Go Framework) available.
+ * Copyright (C) 2017 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ *	https://opensource.org/licenses/MIT
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package async
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/pkg/errors"
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestRedisBroker(t *testing.T, visibilityTimeout time.Duration) *redisBroker {
+	mr := miniredis.RunT(t)
+	client := goredis.NewClient(&goredis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	return &redisBroker{
+		client:            client,
+		group:             redisConsumerGroup,
+		consumerPrefix:    "host-1",
+		visibilityTimeout: visibilityTimeout,
+	}
+}
+
+// 消费队列直到 handler 处理了 n 条消息
+func consumeN(t *testing.T, b *redisBroker, n int, handler Handler) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var wg sync.WaitGroup
+	wg.Add(n)
+	done := make(chan struct{})
+	go func() {
+		_ = b.Consume(ctx, DefaultQueue, func(ctx context.Context, msg *Message) error {
+			defer wg.Done()
+			return handler(ctx, msg)
+		})
+		close(done)
+	}()
+	wg.Wait()
+	cancel()
+	<-done
+}
+
+// 队列中（含未确认的）消息数量
+func streamLen(t *testing.T, b *redisBroker) int64 {
+	n, err := b.client.XLen(context.Background(), redisTaskStreamKey).Result()
+	assert.NoError(t, err)
+	return n
+}
+
+func TestRedisBrokerConsumerName(t *testing.T) {
+	b := newTestRedisBroker(t, time.Second)
+	assert.Equal(t, "host-1-1", b.newConsumer())
+	assert.Equal(t, "host-1-2", b.newConsumer())
+}
+
+func TestRedisBrokerAck(t *testing.T) {
+	b := newTestRedisBroker(t, time.Second)
+	ctx := context.Background()
+	assert.NoError(t, b.Publish(ctx, &Message{ID: "a", Name: "greet", Args: json.RawMessage(`{}`)}))
+	assert.EqualValues(t, 1, streamLen(t, b))
+
+	var handled []string
+	consumeN(t, b, 1, func(_ context.Context, msg *Message) error {
+		handled = append(handled, msg.ID)
+		return nil
+	})
+	assert.Equal(t, []string{"a"}, handled)
+	// 确认后消息从 Stream & PEL 中删除
+	assert.EqualValues(t, 0, streamLen(t, b))
+	pending, err := b.client.XPending(ctx, redisTaskStreamKey, redisConsumerGroup).Result()
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0, pending.Count)
+}
+
+func TestRedisBrokerRequeue(t *testing.T) {
+	b := newTestRedisBroker(t, time.Second)
+	assert.NoError(t, b.Publish(context.Background(), &Message{ID: "a", Name: "greet"}))
+
+	// 第一次处理失败，消息重新投递后再次处理
+	attempts := 0
+	consumeN(t, b, 2, func(context.Context, *Message) error {
+		attempts++
+		if attempts == 1 {
+			return errors.New("failed")
+		}
+		return nil
+	})
+	assert.Equal(t, 2, attempts)
+	assert.EqualValues(t, 0, streamLen(t, b))
+}
+
+func TestRedisBrokerRequeueExhausted(t *testing.T) {
+	b := newTestRedisBroker(t, time.Second)
+	var exhausted []*Message
+	b.onRequeueExhausted = func(_ context.Context, msg *Message, _ error) { exhausted = append(exhausted, msg) }
+	assert.NoError(t, b.Publish(context.Background(), &Message{ID: "a", Name: "greet"}))
+
+	// 一直处理失败的消息，超过最大重新投递次数后不再重新投递
+	var requeues []int
+	consumeN(t, b, redisMaxRequeueCount+1, func(_ context.Context, msg *Message) error {
+		requeues = append(requeues, msg.Requeues)
+		return errors.New("failed")
+	})
+	assert.Equal(t, []int{0, 1, 2, 3, 4, 5}, requeues)
+	assert.Len(t, exhausted, 1)
+	assert.Equal(t, "a", exhausted[0].ID)
+	assert.EqualValues(t, 0, streamLen(t, b))
+}
+
+func TestRedisBrokerClaim(t *testing.T) {
+	b := newTestRedisBroker(t, 200*time.Millisecond)
+	ctx := context.Background()
+	assert.NoError(t, b.ensureGroup(ctx, redisTaskStreamKey))
+	assert.NoError(t, b.Publish(ctx, &Message{ID: "a", Name: "greet"}))
+
+	// 模拟 worker 读取消息后崩溃：消息留在其 PEL 中，未超过可见性超时前不会被其他消费者认领
+	msgs, err := b.read(ctx, redisTaskStreamKey, "crashed")
+	assert.NoError(t, err)
+	assert.Len(t, msgs, 1)
+	claimed, _, err := b.client.XAutoClaim(ctx, &goredis.XAutoClaimArgs{
+		Stream: redisTaskStreamKey, Group: b.group, Consumer: "other", MinIdle: b.visibilityTimeout, Start: "0-0",
+	}).Result()
+	assert.NoError(t, err)
+	assert.Empty(t, claimed)
+
+	// 超过可见性超时后被重新认领并执行
+	time.Sleep(300 * time.Millisecond)
+	var handled []string
+	consumeN(t, b, 1, func(_ context.Context, msg *Message) error {
+		handled = append(handled, msg.ID)
+		return nil
+	})
+	assert.Equal(t, []string{"a"}, handled)
+	assert.EqualValues(t, 0, streamLen(t, b))
+}
+
+func TestRedisBrokerHeartbeat(t *testing.T) {
+	b := newTestRedisBroker(t, 300*time.Millisecond)
+	ctx := context.Background()
+	assert.NoError(t, b.Publish(ctx, &Message{ID: "a", Name: "greet"}))
+
+	// 执行时间超过可见性超时，但执行期间持续续期，不会被其他消费者认领
+	var stolen []goredis.XMessage
+	consumeN(t, b, 1, func(ctx context.Context, _ *Message) error {
+		for range 5 {
+			time.Sleep(200 * time.Millisecond)
+			claimed, _, err := b.client.XAutoClaim(ctx, &goredis.XAutoClaimArgs{
+				Stream: redisTaskStreamKey, Group: b.group, Consumer: "other", MinIdle: b.visibilityTimeout, Start: "0-0",
+			}).Result()
+			assert.NoError(t, err)
+			stolen = append(stolen, claimed...)
+		}
+		return nil
+	})
+	assert.Empty(t, stolen)
+	assert.EqualValues(t, 0, streamLen(t, b))
+}
+
+func TestRedisBrokerDelayed(t *testing.T) {
+	b := newTestRedisBroker(t, time.Second)
+	ctx := context.Background()
+	assert.NoError(t, b.Publish(ctx, &Message{ID: "a", Name: "greet", ETA: time.Now().Add(200 * time.Millisecond)}))
+
+	// 到期前仍在延迟队列中
+	assert.NoError(t, b.promote(ctx, DefaultQueue))
+	assert.EqualValues(t, 0, streamLen(t, b))
+	delayed, err := b.client.ZCard(ctx, redisDelayedSetKey(DefaultQueue)).Result()
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, delayed)
+
+	// 到期后转移到队列中
+	time.Sleep(300 * time.Millisecond)
+	assert.NoError(t, b.promote(ctx, DefaultQueue))
+	assert.EqualValues(t, 1, streamLen(t, b))
+	delayed, err = b.client.ZCard(ctx, redisDelayedSetKey(DefaultQueue)).Result()
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0, delayed)
+}
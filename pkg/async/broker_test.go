@@ -0,0 +1,50 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - Go 开发框架 (BlueKing - Go Framework) available.
+ * Copyright (C) 2017 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ *	https://opensource.org/licenses/MIT
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package async
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/TencentBlueKing/blueapps-go/pkg/config"
+)
+
+func TestLocalBrokerDelayedSubmitFailed(t *testing.T) {
+	b := newLocalBroker(&config.AsyncConfig{Concurrency: 1}, func(context.Context, *Message) error { return nil })
+	failed := make(chan error, 1)
+	b.onSubmitFailed = func(_ context.Context, msg *Message, err error) {
+		assert.Equal(t, "a", msg.ID)
+		failed <- err
+	}
+
+	// 延迟任务到期时 worker pool 已关闭，无法提交，需要按执行失败处理
+	msg := &Message{ID: "a", TaskID: 1, ETA: time.Now().Add(50 * time.Millisecond)}
+	assert.NoError(t, b.Publish(context.Background(), msg))
+	b.pool.stop()
+
+	select {
+	case err := <-failed:
+		assert.ErrorIs(t, err, ErrPoolClosed)
+	case <-time.After(time.Second):
+		t.Fatal("delayed submit failure not handled")
+	}
+}
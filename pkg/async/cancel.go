@@ -0,0 +1,146 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - Go 开发框架 (BlueKing - Go Framework) available.
+ * Copyright (C) 2017 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ *	https://opensource.org/licenses/MIT
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package async
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/TencentBlueKing/blueapps-go/pkg/infras/database"
+	log "github.com/TencentBlueKing/blueapps-go/pkg/logging"
+	"github.com/TencentBlueKing/blueapps-go/pkg/model"
+)
+
+var (
+	// ErrTaskCancelled 任务被取消（context.Cause）
+	ErrTaskCancelled = errors.New("task cancelled")
+	// ErrTaskTimeout 任务执行超时（context.Cause）
+	ErrTaskTimeout = errors.New("task timeout")
+)
+
+// 检查任务是否在其他进程中被取消的时间间隔
+const cancelCheckInterval = 3 * time.Second
+
+// 当前进程中正在执行的任务：任务 ID -> 取消函数
+var runningTasks sync.Map
+
+// CancelTask 取消任务：等待执行（含等待重试）的任务不会再被执行，执行中的任务的 context 会被取消
+// 注：任务函数需要响应 ctx.Done()，否则任务函数仍会在后台运行至结束，但其结果不会被记录
+// 工作流节点被取消时，会级联取消其未结束的子节点，并推进其所属的工作流
+func CancelTask(ctx context.Context, taskID int64, operator string) error {
+	pending, err := cancelTask(ctx, taskID, operator)
+	if err != nil {
+		return err
+	}
+
+	var task model.Task
+	if err = database.Client(ctx).Select("id", "kind", "parent_id", "name", "args").First(&task, taskID).Error; err != nil {
+		return err
+	}
+	// 等待执行的任务被取消后不会再执行，需要立即释放任务锁；执行中的任务函数可能未响应取消仍在运行，
+	// 由执行任务的进程在结束时释放，避免相同参数的任务在此期间被下发执行
+	if pending {
+		releaseUniqueLock(ctx, taskID, task.Name, task.Args)
+	}
+	// 单个任务由执行任务的进程（或下发时）推进工作流
+	if task.Kind != model.TaskKindTask && task.ParentID != 0 {
+		advanceWorkflow(ctx, taskID)
+	}
+	return nil
+}
+
+// 取消任务，并级联取消未结束的子节点（不推进工作流），pending 表示任务被取消前是否为等待执行（未被 worker 执行）
+func cancelTask(ctx context.Context, taskID int64, operator string) (pending bool, err error) {
+	values := finishedValues(time.Time{})
+	values["error"] = ErrTaskCancelled.Error()
+	values["updater"] = operator
+	// 先按等待执行的任务取消，失败再按执行中的任务取消，以区分取消前的状态（每次流转均为原子操作）
+	err = transitTaskFrom(
+		database.Client(ctx), taskID, []model.TaskStatus{model.TaskStatusPending}, model.TaskStatusCancelled, values,
+	)
+	pending = err == nil
+	if errors.Is(err, ErrInvalidTransition) {
+		err = transitTask(database.Client(ctx), taskID, model.TaskStatusCancelled, values)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	// 任务在当前进程中执行则立即取消，否则由执行任务的进程轮询感知（watchCancel）
+	if cancel, ok := runningTasks.Load(taskID); ok {
+		cancel.(context.CancelCauseFunc)(ErrTaskCancelled)
+	}
+
+	var children []model.Task
+	if err := database.Client(ctx).
+		Select("id").
+		Where("parent_id = ? AND status IN ?", taskID, allowedTransitions[model.TaskStatusCancelled]).
+		Find(&children).Error; err != nil {
+		return pending, err
+	}
+	for _, child := range children {
+		if _, err = cancelTask(ctx, child.ID, operator); err != nil && !errors.Is(err, ErrInvalidTransition) {
+			return pending, err
+		}
+	}
+	return pending, nil
+}
+
+// 登记执行中的任务，返回注销函数
+func registerRunningTask(taskID int64, cancel context.CancelCauseFunc) (unregister func()) {
+	runningTasks.Store(taskID, cancel)
+	return func() {
+		runningTasks.Delete(taskID)
+	}
+}
+
+// 定期检查任务是否已被取消（如在 webserver 进程中取消 worker 进程中执行的任务），是则取消任务的 context
+func watchCancel(ctx context.Context, taskID int64, cancel context.CancelCauseFunc) (stop func()) {
+	watchCtx, stopWatch := context.WithCancel(ctx)
+	go func() {
+		ticker := time.NewTicker(cancelCheckInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-watchCtx.Done():
+				return
+			case <-ticker.C:
+				var count int64
+				err := database.Client(watchCtx).
+					Model(&model.Task{}).
+					Where("id = ? AND status = ?", taskID, model.TaskStatusCancelled).
+					Count(&count).Error
+				if err != nil {
+					if watchCtx.Err() == nil {
+						log.Warnf(watchCtx, "check task %d cancelled error: %s", taskID, err)
+					}
+					continue
+				}
+				if count != 0 {
+					cancel(ErrTaskCancelled)
+					return
+				}
+			}
+		}
+	}()
+	return stopWatch
+}
@@ -0,0 +1,77 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - Go 开发框架 (BlueKing - Go Framework) available.
+ * Copyright (C) 2017 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ *	https://opensource.org/licenses/MIT
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package async
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type sleepArgs struct {
+	Duration time.Duration `json:"duration"`
+}
+
+func sleepTask(ctx context.Context, args sleepArgs) (string, error) {
+	select {
+	case <-time.After(args.Duration):
+		return "done", nil
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+func TestRun(t *testing.T) {
+	Register("sleepTask", sleepTask)
+	defer unregister("sleepTask")
+
+	newSleepMsg := func(taskID int64, sleep, timeout time.Duration) *Message {
+		args, _ := json.Marshal(sleepArgs{Duration: sleep})
+		msg := newMessage(context.Background(), taskID, "sleepTask", args)
+		msg.Timeout = timeout
+		return msg
+	}
+
+	t.Run("succeeded", func(t *testing.T) {
+		result, err := run(context.Background(), newSleepMsg(1, 10*time.Millisecond, time.Second))
+		assert.NoError(t, err)
+		assert.Equal(t, "done", result)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		_, err := run(context.Background(), newSleepMsg(2, time.Second, 10*time.Millisecond))
+		assert.ErrorIs(t, err, ErrTaskTimeout)
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		time.AfterFunc(10*time.Millisecond, func() {
+			cancel, ok := runningTasks.Load(int64(3))
+			assert.True(t, ok)
+			cancel.(context.CancelCauseFunc)(ErrTaskCancelled)
+		})
+		_, err := run(context.Background(), newSleepMsg(3, time.Second, 0))
+		assert.ErrorIs(t, err, ErrTaskCancelled)
+
+		_, ok := runningTasks.Load(int64(3))
+		assert.False(t, ok)
+	})
+}
@@ -0,0 +1,335 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - Go 开发框架 (BlueKing - Go Framework) available.
+ * Copyright (C) 2017 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ *	https://opensource.org/licenses/MIT
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package async
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/samber/lo"
+
+	"github.com/TencentBlueKing/blueapps-go/pkg/i18n"
+	"github.com/TencentBlueKing/blueapps-go/pkg/model"
+)
+
+// 计算 cron 表达式最小触发间隔时采样的触发次数
+const cronIntervalSamples = 100
+
+// CronPreview cron 表达式预览
+type CronPreview struct {
+	// 接下来的触发时间（位于 cron 表达式的时区）
+	NextRunAt []time.Time
+	// 接下来若干次触发（至多 100 次）间的最小间隔，触发次数不足 2 次时为 0
+	MinInterval time.Duration
+}
+
+// PreviewCron 预览 cron 表达式在指定时区（为空则为 scheduler 所在机器时区）中，after 之后的 count 次触发时间及最小触发间隔
+// 注：与周期任务使用相同的解析器（支持秒级字段及 @daily 等描述符），不会触发的表达式（如 2 月 30 日）返回空列表
+func PreviewCron(expr, timezone string, count int, after time.Time) (*CronPreview, error) {
+	schedule, err := ParseSchedule(&model.PeriodicTask{Cron: expr, Timezone: timezone})
+	if err != nil {
+		return nil, err
+	}
+	// 触发时间按 after 所在时区返回，因此需先转换到表达式的时区
+	if timezone != "" {
+		loc, _ := time.LoadLocation(timezone)
+		after = after.In(loc)
+	}
+
+	preview := &CronPreview{NextRunAt: []time.Time{}}
+	prev := time.Time{}
+	for i, next := 0, schedule.Next(after); i < max(count, cronIntervalSamples) && !next.IsZero(); i++ {
+		if i < count {
+			preview.NextRunAt = append(preview.NextRunAt, next)
+		}
+		if interval := next.Sub(prev); !prev.IsZero() && (preview.MinInterval == 0 || interval < preview.MinInterval) {
+			preview.MinInterval = interval
+		}
+		prev, next = next, schedule.Next(next)
+	}
+	return preview, nil
+}
+
+// cron 描述符对应的表达式
+var cronDescriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// 月份 & 星期的英文缩写（cron 表达式中可代替数字使用）
+var (
+	cronMonthNames   = []string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}
+	cronWeekdayNames = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+)
+
+// DescribeCron 生成 cron 表达式的自然语言描述（按 ctx 中的语言国际化），如 "0 8 * * 1-5" -> "At 08:00, only on Monday through Friday"
+// 描述由时间、日期、星期、月份等子句组成，未覆盖的写法（如带起始值的步长）会保留原始的字段值
+func DescribeCron(ctx context.Context, expr string) (string, error) {
+	if _, err := cronParser.Parse(expr); err != nil {
+		return "", err
+	}
+	fields := strings.Fields(expr)
+	// 时区前缀不影响描述（时区由调用方单独展示）
+	if strings.HasPrefix(fields[0], "TZ=") || strings.HasPrefix(fields[0], "CRON_TZ=") {
+		fields = fields[1:]
+	}
+	if strings.HasPrefix(fields[0], "@") {
+		if spec, ok := cronDescriptors[fields[0]]; ok {
+			return DescribeCron(ctx, spec)
+		}
+		// @every <duration>
+		interval, _ := time.ParseDuration(fields[1])
+		return upperFirst(fmt.Sprintf(i18n.T(ctx, "every %s"), interval)), nil
+	}
+	// 不含秒级字段时，在整分触发
+	if len(fields) == 5 {
+		fields = append([]string{"0"}, fields...)
+	}
+	second, minute, hour, dom, month, dow := fields[0], fields[1], fields[2], fields[3], fields[4], fields[5]
+
+	clauses := []string{describeCronTime(ctx, second, minute, hour)}
+	clauses = append(clauses, describeCronDayOfMonth(ctx, dom), describeCronDayOfWeek(ctx, dow), describeCronMonth(ctx, month))
+	clauses = lo.Compact(clauses)
+	return upperFirst(strings.Join(clauses, lo.Ternary(i18n.GetLangFromContext(ctx) == i18n.LangZH, "，", ", "))), nil
+}
+
+// 描述触发的时间（秒 / 分 / 时）
+func describeCronTime(ctx context.Context, second, minute, hour string) string {
+	// 固定的时刻，如 08:00 / 08:00、20:00
+	hours := strings.Split(hour, ",")
+	if isCronNumber(second) && isCronNumber(minute) && lo.EveryBy(hours, isCronNumber) {
+		times := lo.Map(hours, func(h string, _ int) string {
+			t := fmt.Sprintf("%02d:%02d", atoi(h), atoi(minute))
+			return lo.Ternary(second == "0", t, fmt.Sprintf("%s:%02d", t, atoi(second)))
+		})
+		return fmt.Sprintf(i18n.T(ctx, "at %s"), joinCronValues(ctx, times))
+	}
+
+	parts := []string{}
+	if second != "0" {
+		parts = append(parts, describeCronUnit(ctx, second, cronUnitSecond))
+	}
+	// 按秒触发时，“每分钟”是多余的
+	if minute != "*" || second == "0" {
+		parts = append(parts, describeCronUnit(ctx, minute, cronUnitMinute))
+	}
+	switch {
+	case isCronAny(hour):
+	case strings.HasPrefix(hour, "*/"):
+		parts = append(parts, fmt.Sprintf(i18n.T(ctx, "every %d hours"), atoi(hour[2:])))
+	case isCronNumber(hour):
+		parts = append(parts, fmt.Sprintf(i18n.T(ctx, "between %02d:00 and %02d:59"), atoi(hour), atoi(hour)))
+	default:
+		if from, to, ok := cronRange(hour); ok {
+			parts = append(parts, fmt.Sprintf(i18n.T(ctx, "between %02d:00 and %02d:59"), atoi(from), atoi(to)))
+		} else {
+			parts = append(parts, fmt.Sprintf(i18n.T(ctx, "during hours %s"), hour))
+		}
+	}
+	return strings.Join(parts, lo.Ternary(i18n.GetLangFromContext(ctx) == i18n.LangZH, "，", ", "))
+}
+
+type cronUnit int
+
+const (
+	cronUnitSecond cronUnit = iota
+	cronUnitMinute
+)
+
+// 描述秒 / 分字段
+func describeCronUnit(ctx context.Context, field string, unit cronUnit) string {
+	from, to, isRange := cronRange(field)
+	switch {
+	case isCronAny(field):
+		return lo.Ternary(unit == cronUnitSecond, i18n.T(ctx, "every second"), i18n.T(ctx, "every minute"))
+	case strings.HasPrefix(field, "*/"):
+		step := atoi(field[2:])
+		if unit == cronUnitSecond {
+			return fmt.Sprintf(i18n.T(ctx, "every %d seconds"), step)
+		}
+		return fmt.Sprintf(i18n.T(ctx, "every %d minutes"), step)
+	case isRange:
+		if unit == cronUnitSecond {
+			return fmt.Sprintf(i18n.T(ctx, "seconds %s through %s past the minute"), from, to)
+		}
+		return fmt.Sprintf(i18n.T(ctx, "minutes %s through %s past the hour"), from, to)
+	default:
+		if unit == cronUnitSecond {
+			return fmt.Sprintf(i18n.T(ctx, "at %s seconds past the minute"), field)
+		}
+		return fmt.Sprintf(i18n.T(ctx, "at %s minutes past the hour"), field)
+	}
+}
+
+// 描述日期字段
+func describeCronDayOfMonth(ctx context.Context, dom string) string {
+	if isCronAny(dom) {
+		return ""
+	}
+	if strings.HasPrefix(dom, "*/") {
+		return fmt.Sprintf(i18n.T(ctx, "every %d days"), atoi(dom[2:]))
+	}
+	if from, to, ok := cronRange(dom); ok {
+		return fmt.Sprintf(i18n.T(ctx, "between day %s and %s of the month"), from, to)
+	}
+	return fmt.Sprintf(i18n.T(ctx, "on day %s of the month"), dom)
+}
+
+// 描述星期字段
+func describeCronDayOfWeek(ctx context.Context, dow string) string {
+	if isCronAny(dow) {
+		return ""
+	}
+	return fmt.Sprintf(i18n.T(ctx, "only on %s"), describeCronNames(ctx, dow, cronWeekdayNames, 0, weekdayName))
+}
+
+// 描述月份字段
+func describeCronMonth(ctx context.Context, month string) string {
+	if isCronAny(month) {
+		return ""
+	}
+	if strings.HasPrefix(month, "*/") {
+		return fmt.Sprintf(i18n.T(ctx, "every %d months"), atoi(month[2:]))
+	}
+	return fmt.Sprintf(i18n.T(ctx, "only in %s"), describeCronNames(ctx, month, cronMonthNames, 1, monthName))
+}
+
+// 将月份 / 星期字段中的值（数字或英文缩写）转换为名称，如 "1-5" -> "Monday through Friday"，带步长的写法保留原始值
+func describeCronNames(
+	ctx context.Context, field string, abbrs []string, base int, name func(context.Context, int) string,
+) string {
+	if strings.Contains(field, "/") {
+		return field
+	}
+	toName := func(value string) string {
+		if idx := lo.IndexOf(abbrs, strings.ToLower(value)); idx != -1 {
+			return name(ctx, idx+base)
+		}
+		return name(ctx, atoi(value))
+	}
+	items := lo.Map(strings.Split(field, ","), func(item string, _ int) string {
+		if from, to, ok := cronRange(item); ok {
+			return fmt.Sprintf(i18n.T(ctx, "%s through %s"), toName(from), toName(to))
+		}
+		return toName(item)
+	})
+	return joinCronValues(ctx, items)
+}
+
+// 字段是否为任意值
+func isCronAny(field string) bool {
+	return field == "*" || field == "?"
+}
+
+// 字段是否为单个数字
+func isCronNumber(field string) bool {
+	_, err := strconv.Atoi(field)
+	return err == nil
+}
+
+// 字段是否为单个范围（a-b），是则返回起止值
+func cronRange(field string) (from, to string, ok bool) {
+	if strings.ContainsAny(field, ",/") {
+		return "", "", false
+	}
+	from, to, ok = strings.Cut(field, "-")
+	return from, to, ok
+}
+
+// 解析数字（表达式已校验过，不会失败）
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+// 拼接多个值，如 "Monday, Friday" / "星期一、星期五"
+func joinCronValues(ctx context.Context, values []string) string {
+	return strings.Join(values, lo.Ternary(i18n.GetLangFromContext(ctx) == i18n.LangZH, "、", ", "))
+}
+
+// 首字母大写（中文不受影响）
+func upperFirst(s string) string {
+	runes := []rune(s)
+	if len(runes) == 0 {
+		return s
+	}
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
+
+// 月份名称（1-12）
+func monthName(ctx context.Context, month int) string {
+	switch month {
+	case 1:
+		return i18n.T(ctx, "January")
+	case 2:
+		return i18n.T(ctx, "February")
+	case 3:
+		return i18n.T(ctx, "March")
+	case 4:
+		return i18n.T(ctx, "April")
+	case 5:
+		return i18n.T(ctx, "May")
+	case 6:
+		return i18n.T(ctx, "June")
+	case 7:
+		return i18n.T(ctx, "July")
+	case 8:
+		return i18n.T(ctx, "August")
+	case 9:
+		return i18n.T(ctx, "September")
+	case 10:
+		return i18n.T(ctx, "October")
+	case 11:
+		return i18n.T(ctx, "November")
+	case 12:
+		return i18n.T(ctx, "December")
+	}
+	return strconv.Itoa(month)
+}
+
+// 星期名称（0-6，0 为星期日）
+func weekdayName(ctx context.Context, weekday int) string {
+	switch weekday {
+	case 0:
+		return i18n.T(ctx, "Sunday")
+	case 1:
+		return i18n.T(ctx, "Monday")
+	case 2:
+		return i18n.T(ctx, "Tuesday")
+	case 3:
+		return i18n.T(ctx, "Wednesday")
+	case 4:
+		return i18n.T(ctx, "Thursday")
+	case 5:
+		return i18n.T(ctx, "Friday")
+	case 6:
+		return i18n.T(ctx, "Saturday")
+	}
+	return strconv.Itoa(weekday)
+}
@@ -0,0 +1,94 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - Go 开发框架 (BlueKing - Go Framework) available.
+ * Copyright (C) 2017 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ *	https://opensource.org/licenses/MIT
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package async
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPreviewCron(t *testing.T) {
+	after := time.Date(2026, 10, 17, 8, 0, 0, 0, time.UTC)
+
+	preview, err := PreviewCron("*/15 8-9 * * *", "UTC", 3, after)
+	assert.NoError(t, err)
+	assert.Equal(t, []time.Time{
+		after.Add(15 * time.Minute), after.Add(30 * time.Minute), after.Add(45 * time.Minute),
+	}, preview.NextRunAt)
+	assert.Equal(t, 15*time.Minute, preview.MinInterval)
+
+	// 秒级字段
+	preview, err = PreviewCron("*/10 * * * * *", "", 1, after)
+	assert.NoError(t, err)
+	assert.Len(t, preview.NextRunAt, 1)
+	assert.Equal(t, 10*time.Second, preview.MinInterval)
+
+	// 时区
+	preview, err = PreviewCron("0 9 * * *", "Asia/Shanghai", 1, after)
+	assert.NoError(t, err)
+	assert.Equal(t, "2026-10-18T09:00:00+08:00", preview.NextRunAt[0].Format(time.RFC3339))
+	assert.Equal(t, 24*time.Hour, preview.MinInterval)
+
+	// 不会触发
+	preview, err = PreviewCron("0 0 30 2 *", "", 5, after)
+	assert.NoError(t, err)
+	assert.Empty(t, preview.NextRunAt)
+	assert.Zero(t, preview.MinInterval)
+
+	_, err = PreviewCron("* * *", "", 5, after)
+	assert.Error(t, err)
+	_, err = PreviewCron("* * * * *", "Mars/Olympus", 5, after)
+	assert.Error(t, err)
+}
+
+func TestDescribeCron(t *testing.T) {
+	ctx := context.Background()
+
+	for expr, expected := range map[string]string{
+		"* * * * *":             "Every minute",
+		"*/5 * * * *":           "Every 5 minutes",
+		"*/10 * * * * *":        "Every 10 seconds",
+		"30 * * * * *":          "At 30 seconds past the minute",
+		"0 8 * * *":             "At 08:00",
+		"30 0 8,20 * * *":       "At 08:00:30, 20:00:30",
+		"0 8 * * 1-5":           "At 08:00, only on Monday through Friday",
+		"0 9 * * SAT,sun":       "At 09:00, only on Saturday, Sunday",
+		"*/5 8 * * *":           "Every 5 minutes, between 08:00 and 08:59",
+		"0 9-18 * * *":          "At 0 minutes past the hour, between 09:00 and 18:59",
+		"10-20 */2 * * *":       "Minutes 10 through 20 past the hour, every 2 hours",
+		"0 0 1,15 * *":          "At 00:00, on day 1,15 of the month",
+		"0 0 1-7 jan-mar *":     "At 00:00, between day 1 and 7 of the month, only in January through March",
+		"0 0 */2 */3 *":         "At 00:00, every 2 days, every 3 months",
+		"0 0,12 * * 1/2":        "At 00:00, 12:00, only on 1/2",
+		"@daily":                "At 00:00",
+		"@yearly":               "At 00:00, on day 1 of the month, only in January",
+		"@every 1h30m":          "Every 1h30m0s",
+		"CRON_TZ=UTC 0 8 * * *": "At 08:00",
+	} {
+		desc, err := DescribeCron(ctx, expr)
+		assert.NoError(t, err, expr)
+		assert.Equal(t, expected, desc, expr)
+	}
+
+	_, err := DescribeCron(ctx, "61 * * * *")
+	assert.Error(t, err)
+}
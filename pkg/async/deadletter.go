@@ -0,0 +1,145 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - Go 开发框架 (BlueKing - Go Framework) available.
+ * Copyright (C) 2017 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ *	https://opensource.org/licenses/MIT
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package async
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+
+	"github.com/TencentBlueKing/blueapps-go/pkg/infras/database"
+	log "github.com/TencentBlueKing/blueapps-go/pkg/logging"
+	"github.com/TencentBlueKing/blueapps-go/pkg/model"
+)
+
+// 记录死信：任务最终执行失败（含超时）或投递失败时，保存任务参数、错误、调用栈及执行记录等快照，
+// 以便排查问题后修正参数重新下发；记录失败仅打印日志，不影响任务状态流转
+func recordDeadLetter(ctx context.Context, msg *Message, status model.TaskStatus, taskErr error) {
+	var task model.Task
+	if err := database.Client(ctx).Select("id", "creator", "attempts").First(&task, msg.TaskID).Error; err != nil {
+		log.Errorf(ctx, "failed to get task %d for dead letter: %s", msg.TaskID, err)
+		return
+	}
+	var attempts []model.TaskAttempt
+	if err := database.Client(ctx).Where("task_id = ?", msg.TaskID).Order("attempt").Find(&attempts).Error; err != nil {
+		log.Errorf(ctx, "failed to get task %d attempts for dead letter: %s", msg.TaskID, err)
+		return
+	}
+	history, err := json.Marshal(attempts)
+	if err != nil {
+		log.Errorf(ctx, "failed to marshal task %d attempts for dead letter: %s", msg.TaskID, err)
+		return
+	}
+
+	letter := model.DeadLetter{
+		BaseModel:      model.BaseModel{Creator: task.Creator},
+		TaskID:         msg.TaskID,
+		Name:           msg.Name,
+		Args:           []byte(msg.Args),
+		Queue:          msg.Queue,
+		Status:         status,
+		Error:          errorString(taskErr),
+		Stack:          errorStack(taskErr),
+		Attempts:       task.Attempts,
+		AttemptHistory: history,
+		RequestID:      msg.RequestID,
+		TraceID:        traceID(ctx),
+		FailedAt:       time.Now(),
+	}
+	if err = database.Client(ctx).Create(&letter).Error; err != nil {
+		log.Errorf(ctx, "failed to record task %s (id: %d) dead letter: %s", msg.Name, msg.TaskID, err)
+		return
+	}
+	log.Warnf(ctx, "task %s (id: %d) %s, recorded as dead letter %d", msg.Name, msg.TaskID, status, letter.ID)
+}
+
+// 错误信息，nil 为空字符串
+func errorString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+// 任务函数 panic 时的调用栈，其他错误为空字符串
+func errorStack(err error) string {
+	var stackErr interface{ Stack() string }
+	if errors.As(err, &stackErr) {
+		return stackErr.Stack()
+	}
+	return ""
+}
+
+// 当前 span 的 trace ID，无有效 span 时为空字符串
+func traceID(ctx context.Context) string {
+	spanCtx := trace.SpanContextFromContext(ctx)
+	if !spanCtx.HasTraceID() {
+		return ""
+	}
+	return spanCtx.TraceID().String()
+}
+
+// ReplayDeadLetter 重新下发死信中的任务，返回新任务的 ID；args 不为空（且不为 null）时使用修正后的参数，否则使用原参数
+// 注：新任务投递到原任务所在的队列，原死信记录保留，并记录重新下发的次数 & 最近一次下发的任务 ID
+func ReplayDeadLetter(ctx context.Context, id int64, args json.RawMessage, operator string) (int64, error) {
+	var letter model.DeadLetter
+	if err := database.Client(ctx).First(&letter, id).Error; err != nil {
+		return 0, errors.Wrapf(err, "get dead letter %d", id)
+	}
+	args = replayArgs(args, letter.Args)
+	opts := []TaskOption{WithCreator(operator)}
+	if letter.Queue != "" {
+		opts = append(opts, WithQueue(letter.Queue))
+	}
+	taskID, err := ApplyTask(ctx, letter.Name, args, opts...)
+	if err != nil {
+		return taskID, err
+	}
+
+	err = database.Client(ctx).Model(&letter).Updates(map[string]any{
+		"replay_count":     gorm.Expr("replay_count + 1"),
+		"replayed_task_id": taskID,
+		"replayed_at":      time.Now(),
+		"updater":          operator,
+	}).Error
+	if err != nil {
+		// 任务已下发，记录失败仅打印日志
+		log.Errorf(ctx, "failed to record dead letter %d replayed (task id: %d): %s", id, taskID, err)
+	}
+	return taskID, nil
+}
+
+// ReplayArgsOmitted 重新下发死信时是否未指定修正后的参数（为空或 JSON null，如请求体中 "args": null），未指定时使用原参数
+func ReplayArgsOmitted(args json.RawMessage) bool {
+	trimmed := bytes.TrimSpace(args)
+	return len(trimmed) == 0 || bytes.Equal(trimmed, []byte("null"))
+}
+
+// 重新下发时使用的参数：未指定时使用原参数
+func replayArgs(args json.RawMessage, original []byte) json.RawMessage {
+	if ReplayArgsOmitted(args) {
+		return json.RawMessage(original)
+	}
+	return args
+}
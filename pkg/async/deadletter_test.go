@@ -0,0 +1,69 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - Go 开发框架 (BlueKing - Go Framework) available.
+ * Copyright (C) 2017 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ *	https://opensource.org/licenses/MIT
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package async
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+type stackError struct {
+	stack string
+}
+
+func (e *stackError) Error() string {
+	return "panic"
+}
+
+func (e *stackError) Stack() string {
+	return e.stack
+}
+
+func TestErrorStack(t *testing.T) {
+	assert.Empty(t, errorStack(nil))
+	assert.Empty(t, errorStack(errors.New("boom")))
+	// 被包装的错误也能取到调用栈
+	err := errors.Wrap(&stackError{stack: "goroutine 1 [running]"}, "run task")
+	assert.Equal(t, "goroutine 1 [running]", errorStack(err))
+}
+
+func TestTraceID(t *testing.T) {
+	assert.Empty(t, traceID(context.Background()))
+
+	provider := sdktrace.NewTracerProvider()
+	ctx, span := provider.Tracer("test").Start(context.Background(), "run")
+	defer span.End()
+	assert.Equal(t, span.SpanContext().TraceID().String(), traceID(ctx))
+}
+
+func TestReplayArgs(t *testing.T) {
+	original := []byte(`{"n":1}`)
+	// 未指定参数时使用原参数
+	for _, args := range []string{"", "null", " null\n"} {
+		assert.Equal(t, json.RawMessage(original), replayArgs(json.RawMessage(args), original), args)
+	}
+	assert.Equal(t, json.RawMessage(original), replayArgs(nil, original))
+	// 使用修正后的参数
+	assert.Equal(t, json.RawMessage(`{"n":2}`), replayArgs(json.RawMessage(`{"n":2}`), original))
+}
@@ -0,0 +1,68 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - Go 开发框架 (BlueKing - Go Framework) available.
+ * Copyright (C) 2017 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ *	https://opensource.org/licenses/MIT
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package async
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm/clause"
+
+	"github.com/TencentBlueKing/blueapps-go/pkg/infras/database"
+	log "github.com/TencentBlueKing/blueapps-go/pkg/logging"
+	"github.com/TencentBlueKing/blueapps-go/pkg/model"
+)
+
+// 抢占本次触发：写入触发记录（run 指定周期任务 ID、计划触发时间及是否为补跑 / 手动触发），
+// 返回 false 表示已被抢占（如其他 scheduler 副本已触发）
+func claimFiring(ctx context.Context, run *model.PeriodicTaskRun) (bool, error) {
+	run.StartedAt = time.Now()
+	run.Status = model.PeriodicTaskRunStatusFiring
+	tx := database.Client(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(run)
+	if tx.Error != nil {
+		return false, tx.Error
+	}
+	return tx.RowsAffected != 0, nil
+}
+
+// 记录触发结果，记录失败仅打印日志，不影响任务下发
+func finishRun(
+	ctx context.Context, run *model.PeriodicTaskRun, status model.PeriodicTaskRunStatus, taskID int64, err error,
+) {
+	values := map[string]any{"status": status}
+	if taskID != 0 {
+		values["task_id"] = taskID
+	}
+	if err != nil {
+		values["error"] = err.Error()
+	}
+	if dbErr := database.Client(ctx).Model(run).Updates(values).Error; dbErr != nil {
+		log.Errorf(ctx, "failed to record periodic task %d run (id: %d): %s", run.PeriodicTaskID, run.ID, dbErr)
+	}
+}
+
+// PeriodicTaskRunOutcome 周期任务触发的最终结果：已下发的以关联任务的状态为准，否则为触发状态（skipped / failed 等）
+func PeriodicTaskRunOutcome(run *model.PeriodicTaskRun, taskStatuses map[int64]model.TaskStatus) string {
+	if run.Status == model.PeriodicTaskRunStatusApplied {
+		if status, ok := taskStatuses[run.TaskID]; ok {
+			return string(status)
+		}
+	}
+	return string(run.Status)
+}
@@ -0,0 +1,43 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - Go 开发框架 (BlueKing - Go Framework) available.
+ * Copyright (C) 2017 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ *	https://opensource.org/licenses/MIT
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package async
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/TencentBlueKing/blueapps-go/pkg/model"
+)
+
+func TestPeriodicTaskRunOutcome(t *testing.T) {
+	taskStatuses := map[int64]model.TaskStatus{1: model.TaskStatusSucceeded}
+
+	run := model.PeriodicTaskRun{TaskID: 1, Status: model.PeriodicTaskRunStatusApplied}
+	assert.Equal(t, "succeeded", PeriodicTaskRunOutcome(&run, taskStatuses))
+
+	run = model.PeriodicTaskRun{TaskID: 2, Status: model.PeriodicTaskRunStatusApplied}
+	assert.Equal(t, "applied", PeriodicTaskRunOutcome(&run, taskStatuses))
+
+	run = model.PeriodicTaskRun{TaskID: 1, Status: model.PeriodicTaskRunStatusFailed}
+	assert.Equal(t, "failed", PeriodicTaskRunOutcome(&run, taskStatuses))
+
+	run = model.PeriodicTaskRun{Status: model.PeriodicTaskRunStatusSkipped}
+	assert.Equal(t, "skipped", PeriodicTaskRunOutcome(&run, taskStatuses))
+}
@@ -0,0 +1,168 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - Go 开发框架 (BlueKing - Go Framework) available.
+ * Copyright (C) 2017 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ *	https://opensource.org/licenses/MIT
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package async
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	goredis "github.com/redis/go-redis/v9"
+
+	"github.com/TencentBlueKing/blueapps-go/pkg/infras/database"
+	"github.com/TencentBlueKing/blueapps-go/pkg/infras/redis"
+	"github.com/TencentBlueKing/blueapps-go/pkg/utils/uuidx"
+)
+
+const (
+	// scheduler 选主使用的 Redis key / MySQL 锁名称
+	schedulerLeaderKey = "blueapps-go:async:scheduler:leader"
+	// 默认的选主租约时长
+	defaultSchedulerLeaseTTL = 15 * time.Second
+)
+
+// 续期租约（仅持有者可续期）
+var redisRenewLeaseScript = goredis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+// 释放租约（仅持有者可释放）
+var redisReleaseLeaseScript = goredis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// 选主器：多个 scheduler 副本中只有一个（leader）调度周期任务，其他副本（standby）空闲等待接管
+type elector interface {
+	// campaign 竞选或续期 leader，返回当前副本是否为 leader
+	campaign(ctx context.Context) (bool, error)
+	// resign 主动放弃 leader，便于其他副本尽快接管
+	resign(ctx context.Context)
+}
+
+// 创建选主器：优先使用 Redis 租约，未启用 Redis 增强服务时退化为 MySQL 咨询锁（GET_LOCK）
+func newElector(leaseTTL time.Duration) elector {
+	if redisEnabled() {
+		hostname, _ := os.Hostname()
+		return &redisElector{
+			client:   redis.Client(),
+			key:      schedulerLeaderKey,
+			identity: fmt.Sprintf("%s-%d-%s", hostname, os.Getpid(), uuidx.New()),
+			leaseTTL: leaseTTL,
+		}
+	}
+	return &mysqlElector{lockName: schedulerLeaderKey}
+}
+
+// 基于 Redis 租约（SET NX PX）的选主器：leader 需在租约过期前续期，否则其他副本可抢占
+type redisElector struct {
+	client   *goredis.Client
+	key      string
+	identity string
+	leaseTTL time.Duration
+}
+
+func (e *redisElector) campaign(ctx context.Context) (bool, error) {
+	renewed, err := redisRenewLeaseScript.Run(
+		ctx, e.client, []string{e.key}, e.identity, e.leaseTTL.Milliseconds(),
+	).Int()
+	if err != nil {
+		return false, errors.Wrap(err, "renew scheduler leader lease")
+	}
+	if renewed == 1 {
+		return true, nil
+	}
+	acquired, err := e.client.SetNX(ctx, e.key, e.identity, e.leaseTTL).Result()
+	if err != nil {
+		return false, errors.Wrap(err, "acquire scheduler leader lease")
+	}
+	return acquired, nil
+}
+
+func (e *redisElector) resign(ctx context.Context) {
+	_ = redisReleaseLeaseScript.Run(ctx, e.client, []string{e.key}, e.identity).Err()
+}
+
+// 基于 MySQL 咨询锁（GET_LOCK）的选主器：锁与数据库连接（会话）绑定，leader 进程退出 / 连接断开时锁自动释放
+type mysqlElector struct {
+	lockName string
+	// 为空则使用 database.Client 的连接池
+	db *sql.DB
+	// 持有锁的连接
+	conn *sql.Conn
+}
+
+func (e *mysqlElector) campaign(ctx context.Context) (bool, error) {
+	// 已持有锁：确认锁仍由当前连接（会话）持有，连接断开重连后锁已被释放，可能已被其他副本获取
+	if e.conn != nil {
+		var held sql.NullInt64
+		err := e.conn.QueryRowContext(ctx, "SELECT IS_USED_LOCK(?) = CONNECTION_ID()", e.lockName).Scan(&held)
+		if err == nil && held.Int64 == 1 {
+			return true, nil
+		}
+		_ = e.conn.Close()
+		e.conn = nil
+	}
+
+	sqlDB, err := e.sqlDB(ctx)
+	if err != nil {
+		return false, err
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return false, errors.Wrap(err, "get sql conn")
+	}
+	var acquired sql.NullInt64
+	if err = conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, 0)", e.lockName).Scan(&acquired); err != nil {
+		_ = conn.Close()
+		return false, errors.Wrap(err, "acquire scheduler leader lock")
+	}
+	if acquired.Int64 != 1 {
+		_ = conn.Close()
+		return false, nil
+	}
+	e.conn = conn
+	return true, nil
+}
+
+// 获取连接池
+func (e *mysqlElector) sqlDB(ctx context.Context) (*sql.DB, error) {
+	if e.db != nil {
+		return e.db, nil
+	}
+	sqlDB, err := database.Client(ctx).DB()
+	return sqlDB, errors.Wrap(err, "get sql db")
+}
+
+func (e *mysqlElector) resign(ctx context.Context) {
+	if e.conn == nil {
+		return
+	}
+	_, _ = e.conn.ExecContext(ctx, "SELECT RELEASE_LOCK(?)", e.lockName)
+	_ = e.conn.Close()
+	e.conn = nil
+}
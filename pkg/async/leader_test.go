@@ -0,0 +1,143 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - Go 开发框架 (BlueKing - Go Framework) available.
+ * Copyright (C) 2017 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ *	https://opensource.org/licenses/MIT
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package async
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alicebob/miniredis/v2"
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedisElector(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := goredis.NewClient(&goredis.Options{Addr: mr.Addr()})
+	defer client.Close()
+	newRedisElector := func(identity string) *redisElector {
+		return &redisElector{client: client, key: schedulerLeaderKey, identity: identity, leaseTTL: 15 * time.Second}
+	}
+	ctx := context.Background()
+	a, b := newRedisElector("a"), newRedisElector("b")
+
+	// 竞选：先到先得
+	isLeader, err := a.campaign(ctx)
+	assert.NoError(t, err)
+	assert.True(t, isLeader)
+	isLeader, err = b.campaign(ctx)
+	assert.NoError(t, err)
+	assert.False(t, isLeader)
+
+	// 续期：租约时长重置
+	mr.FastForward(10 * time.Second)
+	isLeader, err = a.campaign(ctx)
+	assert.NoError(t, err)
+	assert.True(t, isLeader)
+	assert.Equal(t, 15*time.Second, mr.TTL(schedulerLeaderKey))
+
+	// 失去 leader：未及时续期，租约过期后被其他副本抢占
+	mr.FastForward(16 * time.Second)
+	isLeader, err = b.campaign(ctx)
+	assert.NoError(t, err)
+	assert.True(t, isLeader)
+	isLeader, err = a.campaign(ctx)
+	assert.NoError(t, err)
+	assert.False(t, isLeader)
+
+	// 放弃 leader：非持有者无法释放租约，持有者释放后其他副本可立即接管
+	a.resign(ctx)
+	leader, err := mr.Get(schedulerLeaderKey)
+	assert.NoError(t, err)
+	assert.Equal(t, "b", leader)
+	b.resign(ctx)
+	assert.False(t, mr.Exists(schedulerLeaderKey))
+	isLeader, err = a.campaign(ctx)
+	assert.NoError(t, err)
+	assert.True(t, isLeader)
+
+	// Redis 不可用
+	mr.Close()
+	_, err = a.campaign(ctx)
+	assert.Error(t, err)
+}
+
+func TestMySQLElector(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	e := &mysqlElector{lockName: schedulerLeaderKey, db: db}
+	ctx := context.Background()
+
+	// 竞选：锁已被其他副本持有
+	mock.ExpectQuery("SELECT GET_LOCK").WithArgs(schedulerLeaderKey).
+		WillReturnRows(sqlmock.NewRows([]string{"acquired"}).AddRow(0))
+	isLeader, err := e.campaign(ctx)
+	assert.NoError(t, err)
+	assert.False(t, isLeader)
+
+	// 竞选：获取锁
+	mock.ExpectQuery("SELECT GET_LOCK").WithArgs(schedulerLeaderKey).
+		WillReturnRows(sqlmock.NewRows([]string{"acquired"}).AddRow(1))
+	isLeader, err = e.campaign(ctx)
+	assert.NoError(t, err)
+	assert.True(t, isLeader)
+
+	// 续期：锁仍由当前连接持有
+	mock.ExpectQuery("SELECT IS_USED_LOCK\\(\\?\\) = CONNECTION_ID\\(\\)").WithArgs(schedulerLeaderKey).
+		WillReturnRows(sqlmock.NewRows([]string{"held"}).AddRow(1))
+	isLeader, err = e.campaign(ctx)
+	assert.NoError(t, err)
+	assert.True(t, isLeader)
+
+	// 失去 leader：连接重连后锁已释放并被其他副本获取（IS_USED_LOCK 为其他连接），重新竞选失败
+	mock.ExpectQuery("SELECT IS_USED_LOCK").WithArgs(schedulerLeaderKey).
+		WillReturnRows(sqlmock.NewRows([]string{"held"}).AddRow(0))
+	mock.ExpectQuery("SELECT GET_LOCK").WithArgs(schedulerLeaderKey).
+		WillReturnRows(sqlmock.NewRows([]string{"acquired"}).AddRow(0))
+	isLeader, err = e.campaign(ctx)
+	assert.NoError(t, err)
+	assert.False(t, isLeader)
+	assert.Nil(t, e.conn)
+
+	// 锁已无人持有（IS_USED_LOCK 为 NULL）时同样视为失去 leader，重新竞选
+	mock.ExpectQuery("SELECT GET_LOCK").WithArgs(schedulerLeaderKey).
+		WillReturnRows(sqlmock.NewRows([]string{"acquired"}).AddRow(1))
+	isLeader, err = e.campaign(ctx)
+	assert.NoError(t, err)
+	assert.True(t, isLeader)
+	mock.ExpectQuery("SELECT IS_USED_LOCK").WithArgs(schedulerLeaderKey).
+		WillReturnRows(sqlmock.NewRows([]string{"held"}).AddRow(nil))
+	mock.ExpectQuery("SELECT GET_LOCK").WithArgs(schedulerLeaderKey).
+		WillReturnRows(sqlmock.NewRows([]string{"acquired"}).AddRow(1))
+	isLeader, err = e.campaign(ctx)
+	assert.NoError(t, err)
+	assert.True(t, isLeader)
+
+	// 放弃 leader：释放锁并归还连接
+	mock.ExpectExec("SELECT RELEASE_LOCK").WithArgs(schedulerLeaderKey).WillReturnResult(sqlmock.NewResult(0, 1))
+	e.resign(ctx)
+	assert.Nil(t, e.conn)
+	// 未持有锁时无需释放
+	e.resign(ctx)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
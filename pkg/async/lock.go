@@ -0,0 +1,180 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - Go 开发框架 (BlueKing - Go Framework) available.
+ * Copyright (C) 2017 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ *	https://opensource.org/licenses/MIT
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package async
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	goredis "github.com/redis/go-redis/v9"
+	"gorm.io/gorm/clause"
+
+	"github.com/TencentBlueKing/blueapps-go/pkg/infras/database"
+	"github.com/TencentBlueKing/blueapps-go/pkg/infras/redis"
+	"github.com/TencentBlueKing/blueapps-go/pkg/model"
+)
+
+const (
+	// 任务锁使用的 Redis key 前缀
+	taskLockKeyPrefix = "blueapps-go:async:locks:"
+	// 默认的去重有效期
+	defaultDedupTTL = time.Minute
+	// 获取锁时，锁恰好过期 / 被释放导致无法获取持有者的最大重试次数
+	maxLockAttempts = 3
+)
+
+// ErrTaskLocked 声明了执行期间唯一的任务，已有相同参数的任务等待执行或执行中
+var ErrTaskLocked = errors.New("task already pending or running")
+
+// 任务锁：锁的值为持有锁的任务 ID，在有效期内仅能被一个任务持有
+type taskLocker interface {
+	// acquire 获取锁，获取失败时返回当前持有锁的任务 ID
+	acquire(ctx context.Context, key string, taskID int64, ttl time.Duration) (holder int64, acquired bool, err error)
+	// release 释放锁（仅持有者可释放）
+	release(ctx context.Context, key string, taskID int64) error
+}
+
+// 获取任务锁：优先使用 Redis，未启用 Redis 增强服务时使用 DB（model.TaskLock）
+func getTaskLocker() taskLocker {
+	if redisEnabled() {
+		return &redisTaskLocker{client: redis.Client()}
+	}
+	return &dbTaskLocker{}
+}
+
+// 下发任务去重的锁名称
+func dedupLockKey(name, dedupKey string) string {
+	return "dedup:" + name + ":" + dedupKey
+}
+
+// 任务执行期间唯一的锁名称：任务名称 + 参数（JSON）摘要，即相同参数的任务同时只能有一个等待执行或执行中
+// 注：参数会先规范化（排序字段 & 去除空白），避免 DB 中读取的参数（MySQL JSON 类型会重新格式化）与下发时的摘要不一致
+func uniqueLockKey(name string, rawArgs []byte) string {
+	sum := sha1.Sum(normalizeJSON(rawArgs))
+	return "unique:" + name + ":" + hex.EncodeToString(sum[:])
+}
+
+// 规范化 JSON（排序字段 & 去除空白，保留数字精度），无法解析时原样返回
+func normalizeJSON(raw []byte) []byte {
+	var v any
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	if err := decoder.Decode(&v); err != nil {
+		return raw
+	}
+	normalized, err := json.Marshal(v)
+	if err != nil {
+		return raw
+	}
+	return normalized
+}
+
+// 基于 Redis（SET NX PX）的任务锁
+type redisTaskLocker struct {
+	client *goredis.Client
+}
+
+func (l *redisTaskLocker) acquire(
+	ctx context.Context, key string, taskID int64, ttl time.Duration,
+) (int64, bool, error) {
+	key = taskLockKeyPrefix + key
+	for range maxLockAttempts {
+		acquired, err := l.client.SetNX(ctx, key, taskID, ttl).Result()
+		if err != nil {
+			return 0, false, errors.Wrapf(err, "acquire task lock %s", key)
+		}
+		if acquired {
+			return taskID, true, nil
+		}
+		holder, err := l.client.Get(ctx, key).Int64()
+		// 锁恰好过期 / 被释放，重新获取
+		if errors.Is(err, goredis.Nil) {
+			continue
+		}
+		if err != nil {
+			return 0, false, errors.Wrapf(err, "get task lock %s holder", key)
+		}
+		return holder, false, nil
+	}
+	return 0, false, errors.Errorf("acquire task lock %s: too many attempts", key)
+}
+
+func (l *redisTaskLocker) release(ctx context.Context, key string, taskID int64) error {
+	key = taskLockKeyPrefix + key
+	err := redisReleaseLeaseScript.Run(ctx, l.client, []string{key}, strconv.FormatInt(taskID, 10)).Err()
+	return errors.Wrapf(err, "release task lock %s", key)
+}
+
+// 基于 DB 的任务锁：通过主键唯一约束保证仅有一个任务持有锁，过期的锁通过条件更新抢占
+type dbTaskLocker struct{}
+
+func (l *dbTaskLocker) acquire(
+	ctx context.Context, key string, taskID int64, ttl time.Duration,
+) (int64, bool, error) {
+	db := database.Client(ctx)
+	for range maxLockAttempts {
+		now := time.Now()
+		lock := model.TaskLock{Key: key, TaskID: taskID, ExpiresAt: now.Add(ttl)}
+		tx := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&lock)
+		if tx.Error != nil {
+			return 0, false, errors.Wrapf(tx.Error, "acquire task lock %s", key)
+		}
+		if tx.RowsAffected != 0 {
+			l.purgeExpired(ctx, now)
+			return taskID, true, nil
+		}
+
+		// 锁已过期则抢占
+		tx = db.Model(&model.TaskLock{}).
+			Where("lock_key = ? AND expires_at <= ?", key, now).
+			Updates(map[string]any{"task_id": taskID, "expires_at": lock.ExpiresAt})
+		if tx.Error != nil {
+			return 0, false, errors.Wrapf(tx.Error, "acquire expired task lock %s", key)
+		}
+		if tx.RowsAffected != 0 {
+			return taskID, true, nil
+		}
+
+		var holders []int64
+		if err := db.Model(&model.TaskLock{}).Where("lock_key = ?", key).Pluck("task_id", &holders).Error; err != nil {
+			return 0, false, errors.Wrapf(err, "get task lock %s holder", key)
+		}
+		// 锁恰好被释放，重新获取
+		if len(holders) != 0 {
+			return holders[0], false, nil
+		}
+	}
+	return 0, false, errors.Errorf("acquire task lock %s: too many attempts", key)
+}
+
+func (l *dbTaskLocker) release(ctx context.Context, key string, taskID int64) error {
+	err := database.Client(ctx).Where("lock_key = ? AND task_id = ?", key, taskID).Delete(&model.TaskLock{}).Error
+	return errors.Wrapf(err, "release task lock %s", key)
+}
+
+// 清理部分已过期的锁（如去重记录），避免锁记录无限增长，清理失败不影响获取锁
+func (l *dbTaskLocker) purgeExpired(ctx context.Context, now time.Time) {
+	database.Client(ctx).Where("expires_at <= ?", now).Limit(100).Delete(&model.TaskLock{})
+}
@@ -0,0 +1,58 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - Go 开发框架 (BlueKing - Go Framework) available.
+ * Copyright (C) 2017 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ *	https://opensource.org/licenses/MIT
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package async
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUniqueLockKey(t *testing.T) {
+	key := uniqueLockKey("greet", []byte(`{"name":"foo","times":1}`))
+	assert.Regexp(t, `^unique:greet:[0-9a-f]{40}$`, key)
+
+	// 字段顺序 & 空白不影响锁名称（如 MySQL JSON 类型读取的参数）
+	assert.Equal(t, key, uniqueLockKey("greet", []byte(`{"times": 1, "name": "foo"}`)))
+	// 参数或任务名称不同则锁名称不同
+	assert.NotEqual(t, key, uniqueLockKey("greet", []byte(`{"name":"foo","times":2}`)))
+	assert.NotEqual(t, key, uniqueLockKey("hello", []byte(`{"name":"foo","times":1}`)))
+	// 大整数不丢失精度
+	assert.NotEqual(t,
+		uniqueLockKey("greet", []byte(`{"id":9007199254740993}`)),
+		uniqueLockKey("greet", []byte(`{"id":9007199254740992}`)),
+	)
+	// 无参数
+	assert.Equal(t, uniqueLockKey("greet", []byte("null")), uniqueLockKey("greet", []byte(" null ")))
+}
+
+func TestDedupLockKey(t *testing.T) {
+	assert.Equal(t, "dedup:greet:abc", dedupLockKey("greet", "abc"))
+	assert.NotEqual(t, dedupLockKey("greet", "abc"), dedupLockKey("hello", "abc"))
+}
+
+func TestWithUniqueWhileRunning(t *testing.T) {
+	Register("greet", greet, WithUniqueWhileRunning(time.Hour))
+	defer unregister("greet")
+
+	def, err := getTaskDef("greet")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Hour, def.uniqueTTL)
+}
@@ -0,0 +1,137 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - Go 开发框架 (BlueKing - Go Framework) available.
+ * Copyright (C) 2017 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ *	https://opensource.org/licenses/MIT
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package async
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/TencentBlueKing/blueapps-go/pkg/model"
+)
+
+// 异步任务相关指标，通过 /metrics 暴露
+var (
+	// 各队列等待执行的任务数量（单个进程内）
+	queueDepth = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "async_task",
+		Name:      "queue_depth",
+		Help:      "Number of async tasks waiting in the worker pool queue.",
+	}, []string{"queue"})
+
+	// 各队列 & 任务执行中的数量（单个进程内）
+	inFlightTasks = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "async_task",
+		Name:      "in_flight",
+		Help:      "Number of async tasks being executed by the worker pool.",
+	}, []string{"queue", "task"})
+
+	// 下发的任务数量（不含重试）
+	enqueuedTasks = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "async_task",
+		Name:      "enqueued_total",
+		Help:      "Total number of async tasks enqueued.",
+	}, []string{"task", "queue"})
+
+	// 开始执行的次数（含重试）
+	startedTasks = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "async_task",
+		Name:      "started_total",
+		Help:      "Total number of async task executions started, including retries.",
+	}, []string{"task"})
+
+	// 执行成功的任务数量
+	succeededTasks = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "async_task",
+		Name:      "succeeded_total",
+		Help:      "Total number of async tasks succeeded.",
+	}, []string{"task"})
+
+	// 最终执行失败的任务数量，reason 为 error（执行出错）/ timeout（执行超时）
+	failedTasks = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "async_task",
+		Name:      "failed_total",
+		Help:      "Total number of async tasks failed after all attempts.",
+	}, []string{"task", "reason"})
+
+	// 任务函数 panic 的次数（panic 的任务会直接标记为失败）
+	panickedTasks = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "async_task",
+		Name:      "panics_total",
+		Help:      "Total number of async task executions panicked.",
+	}, []string{"task"})
+
+	// 执行失败后等待重试的次数
+	retriedTasks = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "async_task",
+		Name:      "retried_total",
+		Help:      "Total number of async task executions failed and scheduled for retry.",
+	}, []string{"task"})
+
+	// 单次执行耗时，status 为本次执行的结果（succeeded / failed / timeout / cancelled）
+	taskDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "async_task",
+		Name:      "duration_seconds",
+		Help:      "Duration of async task executions in seconds.",
+		Buckets:   []float64{0.01, 0.05, 0.1, 0.5, 1, 5, 10, 30, 60, 300, 900, 3600},
+	}, []string{"task", "status"})
+
+	// 任务从可执行（下发 / 重试延迟到期）到开始执行的等待时间
+	queueLatency = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "async_task",
+		Name:      "queue_latency_seconds",
+		Help:      "Time async tasks spent waiting in the queue before execution in seconds.",
+		Buckets:   []float64{0.01, 0.05, 0.1, 0.5, 1, 5, 10, 30, 60, 300, 900},
+	}, []string{"task"})
+)
+
+func init() {
+	prometheus.MustRegister(
+		queueDepth, inFlightTasks,
+		enqueuedTasks, startedTasks, succeededTasks, failedTasks, panickedTasks, retriedTasks,
+		taskDuration, queueLatency,
+	)
+}
+
+// 记录任务开始执行，及其在队列中的等待时间
+func observeTaskStarted(msg *Message, startedAt time.Time) {
+	startedTasks.WithLabelValues(msg.Name).Inc()
+	// 延迟执行（如重试）的任务，从预计执行时间开始计算
+	readyAt := msg.EnqueuedAt
+	if msg.ETA.After(readyAt) {
+		readyAt = msg.ETA
+	}
+	queueLatency.WithLabelValues(msg.Name).Observe(max(startedAt.Sub(readyAt), 0).Seconds())
+}
+
+// 记录任务单次执行的结果 & 耗时，status 为 pending 表示执行失败但等待重试
+func observeTaskFinished(msg *Message, status model.TaskStatus, duration time.Duration) {
+	switch status {
+	case model.TaskStatusSucceeded:
+		succeededTasks.WithLabelValues(msg.Name).Inc()
+	case model.TaskStatusFailed:
+		failedTasks.WithLabelValues(msg.Name, "error").Inc()
+	case model.TaskStatusTimeout:
+		failedTasks.WithLabelValues(msg.Name, "timeout").Inc()
+	case model.TaskStatusPending:
+		retriedTasks.WithLabelValues(msg.Name).Inc()
+		status = model.TaskStatusFailed
+	}
+	taskDuration.WithLabelValues(msg.Name, string(status)).Observe(duration.Seconds())
+}
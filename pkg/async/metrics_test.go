@@ -0,0 +1,59 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - Go 开发框架 (BlueKing - Go Framework) available.
+ * Copyright (C) 2017 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ *	https://opensource.org/licenses/MIT
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package async
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/TencentBlueKing/blueapps-go/pkg/model"
+)
+
+func TestObserveTaskStarted(t *testing.T) {
+	now := time.Now()
+	msg := &Message{Name: "metrics-started", EnqueuedAt: now.Add(-2 * time.Second), Attempt: 1}
+	observeTaskStarted(msg, now)
+	assert.Equal(t, 1.0, testutil.ToFloat64(startedTasks.WithLabelValues("metrics-started")))
+
+	// 重试的任务，从预计执行时间开始计算等待时间
+	msg = msg.retry(time.Hour)
+	observeTaskStarted(msg, msg.ETA.Add(time.Second))
+	assert.Equal(t, 2.0, testutil.ToFloat64(startedTasks.WithLabelValues("metrics-started")))
+	assert.Equal(t, 1, testutil.CollectAndCount(queueLatency, "async_task_queue_latency_seconds"))
+}
+
+func TestObserveTaskFinished(t *testing.T) {
+	msg := &Message{Name: "metrics-finished"}
+	observeTaskFinished(msg, model.TaskStatusSucceeded, time.Second)
+	observeTaskFinished(msg, model.TaskStatusPending, time.Second)
+	observeTaskFinished(msg, model.TaskStatusPending, time.Second)
+	observeTaskFinished(msg, model.TaskStatusFailed, time.Second)
+	observeTaskFinished(msg, model.TaskStatusTimeout, time.Minute)
+	observeTaskFinished(msg, model.TaskStatusCancelled, time.Second)
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(succeededTasks.WithLabelValues(msg.Name)))
+	assert.Equal(t, 2.0, testutil.ToFloat64(retriedTasks.WithLabelValues(msg.Name)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(failedTasks.WithLabelValues(msg.Name, "error")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(failedTasks.WithLabelValues(msg.Name, "timeout")))
+	// 等待重试的执行按 failed 记录耗时
+	assert.Equal(t, 4, testutil.CollectAndCount(taskDuration, "async_task_duration_seconds"))
+}
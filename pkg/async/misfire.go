@@ -0,0 +1,136 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - Go 开发框架 (BlueKing - Go Framework) available.
+ * Copyright (C) 2017 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ *	https://opensource.org/licenses/MIT
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package async
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	"github.com/TencentBlueKing/blueapps-go/pkg/infras/database"
+	log "github.com/TencentBlueKing/blueapps-go/pkg/logging"
+	"github.com/TencentBlueKing/blueapps-go/pkg/model"
+)
+
+// 按各周期任务的错过触发策略，补跑 scheduler 停机期间（上次触发时间 ~ now）错过的触发
+// 注：ETA 已过的一次性任务在注册时处理（见 fireOverdueOneOff），这里不再补跑
+func (s *TaskScheduler) catchUpMisfires(now time.Time) {
+	var periodicTasks []model.PeriodicTask
+	if err := database.Client(s.ctx).
+		Where("enabled = ?", true).
+		Where("eta IS NULL").
+		Where("misfire_policy != ?", model.MisfirePolicySkip).
+		Find(&periodicTasks).Error; err != nil {
+		log.Errorf(s.ctx, "failed to load periodic tasks for misfire catch-up: %s", err)
+		return
+	}
+	if len(periodicTasks) == 0 {
+		return
+	}
+
+	lastFiredAts, err := lastFiredAts(s.ctx, periodicTasks)
+	if err != nil {
+		log.Errorf(s.ctx, "failed to load periodic tasks last fired time: %s", err)
+		return
+	}
+
+	for _, task := range periodicTasks {
+		// 从未触发过的任务，以创建时间作为上次触发时间
+		lastFiredAt, ok := lastFiredAts[task.ID]
+		if !ok {
+			lastFiredAt = task.CreatedAt
+		}
+
+		schedule, err := ParseSchedule(&task)
+		if err != nil {
+			log.Errorf(s.ctx, "failed to parse periodic task %d schedule: %s", task.ID, err)
+			continue
+		}
+
+		firings, truncated := missedFirings(schedule, lastFiredAt, now, task.MisfirePolicy, task.MisfireLimit)
+		if len(firings) == 0 {
+			continue
+		}
+
+		taskRepr := fmt.Sprintf("periodic task %s (id: %d)", task.Name, task.ID)
+		log.Infof(
+			s.ctx, "[catch-up] %s missed firings since %s, policy: %s, catch up %d firings (truncated: %t)",
+			taskRepr, lastFiredAt, task.MisfirePolicy, len(firings), truncated,
+		)
+		for _, scheduledAt := range firings {
+			s.fire(task.ID, taskRepr, scheduledAt, true)
+		}
+	}
+}
+
+// 获取各周期任务的上次触发（计划）时间：周期任务 ID -> 上次触发时间
+// 注：手动触发不是按调度计划的触发，不计入（否则会跳过手动触发前错过的触发）
+func lastFiredAts(ctx context.Context, periodicTasks []model.PeriodicTask) (map[int64]time.Time, error) {
+	taskIDs := make([]int64, 0, len(periodicTasks))
+	for _, task := range periodicTasks {
+		taskIDs = append(taskIDs, task.ID)
+	}
+
+	var rows []struct {
+		PeriodicTaskID int64
+		LastFiredAt    time.Time
+	}
+	if err := database.Client(ctx).
+		Model(&model.PeriodicTaskRun{}).
+		Select("periodic_task_id, MAX(scheduled_at) AS last_fired_at").
+		Where("periodic_task_id IN ? AND manual = ?", taskIDs, false).
+		Group("periodic_task_id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	lastFiredAts := make(map[int64]time.Time, len(rows))
+	for _, row := range rows {
+		lastFiredAts[row.PeriodicTaskID] = row.LastFiredAt
+	}
+	return lastFiredAts, nil
+}
+
+// 计算 (lastFiredAt, now) 之间错过的触发时间，按策略返回需要补跑的部分：
+// - run_once：仅补跑最早错过的一次
+// - run_all：按时间顺序补跑，最多 limit 次，truncated 表示是否还有更多错过的触发被丢弃
+func missedFirings(
+	schedule cron.Schedule, lastFiredAt, now time.Time, policy model.MisfirePolicy, limit int,
+) (firings []time.Time, truncated bool) {
+	switch policy {
+	case model.MisfirePolicyRunOnce:
+		limit = 1
+	case model.MisfirePolicyRunAll:
+		if limit <= 0 {
+			return nil, false
+		}
+	default:
+		return nil, false
+	}
+
+	for next := schedule.Next(lastFiredAt); !next.IsZero() && next.Before(now); next = schedule.Next(next) {
+		if len(firings) == limit {
+			return firings, true
+		}
+		firings = append(firings, next)
+	}
+	return firings, false
+}
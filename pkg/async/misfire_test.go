@@ -0,0 +1,71 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - Go 开发框架 (BlueKing - Go Framework) available.
+ * Copyright (C) 2017 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ *	https://opensource.org/licenses/MIT
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package async
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/TencentBlueKing/blueapps-go/pkg/model"
+)
+
+func TestMissedFirings(t *testing.T) {
+	schedule, err := ParseSchedule(&model.PeriodicTask{Cron: "0 * * * *"})
+	assert.NoError(t, err)
+
+	lastFiredAt := time.Date(2026, 10, 18, 10, 0, 0, 0, time.Local)
+	now := time.Date(2026, 10, 18, 13, 30, 0, 0, time.Local)
+	hour := func(h int) time.Time { return time.Date(2026, 10, 18, h, 0, 0, 0, time.Local) }
+
+	// 跳过
+	firings, truncated := missedFirings(schedule, lastFiredAt, now, model.MisfirePolicySkip, 10)
+	assert.Empty(t, firings)
+	assert.False(t, truncated)
+
+	// 仅补跑一次
+	firings, truncated = missedFirings(schedule, lastFiredAt, now, model.MisfirePolicyRunOnce, 10)
+	assert.Equal(t, []time.Time{hour(11)}, firings)
+	assert.True(t, truncated)
+
+	// 补跑全部
+	firings, truncated = missedFirings(schedule, lastFiredAt, now, model.MisfirePolicyRunAll, 10)
+	assert.Equal(t, []time.Time{hour(11), hour(12), hour(13)}, firings)
+	assert.False(t, truncated)
+
+	// 补跑全部，但超出上限
+	firings, truncated = missedFirings(schedule, lastFiredAt, now, model.MisfirePolicyRunAll, 2)
+	assert.Equal(t, []time.Time{hour(11), hour(12)}, firings)
+	assert.True(t, truncated)
+
+	// 没有错过的触发
+	firings, _ = missedFirings(schedule, hour(13), now, model.MisfirePolicyRunAll, 10)
+	assert.Empty(t, firings)
+}
+
+func TestMissedFiringsOneOff(t *testing.T) {
+	eta := time.Date(2026, 10, 18, 3, 0, 0, 0, time.Local)
+	schedule, err := ParseSchedule(&model.PeriodicTask{ETA: eta})
+	assert.NoError(t, err)
+
+	createdAt := eta.Add(-time.Hour)
+	firings, _ := missedFirings(schedule, createdAt, eta.Add(time.Hour), model.MisfirePolicyRunAll, 10)
+	assert.Equal(t, []time.Time{eta}, firings)
+}
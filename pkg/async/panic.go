@@ -0,0 +1,55 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - Go 开发框架 (BlueKing - Go Framework) available.
+ * Copyright (C) 2017 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ *	https://opensource.org/licenses/MIT
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package async
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+
+	log "github.com/TencentBlueKing/blueapps-go/pkg/logging"
+)
+
+// PanicError 任务函数 panic 时返回的错误，记录 panic 的值及调用栈
+type PanicError struct {
+	Value any
+	stack string
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("task panicked: %v", e.Value)
+}
+
+// Stack panic 时的调用栈
+func (e *PanicError) Stack() string {
+	return e.stack
+}
+
+// 恢复任务函数的 panic 并转换为不可重试的 PanicError（需直接 defer 调用），避免 panic 导致进程崩溃
+// 注：ctx 中的 RequestID & trace 上下文会随日志一并打印
+func recoverPanic(ctx context.Context, name string, err *error) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	panicErr := &PanicError{Value: r, stack: string(debug.Stack())}
+	log.Errorf(ctx, "task %s panicked: %v\n%s", name, r, panicErr.stack)
+	panickedTasks.WithLabelValues(name).Inc()
+	*err = NonRetryable(panicErr)
+}
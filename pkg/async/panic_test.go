@@ -0,0 +1,87 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - Go 开发框架 (BlueKing - Go Framework) available.
+ * Copyright (C) 2017 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ *	https://opensource.org/licenses/MIT
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package async
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+type panicArgs struct {
+	Index int `json:"index"`
+}
+
+func panicTask(_ context.Context, args panicArgs) (int, error) {
+	return []int{1, 2, 3}[args.Index], nil
+}
+
+func TestExecutePanicked(t *testing.T) {
+	Register("panicTask", panicTask, WithRetryPolicy(RetryPolicy{MaxAttempts: 3}))
+	defer unregister("panicTask")
+
+	result, err := execute(context.Background(), "panicTask", json.RawMessage(`{"index": 1}`))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, result)
+
+	_, err = execute(context.Background(), "panicTask", json.RawMessage(`{"index": 5}`))
+	var panicErr *PanicError
+	assert.True(t, errors.As(err, &panicErr))
+	assert.Contains(t, err.Error(), "index out of range")
+	assert.Contains(t, panicErr.Stack(), "panicTask")
+	// 调用栈会记录到死信中
+	assert.Equal(t, panicErr.Stack(), errorStack(err))
+	// panic 的任务不会重试
+	assert.False(t, getRetryPolicy("panicTask").shouldRetry(1, err))
+	assert.Equal(t, 1.0, testutil.ToFloat64(panickedTasks.WithLabelValues("panicTask")))
+
+	// 同步执行的任务同样不会因 panic 导致进程崩溃
+	_, err = RunTask(context.Background(), "panicTask", json.RawMessage(`{"index": -1}`), 0)
+	assert.True(t, errors.As(err, &panicErr))
+	assert.Equal(t, 2.0, testutil.ToFloat64(panickedTasks.WithLabelValues("panicTask")))
+}
+
+type panicValidateArgs struct {
+	Name string `json:"name"`
+}
+
+func (a panicValidateArgs) Validate() error {
+	panic("validate " + a.Name)
+}
+
+func TestValidateArgsPanicked(t *testing.T) {
+	Register("panicValidate", func(context.Context, panicValidateArgs) (any, error) { return nil, nil })
+	defer unregister("panicValidate")
+
+	// 参数自定义的校验 panic 时转换为错误，不影响进程
+	var panicErr *PanicError
+	err := ValidateArgs("panicValidate", json.RawMessage(`{"name": "blueking"}`))
+	assert.ErrorAs(t, err, &panicErr)
+	assert.Equal(t, "validate blueking", panicErr.Value)
+	assert.NotEmpty(t, panicErr.Stack())
+
+	// 执行时同样转换为不可重试的错误
+	_, err = execute(context.Background(), "panicValidate", json.RawMessage(`{"name": "blueking"}`))
+	assert.ErrorAs(t, err, &panicErr)
+	assert.False(t, getRetryPolicy("panicValidate").shouldRetry(1, err))
+}
@@ -0,0 +1,63 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - Go 开发框架 (BlueKing - Go Framework) available.
+ * Copyright (C) 2017 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ *	https://opensource.org/licenses/MIT
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package async
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/TencentBlueKing/blueapps-go/pkg/infras/database"
+	"github.com/TencentBlueKing/blueapps-go/pkg/model"
+)
+
+// ErrFiringClaimed 本次触发已被抢占（如同一秒内已手动触发过该周期任务）
+var ErrFiringClaimed = errors.New("periodic task firing already claimed")
+
+// TriggerPeriodicTask 立即触发一次周期任务（无论是否启用），返回下发的任务 ID
+// 手动触发同样写入触发记录（标记为手动触发），但与 scheduler 的触发分别去重，也不影响错过触发的补跑
+func TriggerPeriodicTask(ctx context.Context, periodicTaskID int64, operator string) (int64, error) {
+	var task model.PeriodicTask
+	if err := database.Client(ctx).First(&task, periodicTaskID).Error; err != nil {
+		return 0, errors.Wrapf(err, "get periodic task %d", periodicTaskID)
+	}
+
+	// DB 中触发时间精确到秒，按秒去重（避免重复点击等导致重复下发）
+	run := &model.PeriodicTaskRun{PeriodicTaskID: task.ID, ScheduledAt: time.Now().Truncate(time.Second), Manual: true}
+	claimed, err := claimFiring(ctx, run)
+	if err != nil {
+		return 0, errors.Wrapf(err, "claim periodic task %d firing", task.ID)
+	}
+	if !claimed {
+		return 0, errors.Wrapf(ErrFiringClaimed, "periodic task %d", task.ID)
+	}
+
+	taskID, err := ApplyTask(ctx, task.Name, json.RawMessage(task.Args), WithCreator(operator))
+	switch {
+	case errors.Is(err, ErrTaskLocked):
+		finishRun(ctx, run, model.PeriodicTaskRunStatusSkipped, 0, err)
+	case err != nil:
+		finishRun(ctx, run, model.PeriodicTaskRunStatusFailed, taskID, err)
+	default:
+		finishRun(ctx, run, model.PeriodicTaskRunStatusApplied, taskID, nil)
+	}
+	return taskID, err
+}
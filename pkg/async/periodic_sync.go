@@ -0,0 +1,267 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - Go 开发框架 (BlueKing - Go Framework) available.
+ * Copyright (C) 2017 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ *	https://opensource.org/licenses/MIT
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package async
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/samber/lo"
+	"gopkg.in/yaml.v3"
+	"gorm.io/gorm"
+
+	"github.com/TencentBlueKing/blueapps-go/pkg/infras/database"
+	log "github.com/TencentBlueKing/blueapps-go/pkg/logging"
+	"github.com/TencentBlueKing/blueapps-go/pkg/model"
+)
+
+// scheduler 同步声明式定义时记录的操作人
+const declarativeSyncOperator = "scheduler"
+
+// PeriodicTaskDefinition 声明式周期任务定义（periodic_tasks.yaml 中的 periodicTasks 列表项）
+type PeriodicTaskDefinition struct {
+	// 唯一标识，用于关联已同步的周期任务（修改任务名称 / cron 等不会重建周期任务）
+	Key  string `yaml:"key"`
+	Name string `yaml:"name"`
+	// cron 表达式，支持可选的秒级字段（6 位）
+	Cron string `yaml:"cron"`
+	// IANA 时区（如 Asia/Shanghai），为空表示使用 scheduler 所在机器时区
+	Timezone string `yaml:"timezone"`
+	// 任务参数，需符合任务声明的参数类型
+	Args any `yaml:"args"`
+	// 是否启用，为空表示启用
+	Enabled *bool `yaml:"enabled"`
+	// 错过触发的处理策略（为空表示 skip），及 run_all 策略下最多补跑的次数（为 0 表示 10）
+	MisfirePolicy model.MisfirePolicy `yaml:"misfirePolicy"`
+	MisfireLimit  int                 `yaml:"misfireLimit"`
+}
+
+// LoadPeriodicTaskDefinitions 从 YAML 文件加载声明式周期任务定义（不允许未知字段）
+func LoadPeriodicTaskDefinitions(path string) ([]PeriodicTaskDefinition, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "read periodic tasks file")
+	}
+	var file struct {
+		PeriodicTasks []PeriodicTaskDefinition `yaml:"periodicTasks"`
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+	// 空文件视为没有定义任何周期任务
+	if err = decoder.Decode(&file); err != nil && len(bytes.TrimSpace(content)) != 0 {
+		return nil, errors.Wrapf(err, "parse periodic tasks file %s", path)
+	}
+	return file.PeriodicTasks, nil
+}
+
+// 转换为周期任务，并校验任务名称、参数及调度计划
+func (d *PeriodicTaskDefinition) toPeriodicTask() (*model.PeriodicTask, error) {
+	if d.Key == "" {
+		return nil, errors.New("key required")
+	}
+	if len(d.Key) > 128 {
+		return nil, errors.New("key too long (max 128)")
+	}
+	if !IsRegistered(d.Name) {
+		return nil, errors.Wrap(ErrTaskNotRegistered, d.Name)
+	}
+	args, err := json.Marshal(d.Args)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal args")
+	}
+	if err = ValidateArgs(d.Name, args); err != nil {
+		return nil, errors.Wrap(err, "invalid args")
+	}
+	if d.Cron == "" {
+		return nil, errors.New("cron required")
+	}
+	policy := lo.Ternary(d.MisfirePolicy == "", model.MisfirePolicySkip, d.MisfirePolicy)
+	if !lo.Contains(
+		[]model.MisfirePolicy{model.MisfirePolicySkip, model.MisfirePolicyRunOnce, model.MisfirePolicyRunAll}, policy,
+	) {
+		return nil, errors.Errorf("invalid misfire policy %s", d.MisfirePolicy)
+	}
+	if d.MisfireLimit < 0 || d.MisfireLimit > 100 {
+		return nil, errors.New("misfire limit must be between 0 and 100")
+	}
+
+	task := &model.PeriodicTask{
+		Cron:          d.Cron,
+		Timezone:      d.Timezone,
+		MisfirePolicy: policy,
+		MisfireLimit:  lo.Ternary(d.MisfireLimit == 0, 10, d.MisfireLimit),
+		Name:          d.Name,
+		Args:          args,
+		Enabled:       d.Enabled == nil || *d.Enabled,
+		ManagedKey:    d.Key,
+	}
+	if _, err = ParseSchedule(task); err != nil {
+		return nil, errors.Wrap(err, "invalid cron")
+	}
+	return task, nil
+}
+
+// PeriodicTaskSyncAction 同步声明式定义时对周期任务的操作
+type PeriodicTaskSyncAction string
+
+const (
+	// PeriodicTaskSyncCreated 新增的定义，创建周期任务
+	PeriodicTaskSyncCreated PeriodicTaskSyncAction = "created"
+	// PeriodicTaskSyncUpdated 定义有变更，更新周期任务
+	PeriodicTaskSyncUpdated PeriodicTaskSyncAction = "updated"
+	// PeriodicTaskSyncDisabled 定义已移除，禁用周期任务（保留触发记录，不删除）
+	PeriodicTaskSyncDisabled PeriodicTaskSyncAction = "disabled"
+	// PeriodicTaskSyncUnchanged 定义无变更
+	PeriodicTaskSyncUnchanged PeriodicTaskSyncAction = "unchanged"
+)
+
+// PeriodicTaskSyncResult 单个周期任务的同步结果
+type PeriodicTaskSyncResult struct {
+	Key    string
+	ID     int64
+	Name   string
+	Action PeriodicTaskSyncAction
+}
+
+// SyncPeriodicTasks 将声明式定义同步到 DB：创建新增的、更新有变更的、禁用已移除的周期任务，返回各周期任务的同步结果
+// 任一定义不合法时不做任何变更；dryRun 为 true 时仅返回同步结果，不做变更（新增的周期任务 ID 为 0）
+// 注：同步的周期任务会标记 ManagedKey，API / 页面对其的修改会被拒绝；未标记的周期任务不受影响
+func SyncPeriodicTasks(
+	ctx context.Context, defs []PeriodicTaskDefinition, operator string, dryRun bool,
+) ([]PeriodicTaskSyncResult, error) {
+	declared := make([]*model.PeriodicTask, 0, len(defs))
+	for _, def := range defs {
+		task, err := def.toPeriodicTask()
+		if err != nil {
+			return nil, errors.Wrapf(err, "periodic task definition %s", lo.Ternary(def.Key == "", def.Name, def.Key))
+		}
+		if lo.ContainsBy(declared, func(t *model.PeriodicTask) bool { return t.ManagedKey == def.Key }) {
+			return nil, errors.Errorf("periodic task definition %s: duplicate key", def.Key)
+		}
+		declared = append(declared, task)
+	}
+
+	results := []PeriodicTaskSyncResult{}
+	err := database.Client(ctx).Transaction(func(tx *gorm.DB) error {
+		var managed []model.PeriodicTask
+		if err := tx.Where("managed_key != ''").Find(&managed).Error; err != nil {
+			return err
+		}
+		existing := lo.KeyBy(managed, func(t model.PeriodicTask) string { return t.ManagedKey })
+
+		for _, task := range declared {
+			result := PeriodicTaskSyncResult{Key: task.ManagedKey, Name: task.Name}
+			cur, ok := existing[task.ManagedKey]
+			switch {
+			case !ok:
+				result.Action = PeriodicTaskSyncCreated
+				task.Creator, task.Updater = operator, operator
+				if !dryRun {
+					if err := tx.Create(task).Error; err != nil {
+						return err
+					}
+				}
+				result.ID = task.ID
+			case samePeriodicTask(&cur, task):
+				result.ID, result.Action = cur.ID, PeriodicTaskSyncUnchanged
+			default:
+				result.ID, result.Action = cur.ID, PeriodicTaskSyncUpdated
+				if !dryRun {
+					if err := tx.Model(&cur).Updates(syncedValues(task, operator)).Error; err != nil {
+						return err
+					}
+				}
+			}
+			results = append(results, result)
+		}
+
+		// 定义已移除的周期任务：禁用而非删除，保留触发记录便于追溯
+		for _, cur := range managed {
+			if lo.ContainsBy(declared, func(t *model.PeriodicTask) bool { return t.ManagedKey == cur.ManagedKey }) {
+				continue
+			}
+			if !cur.Enabled {
+				continue
+			}
+			results = append(results, PeriodicTaskSyncResult{
+				Key: cur.ManagedKey, ID: cur.ID, Name: cur.Name, Action: PeriodicTaskSyncDisabled,
+			})
+			if !dryRun {
+				values := map[string]any{"enabled": false, "updater": operator}
+				if err := tx.Model(&cur).Updates(values).Error; err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "sync periodic tasks")
+	}
+
+	if !dryRun {
+		for _, result := range results {
+			if result.Action != PeriodicTaskSyncUnchanged {
+				log.Infof(ctx, "periodic task %s (id: %d) %s by declarative definition", result.Key, result.ID, result.Action)
+				// 通知 scheduler 立即重新注册该周期任务
+				NotifyPeriodicTaskChanged(ctx, result.ID)
+			}
+		}
+	}
+	return results, nil
+}
+
+// SyncPeriodicTasksFromFile 从 YAML 文件加载声明式定义并同步到 DB
+func SyncPeriodicTasksFromFile(
+	ctx context.Context, path, operator string, dryRun bool,
+) ([]PeriodicTaskSyncResult, error) {
+	defs, err := LoadPeriodicTaskDefinitions(path)
+	if err != nil {
+		return nil, err
+	}
+	return SyncPeriodicTasks(ctx, defs, operator, dryRun)
+}
+
+// 已同步的周期任务与定义是否一致（参数按 JSON 语义比较，MySQL JSON 类型会重新格式化）
+func samePeriodicTask(cur, declared *model.PeriodicTask) bool {
+	return cur.Name == declared.Name &&
+		cur.Cron == declared.Cron &&
+		cur.Timezone == declared.Timezone &&
+		cur.MisfirePolicy == declared.MisfirePolicy &&
+		cur.MisfireLimit == declared.MisfireLimit &&
+		cur.Enabled == declared.Enabled &&
+		bytes.Equal(normalizeJSON(cur.Args), normalizeJSON(declared.Args))
+}
+
+// 按定义更新周期任务的字段（使用 map 以更新零值，如禁用 / 清空时区）
+func syncedValues(task *model.PeriodicTask, operator string) map[string]any {
+	return map[string]any{
+		"name":           task.Name,
+		"cron":           task.Cron,
+		"timezone":       task.Timezone,
+		"misfire_policy": task.MisfirePolicy,
+		"misfire_limit":  task.MisfireLimit,
+		"args":           task.Args,
+		"enabled":        task.Enabled,
+		"updater":        operator,
+	}
+}
@@ -0,0 +1,128 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - Go 开发框架 (BlueKing - Go Framework) available.
+ * Copyright (C) 2017 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ *	https://opensource.org/licenses/MIT
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package async
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/TencentBlueKing/blueapps-go/pkg/model"
+)
+
+func TestLoadPeriodicTaskDefinitions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "periodic_tasks.yaml")
+	content := `
+periodicTasks:
+  - key: greet-daily
+    name: greet
+    cron: "0 8 * * *"
+    timezone: Asia/Shanghai
+    args:
+      name: blueking
+      times: 2
+  - key: greet-disabled
+    name: greet
+    cron: "@hourly"
+    args: {name: bk}
+    enabled: false
+    misfirePolicy: run_all
+    misfireLimit: 3
+`
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+
+	defs, err := LoadPeriodicTaskDefinitions(path)
+	assert.NoError(t, err)
+	assert.Len(t, defs, 2)
+	assert.Equal(t, "greet-daily", defs[0].Key)
+	assert.Nil(t, defs[0].Enabled)
+	assert.Equal(t, false, *defs[1].Enabled)
+	assert.Equal(t, model.MisfirePolicyRunAll, defs[1].MisfirePolicy)
+
+	// 未知字段（如拼写错误）需报错，避免配置被静默忽略
+	assert.NoError(t, os.WriteFile(path, []byte("periodicTasks:\n  - key: a\n    crontab: '* * * * *'\n"), 0o644))
+	_, err = LoadPeriodicTaskDefinitions(path)
+	assert.Error(t, err)
+
+	// 空文件视为没有定义任何周期任务
+	assert.NoError(t, os.WriteFile(path, nil, 0o644))
+	defs, err = LoadPeriodicTaskDefinitions(path)
+	assert.NoError(t, err)
+	assert.Empty(t, defs)
+}
+
+func TestPeriodicTaskDefinitionToPeriodicTask(t *testing.T) {
+	Register("greet", greet)
+	defer unregister("greet")
+
+	def := PeriodicTaskDefinition{
+		Key:  "greet-daily",
+		Name: "greet",
+		Cron: "0 8 * * *",
+		Args: map[string]any{"name": "blueking", "times": 2},
+	}
+	task, err := def.toPeriodicTask()
+	assert.NoError(t, err)
+	assert.Equal(t, "greet-daily", task.ManagedKey)
+	assert.True(t, task.Managed())
+	assert.True(t, task.Enabled)
+	assert.Equal(t, model.MisfirePolicySkip, task.MisfirePolicy)
+	assert.Equal(t, 10, task.MisfireLimit)
+	assert.JSONEq(t, `{"name": "blueking", "times": 2}`, string(task.Args))
+
+	invalidDefs := map[string]func(d *PeriodicTaskDefinition){
+		"key required":     func(d *PeriodicTaskDefinition) { d.Key = "" },
+		"not registered":   func(d *PeriodicTaskDefinition) { d.Name = "notExists" },
+		"invalid args":     func(d *PeriodicTaskDefinition) { d.Args = map[string]any{"times": 2} },
+		"cron required":    func(d *PeriodicTaskDefinition) { d.Cron = "" },
+		"invalid cron":     func(d *PeriodicTaskDefinition) { d.Cron = "0 25 * * *" },
+		"invalid timezone": func(d *PeriodicTaskDefinition) { d.Timezone = "Mars/Olympus" },
+		"invalid policy":   func(d *PeriodicTaskDefinition) { d.MisfirePolicy = "run_twice" },
+	}
+	for name, mutate := range invalidDefs {
+		t.Run(name, func(t *testing.T) {
+			invalid := def
+			mutate(&invalid)
+			_, err := invalid.toPeriodicTask()
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestSamePeriodicTask(t *testing.T) {
+	declared := &model.PeriodicTask{
+		Name: "greet", Cron: "0 8 * * *", MisfirePolicy: model.MisfirePolicySkip, MisfireLimit: 10, Enabled: true,
+		Args: []byte(`{"times":2,"name":"blueking"}`),
+	}
+	// MySQL JSON 类型会重新格式化参数
+	cur := *declared
+	cur.ID = 1
+	cur.Args = []byte(`{"name": "blueking", "times": 2}`)
+	assert.True(t, samePeriodicTask(&cur, declared))
+
+	cur.Enabled = false
+	assert.False(t, samePeriodicTask(&cur, declared))
+
+	values := syncedValues(declared, "admin")
+	assert.Equal(t, true, values["enabled"])
+	assert.Equal(t, "admin", values["updater"])
+	assert.Contains(t, values, "timezone")
+}
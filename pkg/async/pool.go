@@ -0,0 +1,322 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - Go 开发框架 (BlueKing - Go Framework) available.
+ * Copyright (C) 2017 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ *	https://opensource.org/licenses/MIT
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package async
+
+import (
+	"container/heap"
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/TencentBlueKing/blueapps-go/pkg/config"
+	log "github.com/TencentBlueKing/blueapps-go/pkg/logging"
+)
+
+const (
+	// DefaultQueue 默认任务队列
+	DefaultQueue = "default"
+	// 默认队列容量
+	defaultQueueCapacity = 1000
+)
+
+var (
+	// ErrQueueFull 任务队列已满（背压），调用方应稍后重试
+	ErrQueueFull = errors.New("task queue is full")
+	// ErrQueueNotFound 任务队列未配置
+	ErrQueueNotFound = errors.New("task queue not found")
+	// ErrPoolClosed worker pool 已关闭
+	ErrPoolClosed = errors.New("worker pool closed")
+)
+
+// 获取配置的任务队列，未配置时仅有一个 default 队列
+func queueConfigs(cfg *config.AsyncConfig) []config.AsyncQueueConfig {
+	if cfg == nil || len(cfg.Queues) == 0 {
+		return []config.AsyncQueueConfig{{Name: DefaultQueue, Capacity: defaultQueueCapacity}}
+	}
+	return cfg.Queues
+}
+
+// 等待执行的任务
+type poolItem struct {
+	ctx   context.Context
+	msg   *Message
+	queue *poolQueue
+	// 入队序号，同优先级的任务按入队顺序执行
+	seq uint64
+	// 执行完成后写入 handler 的返回值，为 nil 表示调用方不关心执行结果
+	done chan error
+}
+
+// 按任务优先级（Message.Priority 越大越优先）、入队顺序排序的堆
+type itemHeap []*poolItem
+
+func (h itemHeap) Len() int { return len(h) }
+
+func (h itemHeap) Less(i, j int) bool {
+	if h[i].msg.Priority != h[j].msg.Priority {
+		return h[i].msg.Priority > h[j].msg.Priority
+	}
+	return h[i].seq < h[j].seq
+}
+
+func (h itemHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *itemHeap) Push(x any) { *h = append(*h, x.(*poolItem)) }
+
+func (h *itemHeap) Pop() any {
+	old := *h
+	item := old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+	return item
+}
+
+// worker pool 中的任务队列
+type poolQueue struct {
+	name     string
+	priority int
+	capacity int
+	items    itemHeap
+	// 等待执行的任务数量（包含因任务并发数限制而暂缓执行的任务）
+	depth int
+}
+
+// workerPool 有界的任务执行池：固定数量的 worker 按队列优先级 & 任务优先级执行任务，
+// 同时限制单个任务名称的并发数（见 WithMaxConcurrency），队列满时拒绝 / 阻塞下发（背压）
+type workerPool struct {
+	handler Handler
+	workers int
+	queues  map[string]*poolQueue
+	// 按优先级从高到低排序的队列
+	ordered []*poolQueue
+
+	lock     sync.Mutex
+	notEmpty *sync.Cond
+	notFull  *sync.Cond
+	seq      uint64
+	closed   bool
+	// 执行中的任务数量：任务名称 -> 数量
+	running map[string]int
+	// 因并发数限制暂缓执行的任务：任务名称 -> 任务（按出队顺序）
+	parked map[string][]*poolItem
+	wg     sync.WaitGroup
+}
+
+func newWorkerPool(cfg *config.AsyncConfig, handler Handler) *workerPool {
+	p := &workerPool{
+		handler: handler,
+		workers: 1,
+		queues:  map[string]*poolQueue{},
+		running: map[string]int{},
+		parked:  map[string][]*poolItem{},
+	}
+	if cfg != nil {
+		p.workers = max(cfg.Concurrency, 1)
+	}
+	for _, queueCfg := range queueConfigs(cfg) {
+		q := &poolQueue{
+			name:     queueCfg.Name,
+			priority: queueCfg.Priority,
+			capacity: queueCfg.Capacity,
+		}
+		if q.capacity <= 0 {
+			q.capacity = defaultQueueCapacity
+		}
+		p.queues[q.name] = q
+		p.ordered = append(p.ordered, q)
+	}
+	sort.SliceStable(p.ordered, func(i, j int) bool {
+		return p.ordered[i].priority > p.ordered[j].priority
+	})
+	p.notEmpty = sync.NewCond(&p.lock)
+	p.notFull = sync.NewCond(&p.lock)
+	return p
+}
+
+// 启动 worker
+func (p *workerPool) start() {
+	for range p.workers {
+		p.wg.Add(1)
+		go p.work()
+	}
+}
+
+// 停止接收任务，等待执行中的任务完成，尚未执行的任务以 ErrPoolClosed 结束
+func (p *workerPool) stop() {
+	p.lock.Lock()
+	p.closed = true
+	var pending []*poolItem
+	for _, q := range p.ordered {
+		pending = append(pending, q.items...)
+		q.items, q.depth = nil, 0
+	}
+	for name, items := range p.parked {
+		pending = append(pending, items...)
+		delete(p.parked, name)
+	}
+	p.notEmpty.Broadcast()
+	p.notFull.Broadcast()
+	p.lock.Unlock()
+
+	for _, item := range pending {
+		p.finish(item, ErrPoolClosed)
+	}
+	p.wg.Wait()
+}
+
+// submit 提交任务，队列满时立即返回 ErrQueueFull，不等待执行结果
+func (p *workerPool) submit(ctx context.Context, msg *Message) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	q, err := p.queue(msg)
+	if err != nil {
+		return err
+	}
+	if q.depth >= q.capacity {
+		return errors.Wrapf(ErrQueueFull, "queue %s (capacity: %d)", q.name, q.capacity)
+	}
+	p.push(&poolItem{ctx: ctx, msg: msg, queue: q})
+	return nil
+}
+
+// execute 提交任务并等待执行结果，队列满时阻塞直到有空位或 ctx 被取消
+func (p *workerPool) execute(ctx context.Context, msg *Message) error {
+	// ctx 被取消时唤醒等待中的调用方
+	stop := context.AfterFunc(ctx, func() {
+		p.lock.Lock()
+		p.notFull.Broadcast()
+		p.lock.Unlock()
+	})
+	defer stop()
+
+	p.lock.Lock()
+	q, err := p.queue(msg)
+	if err != nil {
+		p.lock.Unlock()
+		return err
+	}
+	for q.depth >= q.capacity && !p.closed && ctx.Err() == nil {
+		p.notFull.Wait()
+	}
+	if p.closed {
+		p.lock.Unlock()
+		return ErrPoolClosed
+	}
+	if ctx.Err() != nil {
+		p.lock.Unlock()
+		return ctx.Err()
+	}
+	item := &poolItem{ctx: ctx, msg: msg, queue: q, done: make(chan error, 1)}
+	p.push(item)
+	p.lock.Unlock()
+
+	return <-item.done
+}
+
+// 获取任务消息对应的队列（需持有 lock）
+func (p *workerPool) queue(msg *Message) (*poolQueue, error) {
+	if p.closed {
+		return nil, ErrPoolClosed
+	}
+	name := msg.queue()
+	q, ok := p.queues[name]
+	if !ok {
+		return nil, errors.Wrapf(ErrQueueNotFound, "queue %s", name)
+	}
+	return q, nil
+}
+
+// 任务入队（需持有 lock）
+func (p *workerPool) push(item *poolItem) {
+	p.seq++
+	item.seq = p.seq
+	heap.Push(&item.queue.items, item)
+	item.queue.depth++
+	queueDepth.WithLabelValues(item.queue.name).Set(float64(item.queue.depth))
+	p.notEmpty.Signal()
+}
+
+// 按队列优先级取出下一个可执行的任务，达到并发数上限的任务暂缓执行（需持有 lock）
+func (p *workerPool) next() *poolItem {
+	for _, q := range p.ordered {
+		for q.items.Len() > 0 {
+			item := heap.Pop(&q.items).(*poolItem)
+			name := item.msg.Name
+			if limit := maxConcurrency(name); limit > 0 && p.running[name] >= limit {
+				p.parked[name] = append(p.parked[name], item)
+				continue
+			}
+			return item
+		}
+	}
+	return nil
+}
+
+// worker 循环：取出任务执行，直到 pool 关闭
+func (p *workerPool) work() {
+	defer p.wg.Done()
+	for {
+		p.lock.Lock()
+		item := p.next()
+		for item == nil && !p.closed {
+			p.notEmpty.Wait()
+			item = p.next()
+		}
+		if item == nil {
+			p.lock.Unlock()
+			return
+		}
+		q, name := item.queue, item.msg.Name
+		q.depth--
+		p.running[name]++
+		queueDepth.WithLabelValues(q.name).Set(float64(q.depth))
+		inFlightTasks.WithLabelValues(q.name, name).Inc()
+		p.notFull.Broadcast()
+		p.lock.Unlock()
+
+		err := p.handler(item.ctx, item.msg)
+
+		p.lock.Lock()
+		p.running[name]--
+		inFlightTasks.WithLabelValues(q.name, name).Dec()
+		// 同名任务执行完成后，暂缓执行的任务重新入队（保持原有的入队顺序）
+		if parked := p.parked[name]; len(parked) > 0 {
+			p.parked[name] = parked[1:]
+			heap.Push(&parked[0].queue.items, parked[0])
+			p.notEmpty.Signal()
+		}
+		p.lock.Unlock()
+
+		p.finish(item, err)
+	}
+}
+
+// 通知调用方执行结果，无需等待结果的任务仅打印错误日志
+func (p *workerPool) finish(item *poolItem, err error) {
+	if item.done != nil {
+		item.done <- err
+		return
+	}
+	if err != nil {
+		log.Errorf(item.ctx, "handle task message %s (%s) error: %s", item.msg.ID, item.msg.Name, err)
+	}
+}
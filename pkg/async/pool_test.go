@@ -0,0 +1,132 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - Go 开发框架 (BlueKing - Go Framework) available.
+ * Copyright (C) 2017 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ *	https://opensource.org/licenses/MIT
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package async
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/TencentBlueKing/blueapps-go/pkg/config"
+)
+
+func TestWorkerPoolPriority(t *testing.T) {
+	var order []string
+	var lock sync.Mutex
+	var wg sync.WaitGroup
+	pool := newWorkerPool(&config.AsyncConfig{
+		Concurrency: 1,
+		Queues: []config.AsyncQueueConfig{
+			{Name: "low", Priority: 0, Capacity: 10},
+			{Name: "high", Priority: 10, Capacity: 10},
+		},
+	}, func(_ context.Context, msg *Message) error {
+		lock.Lock()
+		defer lock.Unlock()
+		order = append(order, msg.ID)
+		wg.Done()
+		return nil
+	})
+
+	// 启动前提交任务，以便按优先级排序执行
+	wg.Add(4)
+	assert.NoError(t, pool.submit(context.Background(), &Message{ID: "a", Queue: "low"}))
+	assert.NoError(t, pool.submit(context.Background(), &Message{ID: "b", Queue: "low", Priority: 5}))
+	assert.NoError(t, pool.submit(context.Background(), &Message{ID: "c", Queue: "low"}))
+	assert.NoError(t, pool.submit(context.Background(), &Message{ID: "d", Queue: "high"}))
+	pool.start()
+	wg.Wait()
+	pool.stop()
+
+	assert.Equal(t, []string{"d", "b", "a", "c"}, order)
+}
+
+func TestWorkerPoolBackpressure(t *testing.T) {
+	pool := newWorkerPool(&config.AsyncConfig{
+		Concurrency: 1,
+		Queues:      []config.AsyncQueueConfig{{Name: DefaultQueue, Capacity: 2}},
+	}, func(context.Context, *Message) error { return nil })
+
+	ctx := context.Background()
+	assert.NoError(t, pool.submit(ctx, &Message{ID: "a"}))
+	assert.NoError(t, pool.submit(ctx, &Message{ID: "b"}))
+	assert.ErrorIs(t, pool.submit(ctx, &Message{ID: "c"}), ErrQueueFull)
+	assert.ErrorIs(t, pool.submit(ctx, &Message{ID: "d", Queue: "unknown"}), ErrQueueNotFound)
+
+	// 队列满时阻塞等待，直到 ctx 被取消
+	timeoutCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, pool.execute(timeoutCtx, &Message{ID: "e"}), context.DeadlineExceeded)
+
+	// 开始执行后有空位，可以继续提交并等待执行结果
+	pool.start()
+	assert.NoError(t, pool.execute(ctx, &Message{ID: "f"}))
+	pool.stop()
+
+	assert.ErrorIs(t, pool.submit(ctx, &Message{ID: "g"}), ErrPoolClosed)
+}
+
+func TestWorkerPoolExecuteResult(t *testing.T) {
+	pool := newWorkerPool(&config.AsyncConfig{Concurrency: 1}, func(_ context.Context, msg *Message) error {
+		return errors.New("handle " + msg.ID)
+	})
+	pool.start()
+	defer pool.stop()
+
+	assert.EqualError(t, pool.execute(context.Background(), &Message{ID: "a"}), "handle a")
+}
+
+func TestWorkerPoolMaxConcurrency(t *testing.T) {
+	Register("limitedTask", func(context.Context, struct{}) (any, error) { return nil, nil }, WithMaxConcurrency(1))
+	defer unregister("limitedTask")
+
+	var lock sync.Mutex
+	var wg sync.WaitGroup
+	running, maxRunning := map[string]int{}, map[string]int{}
+	pool := newWorkerPool(&config.AsyncConfig{Concurrency: 4}, func(_ context.Context, msg *Message) error {
+		defer wg.Done()
+		lock.Lock()
+		running[msg.Name]++
+		maxRunning[msg.Name] = max(maxRunning[msg.Name], running[msg.Name])
+		lock.Unlock()
+
+		time.Sleep(20 * time.Millisecond)
+
+		lock.Lock()
+		running[msg.Name]--
+		lock.Unlock()
+		return nil
+	})
+
+	for range 4 {
+		wg.Add(2)
+		assert.NoError(t, pool.submit(context.Background(), &Message{Name: "limitedTask"}))
+		assert.NoError(t, pool.submit(context.Background(), &Message{Name: "otherTask"}))
+	}
+	pool.start()
+	wg.Wait()
+	pool.stop()
+
+	assert.Equal(t, 1, maxRunning["limitedTask"])
+	assert.Greater(t, maxRunning["otherTask"], 1)
+}
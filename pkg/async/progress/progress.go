@@ -0,0 +1,62 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - Go 开发框架 (BlueKing - Go Framework) available.
+ * Copyright (C) 2017 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ *	https://opensource.org/licenses/MIT
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package progress 提供任务执行进度上报：异步任务框架会在任务函数的 context 中注入 Reporter，
+// 任务函数通过 progress.Report 上报的进度会被记录到任务中，并推送到前端页面
+package progress
+
+import (
+	"context"
+)
+
+// Reporter 任务执行进度上报器
+type Reporter interface {
+	// Report 上报进度：percent 为百分比（0 - 100），message 为进度说明，data 为阶段性数据（需可序列化为 JSON，可为 nil）
+	Report(percent float64, message string, data any)
+}
+
+// ReporterFunc 函数形式的进度上报器
+type ReporterFunc func(percent float64, message string, data any)
+
+// Report ...
+func (f ReporterFunc) Report(percent float64, message string, data any) {
+	f(percent, message, data)
+}
+
+type ctxKey struct{}
+
+// 未注入进度上报器时（如单元测试）使用的空实现
+var noopReporter = ReporterFunc(func(float64, string, any) {})
+
+// WithReporter 在 context 中注入进度上报器
+func WithReporter(ctx context.Context, reporter Reporter) context.Context {
+	return context.WithValue(ctx, ctxKey{}, reporter)
+}
+
+// FromContext 获取 context 中的进度上报器，未注入时返回空实现
+func FromContext(ctx context.Context) Reporter {
+	if reporter, ok := ctx.Value(ctxKey{}).(Reporter); ok {
+		return reporter
+	}
+	return noopReporter
+}
+
+// Report 通过 context 中的进度上报器上报进度
+func Report(ctx context.Context, percent float64, message string, data any) {
+	FromContext(ctx).Report(percent, message, data)
+}
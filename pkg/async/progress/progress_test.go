@@ -0,0 +1,39 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - Go 开发框架 (BlueKing - Go Framework) available.
+ * Copyright (C) 2017 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ *	https://opensource.org/licenses/MIT
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package progress
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReport(t *testing.T) {
+	// 未注入进度上报器时不会 panic
+	Report(context.Background(), 50, "half", nil)
+
+	var reported []float64
+	ctx := WithReporter(context.Background(), ReporterFunc(func(percent float64, message string, data any) {
+		reported = append(reported, percent)
+	}))
+	Report(ctx, 10, "start", nil)
+	FromContext(ctx).Report(100, "done", map[string]int{"n": 1})
+	assert.Equal(t, []float64{10, 100}, reported)
+}
@@ -0,0 +1,260 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - Go 开发框架 (BlueKing - Go Framework) available.
+ * Copyright (C) 2017 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ *	https://opensource.org/licenses/MIT
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package async
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"runtime/debug"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/samber/lo"
+)
+
+// ErrTaskNotRegistered 任务未注册
+var ErrTaskNotRegistered = errors.New("task not registered")
+
+// TaskFunc 任务函数，参数由 JSON 解析为 Args 类型，返回值会被序列化为 JSON 记录到任务中
+type TaskFunc[Args, Result any] func(ctx context.Context, args Args) (Result, error)
+
+// ArgsValidator 任务参数实现该接口时，下发任务前会调用 Validate 校验参数
+type ArgsValidator interface {
+	Validate() error
+}
+
+// RegisterOption 注册任务选项
+type RegisterOption func(*taskDef)
+
+// WithRetryPolicy 指定任务的重试策略，未指定时任务执行失败后不会重试
+func WithRetryPolicy(policy RetryPolicy) RegisterOption {
+	return func(d *taskDef) {
+		d.retryPolicy = policy
+	}
+}
+
+// WithDefaultTimeout 指定任务默认的单次执行超时时间，未指定时不限制，下发时可通过 WithTimeout 覆盖
+func WithDefaultTimeout(timeout time.Duration) RegisterOption {
+	return func(d *taskDef) {
+		d.timeout = timeout
+	}
+}
+
+// WithDefaultQueue 指定任务默认下发的队列，未指定时为 default 队列，下发时可通过 WithQueue 覆盖
+func WithDefaultQueue(queue string) RegisterOption {
+	return func(d *taskDef) {
+		d.queue = queue
+	}
+}
+
+// WithMaxConcurrency 限制单个进程内该任务同时执行的数量，未指定时不限制（仅受 worker pool 大小限制）
+func WithMaxConcurrency(n int) RegisterOption {
+	return func(d *taskDef) {
+		d.maxConcurrency = n
+	}
+}
+
+// WithUniqueWhileRunning 声明任务执行期间唯一：相同参数的任务已在等待执行（含等待重试）或执行中时，下发任务会返回 ErrTaskLocked
+// 任务锁在任务结束时释放，lockTTL 为锁的最长持有时间（避免进程崩溃等导致锁无法释放），应大于任务等待 & 执行的最长耗时
+func WithUniqueWhileRunning(lockTTL time.Duration) RegisterOption {
+	return func(d *taskDef) {
+		d.uniqueTTL = lockTTL
+	}
+}
+
+// 已注册任务的定义
+type taskDef struct {
+	name string
+	// 任务函数声明的参数 & 返回值类型
+	argsType   reflect.Type
+	resultType reflect.Type
+	// 解析 & 校验参数
+	decode func(rawArgs json.RawMessage) (any, error)
+	// 解析参数 & 调用任务函数
+	run func(ctx context.Context, rawArgs json.RawMessage) (any, error)
+
+	retryPolicy    RetryPolicy
+	timeout        time.Duration
+	queue          string
+	maxConcurrency int
+	// 执行期间唯一的任务锁的最长持有时间，为 0 表示不限制唯一性
+	uniqueTTL time.Duration
+}
+
+var (
+	registry     = map[string]*taskDef{}
+	registryLock sync.RWMutex
+)
+
+// Register 注册任务，任务名称重复时 panic（与 http.Handle 一致，应在 init 阶段完成注册）
+func Register[Args, Result any](name string, fn TaskFunc[Args, Result], opts ...RegisterOption) {
+	def := &taskDef{
+		name:        name,
+		argsType:    reflect.TypeFor[Args](),
+		resultType:  reflect.TypeFor[Result](),
+		retryPolicy: RetryPolicy{MaxAttempts: 1},
+	}
+	def.decode = func(rawArgs json.RawMessage) (any, error) {
+		return decodeArgs[Args](rawArgs)
+	}
+	def.run = func(ctx context.Context, rawArgs json.RawMessage) (result any, err error) {
+		// 任务函数（含参数自定义的校验）panic 时转换为错误，任务标记为失败，不影响进程
+		defer recoverPanic(ctx, name, &err)
+
+		args, err := decodeArgs[Args](rawArgs)
+		if err != nil {
+			return nil, NonRetryable(err)
+		}
+		return fn(ctx, args)
+	}
+	for _, opt := range opts {
+		opt(def)
+	}
+
+	registryLock.Lock()
+	defer registryLock.Unlock()
+	if _, ok := registry[name]; ok {
+		panic(fmt.Sprintf("async: task %s registered twice", name))
+	}
+	registry[name] = def
+}
+
+// IsRegistered 任务是否已注册
+func IsRegistered(name string) bool {
+	_, err := getTaskDef(name)
+	return err == nil
+}
+
+// RegisteredTaskNames 获取所有已注册任务的名称（按字母序）
+func RegisteredTaskNames() []string {
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// TaskInfo 已注册任务的信息
+type TaskInfo struct {
+	Name string
+	// 任务函数声明的参数 & 返回值类型
+	ArgsType   string
+	ResultType string
+	// 参数类型零值的 JSON，可作为下发任务时的参数示例
+	ArgsExample    string
+	Queue          string
+	Timeout        time.Duration
+	MaxAttempts    int
+	MaxConcurrency int
+	// 执行期间唯一的任务锁的最长持有时间，为 0 表示不限制唯一性
+	UniqueTTL time.Duration
+}
+
+// RegisteredTasks 获取所有已注册任务的信息（按名称字母序）
+func RegisteredTasks() []TaskInfo {
+	names := RegisteredTaskNames()
+	infos := make([]TaskInfo, 0, len(names))
+	for _, name := range names {
+		def, err := getTaskDef(name)
+		if err != nil {
+			continue
+		}
+		example, _ := json.Marshal(reflect.Zero(def.argsType).Interface())
+		infos = append(infos, TaskInfo{
+			Name:           def.name,
+			ArgsType:       def.argsType.String(),
+			ResultType:     def.resultType.String(),
+			ArgsExample:    string(example),
+			Queue:          lo.Ternary(def.queue == "", DefaultQueue, def.queue),
+			Timeout:        def.timeout,
+			MaxAttempts:    def.retryPolicy.MaxAttempts,
+			MaxConcurrency: def.maxConcurrency,
+			UniqueTTL:      def.uniqueTTL,
+		})
+	}
+	return infos
+}
+
+// ValidateArgs 按任务声明的参数类型校验参数（JSON）
+func ValidateArgs(name string, rawArgs json.RawMessage) error {
+	def, err := getTaskDef(name)
+	if err != nil {
+		return err
+	}
+	_, err = def.decode(rawArgs)
+	return err
+}
+
+// 获取任务定义
+func getTaskDef(name string) (*taskDef, error) {
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+
+	def, ok := registry[name]
+	if !ok {
+		return nil, errors.Wrap(ErrTaskNotRegistered, name)
+	}
+	return def, nil
+}
+
+// 获取任务在单个进程内的最大并发数，为 0 表示不限制
+func maxConcurrency(name string) int {
+	if def, err := getTaskDef(name); err == nil {
+		return def.maxConcurrency
+	}
+	return 0
+}
+
+// 将 JSON 参数严格解析为 Args 类型（不允许未知字段），并执行参数自定义的校验；
+// 参数自定义的反序列化 / 校验 panic 时转换为 PanicError，避免下发任务（如 ApplyTask / ValidateArgs）时进程崩溃
+func decodeArgs[Args any](rawArgs json.RawMessage) (args Args, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			panicErr := &PanicError{Value: r, stack: string(debug.Stack())}
+			err = errors.Wrapf(panicErr, "decode args %s into %s", rawArgs, reflect.TypeFor[Args]())
+		}
+	}()
+
+	if len(bytes.TrimSpace(rawArgs)) != 0 {
+		decoder := json.NewDecoder(bytes.NewReader(rawArgs))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&args); err != nil {
+			return args, errors.Wrapf(err, "decode args %s into %s", rawArgs, reflect.TypeFor[Args]())
+		}
+	}
+	if validator, ok := any(args).(ArgsValidator); ok {
+		if err := validator.Validate(); err != nil {
+			return args, err
+		}
+	} else if validator, ok := any(&args).(ArgsValidator); ok {
+		if err := validator.Validate(); err != nil {
+			return args, err
+		}
+	}
+	return args, nil
+}
@@ -0,0 +1,155 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - Go 开发框架 (BlueKing - Go Framework) available.
+ * Copyright (C) 2017 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ *	https://opensource.org/licenses/MIT
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package async
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+)
+
+// 注销任务（仅用于单元测试）
+func unregister(name string) {
+	registryLock.Lock()
+	defer registryLock.Unlock()
+	delete(registry, name)
+}
+
+type greetArgs struct {
+	Name  string `json:"name"`
+	Times int    `json:"times"`
+}
+
+func (a *greetArgs) Validate() error {
+	if a.Name == "" {
+		return errors.New("name required")
+	}
+	return nil
+}
+
+func greet(_ context.Context, args greetArgs) ([]string, error) {
+	greetings := []string{}
+	for range args.Times {
+		greetings = append(greetings, "hello "+args.Name)
+	}
+	return greetings, nil
+}
+
+func TestRegister(t *testing.T) {
+	Register("greet", greet)
+	defer unregister("greet")
+
+	assert.True(t, IsRegistered("greet"))
+	assert.Contains(t, RegisteredTaskNames(), "greet")
+	assert.Panics(t, func() { Register("greet", greet) })
+
+	_, err := getTaskDef("notExists")
+	assert.ErrorIs(t, err, ErrTaskNotRegistered)
+}
+
+func TestValidateArgs(t *testing.T) {
+	Register("greet", greet)
+	defer unregister("greet")
+
+	assert.NoError(t, ValidateArgs("greet", json.RawMessage(`{"name": "blueking", "times": 2}`)))
+	// 类型不匹配
+	assert.Error(t, ValidateArgs("greet", json.RawMessage(`{"name": "blueking", "times": "2"}`)))
+	// 未知字段
+	assert.Error(t, ValidateArgs("greet", json.RawMessage(`{"name": "blueking", "count": 2}`)))
+	// 自定义校验
+	assert.Error(t, ValidateArgs("greet", json.RawMessage(`{"times": 2}`)))
+	assert.Error(t, ValidateArgs("greet", nil))
+	// 未注册的任务
+	assert.ErrorIs(t, ValidateArgs("notExists", json.RawMessage(`{}`)), ErrTaskNotRegistered)
+
+	assert.NoError(t, ValidateArgs("CalcFib", json.RawMessage(`{"n": 10}`)))
+	assert.Error(t, ValidateArgs("CalcFib", json.RawMessage(`{"n": -1}`)))
+	assert.Error(t, ValidateArgs("CalcFib", json.RawMessage(`[10]`)))
+
+	assert.NoError(t, ValidateArgs("PurgeSoftDeleted", json.RawMessage(`{"retentionDays": 30}`)))
+	assert.Error(t, ValidateArgs("PurgeSoftDeleted", json.RawMessage(`{"retentionDays": 0}`)))
+}
+
+func TestExecute(t *testing.T) {
+	Register("greet", greet)
+	defer unregister("greet")
+
+	result, err := execute(context.Background(), "greet", json.RawMessage(`{"name": "blueking", "times": 2}`))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"hello blueking", "hello blueking"}, result)
+
+	result, err = execute(context.Background(), "CalcFib", json.RawMessage(`{"n": 10}`))
+	assert.NoError(t, err)
+	assert.Equal(t, 55, result)
+
+	// 参数无法解析 / 任务未注册的错误不可重试
+	_, err = execute(context.Background(), "greet", json.RawMessage(`{"times": "2"}`))
+	assert.False(t, getRetryPolicy("CalcFib").shouldRetry(1, err))
+	_, err = execute(context.Background(), "notExists", json.RawMessage(`{}`))
+	assert.ErrorIs(t, err, ErrTaskNotRegistered)
+	assert.False(t, getRetryPolicy("CalcFib").shouldRetry(1, err))
+}
+
+func TestRegisteredTasks(t *testing.T) {
+	Register("greet", greet, WithDefaultQueue("critical"), WithMaxConcurrency(2))
+	defer unregister("greet")
+
+	tasks := RegisteredTasks()
+	greetTask, ok := lo.Find(tasks, func(task TaskInfo) bool { return task.Name == "greet" })
+	assert.True(t, ok)
+	assert.Equal(t, "async.greetArgs", greetTask.ArgsType)
+	assert.Equal(t, `{"name":"","times":0}`, greetTask.ArgsExample)
+	assert.Equal(t, "[]string", greetTask.ResultType)
+	assert.Equal(t, "critical", greetTask.Queue)
+	assert.Equal(t, 1, greetTask.MaxAttempts)
+	assert.Equal(t, 2, greetTask.MaxConcurrency)
+
+	calcFib, ok := lo.Find(tasks, func(task TaskInfo) bool { return task.Name == "CalcFib" })
+	assert.True(t, ok)
+	assert.Equal(t, DefaultQueue, calcFib.Queue)
+	assert.Equal(t, time.Minute, calcFib.Timeout)
+}
+
+func TestRunTask(t *testing.T) {
+	Register("greet", greet)
+	defer unregister("greet")
+
+	result, err := RunTask(context.Background(), "greet", json.RawMessage(`{"name": "blueking", "times": 1}`), 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"hello blueking"}, result)
+
+	_, err = RunTask(context.Background(), "greet", json.RawMessage(`{"times": 1}`), 0)
+	assert.Error(t, err)
+	_, err = RunTask(context.Background(), "notExists", nil, 0)
+	assert.ErrorIs(t, err, ErrTaskNotRegistered)
+
+	// 执行超时
+	Register("sleep", func(ctx context.Context, _ struct{}) (any, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	defer unregister("sleep")
+	_, err = RunTask(context.Background(), "sleep", nil, 10*time.Millisecond)
+	assert.ErrorIs(t, err, ErrTaskTimeout)
+}
@@ -0,0 +1,210 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - Go 开发框架 (BlueKing - Go Framework) available.
+ * Copyright (C) 2017 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ *	https://opensource.org/licenses/MIT
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package async
+
+import (
+	"context"
+	"time"
+
+	mapset "github.com/deckarep/golang-set/v2"
+	"github.com/pkg/errors"
+	"github.com/spf13/cast"
+	"gorm.io/gorm"
+
+	"github.com/TencentBlueKing/blueapps-go/pkg/config"
+	"github.com/TencentBlueKing/blueapps-go/pkg/infras/database"
+	"github.com/TencentBlueKing/blueapps-go/pkg/infras/redis"
+	log "github.com/TencentBlueKing/blueapps-go/pkg/logging"
+	"github.com/TencentBlueKing/blueapps-go/pkg/model"
+)
+
+const (
+	// 周期任务变更通知的 Redis 频道，消息内容为周期任务 ID
+	periodicTaskChangedChannel = "blueapps-go:async:periodic-tasks:changed"
+	// 未启用 Redis 时，轮询周期任务变更（updated_at）的时间间隔
+	periodicTaskPollInterval = 3 * time.Second
+)
+
+// 是否启用了 Redis 增强服务
+func redisEnabled() bool {
+	return config.G != nil && config.G.Platform.Addons.Redis != nil
+}
+
+// NotifyPeriodicTaskChanged 通知 scheduler 周期任务已变更（新增 / 启停 / 删除等），scheduler 会立即重新注册该任务
+// 注：未启用 Redis 时无需通知，scheduler 会轮询 updated_at 感知变更；通知失败也不影响变更生效，仅会延迟到下次全量重载
+func NotifyPeriodicTaskChanged(ctx context.Context, periodicTaskID int64) {
+	if !redisEnabled() {
+		return
+	}
+	if err := redis.Client().Publish(ctx, periodicTaskChangedChannel, periodicTaskID).Err(); err != nil {
+		log.Warnf(ctx, "notify periodic task %d changed error: %s", periodicTaskID, err)
+	}
+}
+
+// 监听周期任务变更并重新注册受影响的任务，阻塞直到 ctx 被取消
+func (s *TaskScheduler) watchChanges(ctx context.Context) {
+	if redisEnabled() {
+		s.subscribeChanges(ctx)
+	} else {
+		s.pollChanges(ctx)
+	}
+}
+
+// 订阅 Redis 频道感知周期任务变更（go-redis 会在连接断开后自动重新订阅）
+func (s *TaskScheduler) subscribeChanges(ctx context.Context) {
+	pubsub := redis.Client().Subscribe(ctx, periodicTaskChangedChannel)
+	defer pubsub.Close()
+
+	ch := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-ch:
+			if !ok {
+				return
+			}
+			periodicTaskID, err := cast.ToInt64E(msg.Payload)
+			if err != nil {
+				log.Warnf(s.ctx, "invalid periodic task changed message: %s", msg.Payload)
+				continue
+			}
+			if err = s.reloadTask(periodicTaskID); err != nil {
+				log.Errorf(s.ctx, "failed to reload periodic task %d: %s", periodicTaskID, err)
+			}
+		}
+	}
+}
+
+// 轮询 updated_at 水位感知周期任务的新增 / 变更，并对比 ID 感知删除
+func (s *TaskScheduler) pollChanges(ctx context.Context) {
+	// 以最近一次变更时间作为初始水位（此前的变更已在成为 leader 时全量加载）
+	var latest model.PeriodicTask
+	err := database.Client(ctx).Order("updated_at DESC").Limit(1).Find(&latest).Error
+	if err != nil {
+		log.Errorf(s.ctx, "failed to get periodic tasks watermark: %s", err)
+	}
+	watermark := newChangeWatermark(latest.UpdatedAt)
+
+	ticker := time.NewTicker(periodicTaskPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		// updated_at 精度有限（如秒级），与水位同一时刻提交的变更需要用 >= 查询，再按（ID，updated_at）去重
+		var changedTasks []model.PeriodicTask
+		err := database.Client(ctx).Where("updated_at >= ?", watermark.at).Find(&changedTasks).Error
+		if err != nil {
+			log.Errorf(s.ctx, "failed to poll changed periodic tasks: %s", err)
+			continue
+		}
+		for _, task := range watermark.advance(changedTasks) {
+			if err := s.reloadTask(task.ID); err != nil {
+				log.Errorf(s.ctx, "failed to reload periodic task %d: %s", task.ID, err)
+			}
+		}
+
+		var taskIDs []int64
+		if err := database.Client(ctx).Model(&model.PeriodicTask{}).Pluck("id", &taskIDs).Error; err != nil {
+			log.Errorf(s.ctx, "failed to poll periodic task ids: %s", err)
+			continue
+		}
+		s.reloadLock.Lock()
+		s.unregisterMissing(mapset.NewSet(taskIDs...))
+		s.reloadLock.Unlock()
+	}
+}
+
+// 周期任务的一次变更（ID + 变更时间）
+type periodicTaskChange struct {
+	id        int64
+	updatedAt int64
+}
+
+// 周期任务变更的轮询水位：记录最近的变更时间，及该时刻已处理过的变更
+type changeWatermark struct {
+	at   time.Time
+	seen mapset.Set[periodicTaskChange]
+}
+
+func newPeriodicTaskChange(task model.PeriodicTask) periodicTaskChange {
+	return periodicTaskChange{id: task.ID, updatedAt: task.UpdatedAt.UnixNano()}
+}
+
+func newChangeWatermark(at time.Time) *changeWatermark {
+	return &changeWatermark{at: at, seen: mapset.NewThreadUnsafeSet[periodicTaskChange]()}
+}
+
+// 过滤掉已处理过的变更，并推进水位
+func (w *changeWatermark) advance(tasks []model.PeriodicTask) []model.PeriodicTask {
+	changed := make([]model.PeriodicTask, 0, len(tasks))
+	at := w.at
+	for _, task := range tasks {
+		if task.UpdatedAt.Before(w.at) || w.seen.Contains(newPeriodicTaskChange(task)) {
+			continue
+		}
+		changed = append(changed, task)
+		if task.UpdatedAt.After(at) {
+			at = task.UpdatedAt
+		}
+	}
+
+	// 水位推进后，仅需记住新水位时刻的变更
+	if at.After(w.at) {
+		w.at = at
+		w.seen.Clear()
+	}
+	for _, task := range changed {
+		if task.UpdatedAt.Equal(w.at) {
+			w.seen.Add(newPeriodicTaskChange(task))
+		}
+	}
+	return changed
+}
+
+// 重新注册单个周期任务：已删除 / 禁用的注销，调度配置变更的重新注册
+func (s *TaskScheduler) reloadTask(periodicTaskID int64) error {
+	s.reloadLock.Lock()
+	defer s.reloadLock.Unlock()
+
+	var task model.PeriodicTask
+	err := database.Client(s.ctx).First(&task, periodicTaskID).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	if err != nil || !task.Enabled {
+		s.unregister(periodicTaskID)
+		return nil
+	}
+	return s.register(task)
+}
+
+// 注销不在 taskIDs 中（已被删除 / 禁用）的周期任务（需持有 reloadLock）
+func (s *TaskScheduler) unregisterMissing(taskIDs mapset.Set[int64]) {
+	for _, taskID := range s.taskEntryMap.ids() {
+		if !taskIDs.Contains(taskID) {
+			s.unregister(taskID)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - Go 开发框架 (BlueKing - Go Framework) available.
+ * Copyright (C) 2017 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ *	https://opensource.org/licenses/MIT
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package async
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/TencentBlueKing/blueapps-go/pkg/model"
+)
+
+func TestChangeWatermark(t *testing.T) {
+	at := time.Date(2026, 10, 18, 10, 0, 0, 0, time.Local)
+	task := func(id int64, updatedAt time.Time) model.PeriodicTask {
+		task := model.PeriodicTask{ID: id}
+		task.UpdatedAt = updatedAt
+		return task
+	}
+	ids := func(tasks []model.PeriodicTask) []int64 {
+		return lo.Map(tasks, func(task model.PeriodicTask, _ int) int64 { return task.ID })
+	}
+
+	w := newChangeWatermark(at)
+	// 与水位同一时刻的变更也需要处理（乱序返回时不能漏掉较早的变更）
+	changed := w.advance([]model.PeriodicTask{task(2, at.Add(time.Second)), task(1, at)})
+	assert.Equal(t, []int64{2, 1}, ids(changed))
+	assert.Equal(t, at.Add(time.Second), w.at)
+
+	// 同一秒内提交的新变更（上次轮询时尚未提交）需要处理，已处理过的跳过
+	changed = w.advance([]model.PeriodicTask{task(2, at.Add(time.Second)), task(3, at.Add(time.Second))})
+	assert.Equal(t, []int64{3}, ids(changed))
+
+	// 同一任务再次变更
+	changed = w.advance([]model.PeriodicTask{task(2, at.Add(time.Second)), task(2, at.Add(2*time.Second))})
+	assert.Equal(t, []int64{2}, ids(changed))
+	assert.Equal(t, at.Add(2*time.Second), w.at)
+
+	// 没有新的变更
+	assert.Empty(t, w.advance([]model.PeriodicTask{task(2, at.Add(2*time.Second))}))
+}
+
+func TestSchedulerRegister(t *testing.T) {
+	s := &TaskScheduler{
+		ctx:          context.Background(),
+		cron:         cron.New(),
+		taskEntryMap: &taskEntryMap{mapping: make(map[int64]entry)},
+	}
+	task := model.PeriodicTask{ID: 1, Name: "test", Cron: "*/5 * * * *", Enabled: true}
+	assert.NoError(t, s.register(task))
+	e, ok := s.taskEntryMap.get(task.ID)
+	assert.True(t, ok)
+
+	// 调度配置未变更（如仅修改参数），不重新注册
+	task.Args = []byte(`{"a":1}`)
+	task.UpdatedAt = time.Now()
+	assert.NoError(t, s.register(task))
+	same, _ := s.taskEntryMap.get(task.ID)
+	assert.Equal(t, e.id, same.id)
+
+	// 调度配置变更（如通知丢失后的全量重载），重新注册
+	task.Cron = "0 * * * *"
+	assert.NoError(t, s.register(task))
+	changed, _ := s.taskEntryMap.get(task.ID)
+	assert.NotEqual(t, e.id, changed.id)
+	assert.Len(t, s.cron.Entries(), 1)
+	assert.Equal(t, changed.id, s.cron.Entries()[0].ID)
+
+	// 配置非法时返回错误
+	task.Cron = "invalid"
+	assert.Error(t, s.register(task))
+}
+
+func TestSchedulerRegisterOverdueOneOff(t *testing.T) {
+	var overdue []model.PeriodicTask
+	s := &TaskScheduler{
+		ctx:          context.Background(),
+		cron:         cron.New(),
+		taskEntryMap: &taskEntryMap{mapping: make(map[int64]entry)},
+	}
+	done := make(chan struct{})
+	s.fireOverdue = func(task model.PeriodicTask, _ string) {
+		overdue = append(overdue, task)
+		close(done)
+	}
+
+	// ETA 未到的一次性任务，在 ETA 触发
+	eta := time.Now().Add(time.Hour)
+	assert.NoError(t, s.register(model.PeriodicTask{ID: 1, Name: "test", Enabled: true, ETA: eta}))
+	assert.Len(t, s.cron.Entries(), 1)
+	assert.Equal(t, eta, s.cron.Entries()[0].Schedule.Next(time.Now()))
+
+	// ETA 已过的一次性任务不会再被 cron 触发，交由 fireOverdue 处理（补跑 / 记录跳过并标记为已完成）
+	task := model.PeriodicTask{ID: 2, Name: "test", Enabled: true, ETA: time.Now().Add(-time.Hour)}
+	assert.NoError(t, s.register(task))
+	<-done
+	assert.Len(t, s.cron.Entries(), 1)
+	_, ok := s.taskEntryMap.get(task.ID)
+	assert.False(t, ok)
+	assert.Equal(t, []model.PeriodicTask{task}, overdue)
+}
@@ -0,0 +1,138 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - Go 开发框架 (BlueKing - Go Framework) available.
+ * Copyright (C) 2017 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ *	https://opensource.org/licenses/MIT
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package async
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/TencentBlueKing/blueapps-go/pkg/async/progress"
+	"github.com/TencentBlueKing/blueapps-go/pkg/infras/database"
+	log "github.com/TencentBlueKing/blueapps-go/pkg/logging"
+	"github.com/TencentBlueKing/blueapps-go/pkg/model"
+)
+
+const (
+	// 进度写入 DB 的最小间隔，避免任务频繁上报进度导致 DB 压力过大（期间仅记录最新的进度）
+	progressFlushInterval = time.Second
+	// 进度说明的最大长度（字符数）
+	maxProgressMessageLen = 255
+)
+
+// 写入任务记录的进度上报器：按最小间隔节流写入 DB，间隔内的上报会合并为最新的进度延迟写入
+type taskProgressReporter struct {
+	ctx    context.Context
+	taskID int64
+
+	mu        sync.Mutex
+	values    map[string]any
+	flushedAt time.Time
+	timer     *time.Timer
+	stopped   bool
+}
+
+var _ progress.Reporter = (*taskProgressReporter)(nil)
+
+func newTaskProgressReporter(ctx context.Context, taskID int64) *taskProgressReporter {
+	return &taskProgressReporter{ctx: context.WithoutCancel(ctx), taskID: taskID}
+}
+
+// Report ...
+func (r *taskProgressReporter) Report(percent float64, message string, data any) {
+	values, err := progressValues(percent, message, data)
+	if err != nil {
+		log.Warnf(r.ctx, "task %d report progress error: %s", r.taskID, err)
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.stopped {
+		return
+	}
+	r.values = values
+	if wait := progressFlushInterval - time.Since(r.flushedAt); wait > 0 {
+		// 间隔内的上报延迟写入
+		if r.timer == nil {
+			r.timer = time.AfterFunc(wait, r.flush)
+		}
+		return
+	}
+	r.flushLocked()
+}
+
+// 写入待写入的进度
+func (r *taskProgressReporter) flush() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.timer = nil
+	if !r.stopped {
+		r.flushLocked()
+	}
+}
+
+func (r *taskProgressReporter) flushLocked() {
+	if r.values == nil {
+		return
+	}
+	// 仅更新执行中的任务，避免覆盖已结束任务的进度
+	err := database.Client(r.ctx).
+		Model(&model.Task{}).
+		Where("id = ? AND status = ?", r.taskID, model.TaskStatusRunning).
+		Updates(r.values).Error
+	if err != nil {
+		log.Warnf(r.ctx, "task %d save progress error: %s", r.taskID, err)
+	}
+	r.values = nil
+	r.flushedAt = time.Now()
+}
+
+// 本次执行结束：写入最新的进度，此后的上报（如任务超时 / 取消后仍在后台运行的任务函数）会被忽略
+func (r *taskProgressReporter) stop() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.timer != nil {
+		r.timer.Stop()
+		r.timer = nil
+	}
+	r.flushLocked()
+	r.stopped = true
+}
+
+// 进度需要更新的字段
+func progressValues(percent float64, message string, data any) (map[string]any, error) {
+	var rawData []byte
+	if data != nil {
+		var err error
+		if rawData, err = json.Marshal(data); err != nil {
+			return nil, err
+		}
+	}
+	if utf8.RuneCountInString(message) > maxProgressMessageLen {
+		message = string([]rune(message)[:maxProgressMessageLen])
+	}
+	return map[string]any{
+		"progress":         min(max(percent, 0), 100),
+		"progress_message": message,
+		"progress_data":    rawData,
+	}, nil
+}
@@ -0,0 +1,60 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - Go 开发框架 (BlueKing - Go Framework) available.
+ * Copyright (C) 2017 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ *	https://opensource.org/licenses/MIT
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package async
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProgressValues(t *testing.T) {
+	values, err := progressValues(42.5, "half", map[string]int{"n": 1})
+	assert.NoError(t, err)
+	assert.Equal(t, 42.5, values["progress"])
+	assert.Equal(t, "half", values["progress_message"])
+	assert.Equal(t, `{"n":1}`, string(values["progress_data"].([]byte)))
+
+	// 百分比超出范围
+	values, _ = progressValues(120, "", nil)
+	assert.Equal(t, 100.0, values["progress"])
+	assert.Nil(t, values["progress_data"])
+	values, _ = progressValues(-1, "", nil)
+	assert.Equal(t, 0.0, values["progress"])
+
+	// 进度说明过长（按字符截断）
+	values, _ = progressValues(0, strings.Repeat("进", 300), nil)
+	assert.Equal(t, strings.Repeat("进", maxProgressMessageLen), values["progress_message"])
+
+	// 无法序列化的数据
+	_, err = progressValues(0, "", make(chan int))
+	assert.Error(t, err)
+}
+
+func TestTaskProgressReporterStopped(t *testing.T) {
+	reporter := newTaskProgressReporter(context.Background(), 1)
+	reporter.stop()
+
+	// 执行结束后的上报会被忽略
+	reporter.Report(50, "half", nil)
+	assert.Nil(t, reporter.values)
+	assert.Nil(t, reporter.timer)
+}
@@ -0,0 +1,103 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - Go 开发框架 (BlueKing - Go Framework) available.
+ * Copyright (C) 2017 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ *	https://opensource.org/licenses/MIT
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package async
+
+import (
+	"math/rand/v2"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// RetryPolicy 任务重试策略
+type RetryPolicy struct {
+	// 最大执行次数（包含首次执行），小于等于 1 表示不重试
+	MaxAttempts int
+	// 退避时间基数，第 N 次重试前等待 BackoffBase * 2^(N-1)
+	BackoffBase time.Duration
+	// 退避时间上限，为 0 表示不限制
+	BackoffCap time.Duration
+	// 是否在退避时间上添加随机抖动（[退避时间 / 2, 退避时间]），避免大量任务同时重试
+	Jitter bool
+	// 判断错误是否可重试，为空表示除 NonRetryable 标记的错误外均可重试
+	Retryable func(err error) bool
+}
+
+// 是否应该在第 attempt 次执行失败后重试
+func (p RetryPolicy) shouldRetry(attempt int, err error) bool {
+	if attempt >= p.MaxAttempts {
+		return false
+	}
+	var nrErr *nonRetryableError
+	if errors.As(err, &nrErr) {
+		return false
+	}
+	if p.Retryable != nil {
+		return p.Retryable(err)
+	}
+	return true
+}
+
+// Backoff 计算第 attempt 次执行失败后，下次重试前需要等待的时间
+func (p RetryPolicy) Backoff(attempt int) time.Duration {
+	backoff := p.BackoffBase
+	for i := 1; i < attempt; i++ {
+		// 达到上限 / 溢出时不再继续翻倍
+		if (p.BackoffCap > 0 && backoff >= p.BackoffCap) || backoff > backoff*2 {
+			break
+		}
+		backoff *= 2
+	}
+	if p.BackoffCap > 0 {
+		backoff = min(backoff, p.BackoffCap)
+	}
+	if p.Jitter && backoff > 1 {
+		half := backoff / 2
+		backoff = half + rand.N(backoff-half+1)
+	}
+	return backoff
+}
+
+// 获取任务的重试策略，未声明时不重试
+func getRetryPolicy(name string) RetryPolicy {
+	if def, err := getTaskDef(name); err == nil {
+		return def.retryPolicy
+	}
+	return RetryPolicy{MaxAttempts: 1}
+}
+
+type nonRetryableError struct {
+	err error
+}
+
+func (e *nonRetryableError) Error() string {
+	return e.err.Error()
+}
+
+func (e *nonRetryableError) Unwrap() error {
+	return e.err
+}
+
+// NonRetryable 标记错误不可重试，任务函数返回该错误时会直接失败
+func NonRetryable(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &nonRetryableError{err: err}
+}
@@ -0,0 +1,65 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - Go 开发框架 (BlueKing - Go Framework) available.
+ * Copyright (C) 2017 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ *	https://opensource.org/licenses/MIT
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package async
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 10, BackoffBase: time.Second, BackoffCap: 10 * time.Second}
+
+	assert.Equal(t, time.Second, policy.Backoff(1))
+	assert.Equal(t, 2*time.Second, policy.Backoff(2))
+	assert.Equal(t, 4*time.Second, policy.Backoff(3))
+	assert.Equal(t, 8*time.Second, policy.Backoff(4))
+	assert.Equal(t, 10*time.Second, policy.Backoff(5))
+	assert.Equal(t, 10*time.Second, policy.Backoff(100))
+}
+
+func TestRetryPolicyBackoffWithJitter(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 10, BackoffBase: time.Second, BackoffCap: time.Minute, Jitter: true}
+
+	for range 100 {
+		backoff := policy.Backoff(3)
+		assert.GreaterOrEqual(t, backoff, 2*time.Second)
+		assert.LessOrEqual(t, backoff, 4*time.Second)
+	}
+}
+
+func TestRetryPolicyShouldRetry(t *testing.T) {
+	errTimeout := errors.New("timeout")
+	errInvalid := errors.New("invalid")
+
+	policy := RetryPolicy{MaxAttempts: 3}
+	assert.True(t, policy.shouldRetry(1, errTimeout))
+	assert.True(t, policy.shouldRetry(2, errTimeout))
+	assert.False(t, policy.shouldRetry(3, errTimeout))
+	assert.False(t, policy.shouldRetry(1, NonRetryable(errTimeout)))
+
+	policy.Retryable = func(err error) bool { return errors.Is(err, errTimeout) }
+	assert.True(t, policy.shouldRetry(1, errors.Wrap(errTimeout, "call api")))
+	assert.False(t, policy.shouldRetry(1, errInvalid))
+
+	assert.False(t, getRetryPolicy("NotExists").shouldRetry(1, errTimeout))
+}
@@ -0,0 +1,111 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - Go 开发框架 (BlueKing - Go Framework) available.
+ * Copyright (C) 2017 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ *	https://opensource.org/licenses/MIT
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package async
+
+import (
+	"fmt"
+	"time"
+	// 内嵌时区数据，避免运行环境（如精简的容器镜像）缺少 zoneinfo 导致无法解析周期任务时区
+	_ "time/tzdata"
+
+	"github.com/pkg/errors"
+	"github.com/robfig/cron/v3"
+
+	"github.com/TencentBlueKing/blueapps-go/pkg/model"
+)
+
+// 周期任务 cron 表达式解析器：支持可选的秒级字段（6 位）及 @daily 等描述符
+var cronParser = cron.NewParser(
+	cron.SecondOptional | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor,
+)
+
+// ParseSchedule 解析周期任务的调度计划：
+// - 设置了 ETA 的为一次性任务，仅在 ETA 触发一次
+// - 否则按 cron 表达式（支持秒级字段）在指定时区（为空则为 scheduler 所在机器时区）触发，且仅在 [StartAt, EndAt] 内触发
+func ParseSchedule(task *model.PeriodicTask) (cron.Schedule, error) {
+	if !task.ETA.IsZero() {
+		return &oneOffSchedule{at: task.ETA}, nil
+	}
+
+	spec := task.Cron
+	if task.Timezone != "" {
+		if _, err := time.LoadLocation(task.Timezone); err != nil {
+			return nil, errors.Wrapf(err, "invalid timezone %s", task.Timezone)
+		}
+		spec = "CRON_TZ=" + task.Timezone + " " + spec
+	}
+	schedule, err := cronParser.Parse(spec)
+	if err != nil {
+		return nil, err
+	}
+	if task.StartAt.IsZero() && task.EndAt.IsZero() {
+		return schedule, nil
+	}
+	return &windowSchedule{schedule: schedule, startAt: task.StartAt, endAt: task.EndAt}, nil
+}
+
+// 周期任务中影响调度注册的配置（调度计划 & 任务名称），用于判断已注册的周期任务是否需要重新注册
+func scheduleSpec(task *model.PeriodicTask) string {
+	return fmt.Sprintf(
+		"%s|%s|%s|%s|%s|%s", task.Name, task.Cron, task.Timezone,
+		task.StartAt.Format(time.RFC3339Nano), task.EndAt.Format(time.RFC3339Nano), task.ETA.Format(time.RFC3339Nano),
+	)
+}
+
+// NextRunAt 计算周期任务在 after 之后的下次触发时间，不再触发时返回零值
+func NextRunAt(task *model.PeriodicTask, after time.Time) (time.Time, error) {
+	schedule, err := ParseSchedule(task)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return schedule.Next(after), nil
+}
+
+// 仅在 [startAt, endAt] 时间窗口内触发的调度计划（为零值表示不限制）
+type windowSchedule struct {
+	schedule cron.Schedule
+	startAt  time.Time
+	endAt    time.Time
+}
+
+// Next 返回零值时，cron 不会再触发该任务
+func (s *windowSchedule) Next(t time.Time) time.Time {
+	// 从 startAt 前一秒开始计算，使 startAt 本身也可以被触发
+	if !s.startAt.IsZero() && t.Before(s.startAt) {
+		t = s.startAt.Add(-time.Second)
+	}
+	next := s.schedule.Next(t)
+	if !s.endAt.IsZero() && next.After(s.endAt) {
+		return time.Time{}
+	}
+	return next
+}
+
+// 一次性调度计划：仅在 at 触发一次
+type oneOffSchedule struct {
+	at time.Time
+}
+
+// Next ...
+func (s *oneOffSchedule) Next(t time.Time) time.Time {
+	if t.Before(s.at) {
+		return s.at
+	}
+	return time.Time{}
+}
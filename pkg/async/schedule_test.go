@@ -0,0 +1,93 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - Go 开发框架 (BlueKing - Go Framework) available.
+ * Copyright (C) 2017 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ *	https://opensource.org/licenses/MIT
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package async
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/TencentBlueKing/blueapps-go/pkg/model"
+)
+
+func TestNextRunAt(t *testing.T) {
+	after := time.Date(2026, 10, 18, 10, 7, 30, 0, time.Local)
+
+	nextRunAt, err := NextRunAt(&model.PeriodicTask{Cron: "*/5 * * * *"}, after)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 10, 18, 10, 10, 0, 0, time.Local), nextRunAt)
+
+	_, err = NextRunAt(&model.PeriodicTask{Cron: "invalid"}, after)
+	assert.Error(t, err)
+}
+
+func TestNextRunAtWithSeconds(t *testing.T) {
+	after := time.Date(2026, 10, 18, 10, 7, 30, 0, time.Local)
+
+	nextRunAt, err := NextRunAt(&model.PeriodicTask{Cron: "*/20 * * * * *"}, after)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 10, 18, 10, 7, 40, 0, time.Local), nextRunAt)
+}
+
+func TestNextRunAtWithTimezone(t *testing.T) {
+	after := time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+
+	// 每天上海时间 03:00 -> UTC 19:00
+	nextRunAt, err := NextRunAt(&model.PeriodicTask{Cron: "0 3 * * *", Timezone: "Asia/Shanghai"}, after)
+	assert.NoError(t, err)
+	assert.True(t, time.Date(2026, 10, 18, 19, 0, 0, 0, time.UTC).Equal(nextRunAt))
+
+	_, err = NextRunAt(&model.PeriodicTask{Cron: "0 3 * * *", Timezone: "Mars/Olympus"}, after)
+	assert.Error(t, err)
+}
+
+func TestNextRunAtWithWindow(t *testing.T) {
+	task := model.PeriodicTask{
+		Cron:    "0 * * * *",
+		StartAt: time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC),
+		EndAt:   time.Date(2026, 10, 18, 14, 0, 0, 0, time.UTC),
+	}
+
+	// 早于生效时间，从 startAt 开始触发（包含 startAt）
+	nextRunAt, err := NextRunAt(&task, time.Date(2026, 10, 18, 8, 30, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.True(t, task.StartAt.Equal(nextRunAt))
+
+	// 窗口内正常触发
+	nextRunAt, _ = NextRunAt(&task, time.Date(2026, 10, 18, 13, 30, 0, 0, time.UTC))
+	assert.True(t, task.EndAt.Equal(nextRunAt))
+
+	// 超出 endAt 后不再触发
+	nextRunAt, _ = NextRunAt(&task, task.EndAt)
+	assert.True(t, nextRunAt.IsZero())
+}
+
+func TestNextRunAtOneOff(t *testing.T) {
+	eta := time.Date(2026, 10, 19, 3, 0, 0, 0, time.Local)
+	task := model.PeriodicTask{ETA: eta}
+
+	nextRunAt, err := NextRunAt(&task, eta.Add(-time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, eta, nextRunAt)
+
+	// 触发后不再触发
+	nextRunAt, _ = NextRunAt(&task, eta)
+	assert.True(t, nextRunAt.IsZero())
+}
@@ -31,6 +31,7 @@ import (
 	"go.opentelemetry.io/otel"
 	"gorm.io/gorm"
 
+	"github.com/TencentBlueKing/blueapps-go/pkg/config"
 	"github.com/TencentBlueKing/blueapps-go/pkg/infras/database"
 	log "github.com/TencentBlueKing/blueapps-go/pkg/logging"
 	"github.com/TencentBlueKing/blueapps-go/pkg/model"
@@ -50,17 +51,27 @@ var initOnce sync.Once
 *    综上，我们在讨论后移除了对 machinery 的引入，仅仅作为文档中的示例供有需要的开发者参考
 *
 * Q：目前这套基于 goroutine 实现的机制会有什么问题
-* A：由于没有使用消息队列，也没有保护机制，因此如果进程重启/崩溃，会导致运行中的任务中断
+* A：默认的 local Broker 没有使用消息队列，也没有保护机制，因此如果进程重启/崩溃，会导致运行中的任务中断
+*    如需保证任务不丢失，可将 `service.async.broker` 配置为 redis / rabbitmq，并启动 worker 进程消费任务，
+*    此时任务会持久化到消息队列中，worker 崩溃后未确认的任务会被重新执行（at-least-once）
 *
 * Q：scheduler 是如何管理周期任务的？
 * A：- scheduler 首次启动时，会从 DB 中加载所有周期任务，并根据指定的 Cron 表达式执行
-#    - scheduler 会根据指定的时间间隔（reloadTasksCron）周期性从 DB 中加载新增的任务
+#    - 周期任务新增 / 启停 / 删除后，API 会通过 Redis pub/sub 通知 scheduler 立即重新注册该任务；
+#      未启用 Redis 时，scheduler 每隔数秒轮询 updated_at 感知变更
+#    - scheduler 会根据指定的时间间隔（reloadTasksCron）周期性从 DB 中全量重载任务，作为兜底
+*
+* Q：scheduler 可以多副本运行吗？
+* A：可以，多个副本会通过 Redis 租约（未启用 Redis 时使用 MySQL GET_LOCK）选主，只有 leader 会调度周期任务，
+*    其他副本空闲等待，leader 异常退出后，备用副本最迟在租约时长（schedulerLeaseTTL）后接管；
+*    此外，周期任务的每次触发都会按（周期任务 ID，计划触发时间）去重，避免切主期间重复下发
 *
 * Q：如果我想接入如 machinery 这样的异步框架，应该怎么做？比如怎么适配增强服务？
 # A：请查阅 Readme.md 中的 `异步/定时任务` 一节
 */
 
 // 默认每 5 分钟检查 & 重载周期任务
+// 注：周期任务的变更会通过 Redis pub/sub（或轮询 updated_at）立即生效，全量重载仅作为兜底（如通知丢失）
 // NOTE: SaaS 开发者可以根据需要自行调整，但不建议过大/过小
 const reloadTasksCron = "*/5 * * * *"
 
@@ -71,15 +82,99 @@ type TaskScheduler struct {
 	ctx          context.Context
 	cron         *cron.Cron
 	taskEntryMap *taskEntryMap
+
+	// 选主器 & 竞选 / 续期间隔
+	elector          elector
+	campaignInterval time.Duration
+	// 当前副本是否为 leader（正在调度周期任务）
+	leading bool
+	// 停止监听周期任务变更
+	stopWatch context.CancelFunc
+	// 保证全量重载与单个任务重载互斥
+	reloadLock sync.Mutex
+	// 声明式周期任务定义文件，为空表示不启用
+	periodicTasksFile string
+	// 处理 ETA 已过的一次性任务（异步执行，与注册互不阻塞）
+	fireOverdue func(task model.PeriodicTask, taskRepr string)
+}
+
+// Run 启用调度器：竞选成为 leader 后加载周期任务并开始调度，失去 leader 身份后停止调度，阻塞直到 ctx 被取消
+func (s *TaskScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.campaignInterval)
+	defer ticker.Stop()
+
+	for {
+		s.campaign(ctx)
+
+		select {
+		case <-ctx.Done():
+			s.stopLeading()
+			// 主动放弃 leader，以便备用副本尽快接管
+			s.elector.resign(context.WithoutCancel(ctx))
+			log.Info(s.ctx, "task scheduler stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// 竞选 / 续期 leader，并根据结果启停调度
+func (s *TaskScheduler) campaign(ctx context.Context) {
+	isLeader, err := s.elector.campaign(ctx)
+	if err != nil {
+		// 无法确认 leader 身份时视为失去 leader，避免多个副本同时调度
+		log.Warnf(s.ctx, "scheduler campaign error: %s", err)
+	}
+
+	switch {
+	case isLeader && !s.leading:
+		log.Info(s.ctx, "scheduler became leader, start scheduling periodic tasks")
+		s.syncDeclaredTasks()
+		if err = s.LoadTasks(); err != nil {
+			log.Errorf(s.ctx, "failed to load periodic tasks: %s", err)
+			s.elector.resign(ctx)
+			return
+		}
+		// 按各周期任务的策略处理停机 / 切主期间错过的触发（在开始调度前执行，与正常触发的时间不重叠）
+		s.catchUpMisfires(time.Now())
+		s.cron.Start()
+		s.leading = true
+
+		// 监听周期任务变更，使新增 / 启停 / 删除立即生效
+		var watchCtx context.Context
+		watchCtx, s.stopWatch = context.WithCancel(ctx)
+		go s.watchChanges(watchCtx)
+	case !isLeader && s.leading:
+		log.Warn(s.ctx, "scheduler lost leadership, stop scheduling periodic tasks")
+		s.stopLeading()
+	}
 }
 
-// Run 启用调度器
-func (s *TaskScheduler) Run() {
-	s.cron.Run()
+// 同步声明式定义的周期任务（仅 leader 执行，避免多个副本并发同步），同步失败不影响已有周期任务的调度
+func (s *TaskScheduler) syncDeclaredTasks() {
+	if s.periodicTasksFile == "" {
+		return
+	}
+	if _, err := SyncPeriodicTasksFromFile(s.ctx, s.periodicTasksFile, declarativeSyncOperator, false); err != nil {
+		log.Errorf(s.ctx, "failed to sync periodic tasks from %s: %s", s.periodicTasksFile, err)
+	}
+}
+
+// 停止调度（不等待执行中的 cron 任务，其仅负责下发异步任务，耗时很短）
+func (s *TaskScheduler) stopLeading() {
+	if !s.leading {
+		return
+	}
+	s.stopWatch()
+	s.cron.Stop()
+	s.leading = false
 }
 
 // LoadTasks 加载所有周期任务
 func (s *TaskScheduler) LoadTasks() error {
+	s.reloadLock.Lock()
+	defer s.reloadLock.Unlock()
+
 	// 从数据库加载周期性任务
 	periodicTasks := []model.PeriodicTask{}
 	if err := database.Client(s.ctx).Find(&periodicTasks).Error; err != nil {
@@ -90,69 +185,162 @@ func (s *TaskScheduler) LoadTasks() error {
 	// 根据是否启用，注册/注销周期任务
 	for _, task := range periodicTasks {
 		if !task.Enabled {
-			srv.unregister(task.ID)
+			s.unregister(task.ID)
 			continue
 		}
 		enabledTaskIDs.Add(task.ID)
-		if err := srv.register(task); err != nil {
+		if err := s.register(task); err != nil {
 			return errors.Wrap(err, "register periodic task")
 		}
 	}
 
 	// 对于已经注册但 DB 中已经删除的，需要注销
-	for taskID := range s.taskEntryMap.mapping {
-		if !enabledTaskIDs.Contains(taskID) {
-			srv.unregister(taskID)
-		}
-	}
-	log.Debugf(s.ctx, "%d periodic tasks loaded", len(s.taskEntryMap.mapping))
+	s.unregisterMissing(enabledTaskIDs)
+	log.Debugf(s.ctx, "%d periodic tasks loaded", len(s.taskEntryMap.ids()))
 	return nil
 }
 
-// 注册单个周期任务
+// 注册单个周期任务（已注册且调度配置未变更的跳过，配置已变更的重新注册）
 func (s *TaskScheduler) register(task model.PeriodicTask) error {
-	// 跳过已注册的任务
-	if _, ok := s.taskEntryMap.get(task.ID); ok {
-		return nil
+	spec := scheduleSpec(&task)
+	if e, ok := s.taskEntryMap.get(task.ID); ok {
+		if e.spec == spec {
+			return nil
+		}
+		s.unregister(task.ID)
 	}
 
-	taskRepr := fmt.Sprintf("periodic task %s (id: %d)", task.Name, task.ID)
-	log.Infof(s.ctx, "register %s with cron: %s args: %v", taskRepr, task.Cron, task.Args)
-
-	entryID, err := s.cron.AddFunc(task.Cron, func() {
-		ctx, span := tracer.Start(s.ctx, taskRepr)
-		defer span.End()
-
-		// 已注册任务不存在 -> 已被删除，但还没重载刷新，可以跳过，其他错误需要打印错误日志
-		if err := database.Client(ctx).First(&task, task.ID).Error; err != nil {
-			if errors.Is(err, gorm.ErrRecordNotFound) {
-				log.Infof(ctx, "%s not found in database, skip run...", taskRepr)
-			} else {
-				log.Errorf(ctx, "failed to reload %s from database: %s", taskRepr, err)
-			}
-			return
-		}
-		// 被禁用的已注册任务，在重载前需要跳过
-		if !task.Enabled {
-			log.Infof(ctx, "%s is disabled, skip run...", taskRepr)
-			return
-		}
-		// 解析参数 & 下发异步任务
-		var taskArgs []any
-		if err := json.Unmarshal(task.Args, &taskArgs); err != nil {
-			log.Errorf(ctx, "failed to unmarshal %s args: %s", taskRepr, err)
-			return
-		}
-		ApplyTask(ctx, task.Name, taskArgs)
-	})
+	schedule, err := ParseSchedule(&task)
 	if err != nil {
 		return err
 	}
 
-	s.taskEntryMap.set(task.ID, entry{id: entryID, name: task.Name})
+	taskRepr := fmt.Sprintf("periodic task %s (id: %d)", task.Name, task.ID)
+	if task.ETA.IsZero() {
+		log.Infof(s.ctx, "register %s with cron: %s timezone: %s args: %v", taskRepr, task.Cron, task.Timezone, task.Args)
+	} else {
+		log.Infof(s.ctx, "register %s with eta: %s args: %v", taskRepr, task.ETA, task.Args)
+	}
+
+	// ETA 已过的一次性任务（如 ETA 在 scheduler 停机 / 非 leader 期间到期）不会再被 cron 触发，无需注册
+	if !task.ETA.IsZero() && !task.ETA.After(time.Now()) {
+		go s.fireOverdue(task, taskRepr)
+		return nil
+	}
+
+	// cron 已启动时，任务可能在 Schedule 返回（entryID 赋值）前就被触发，需等待赋值完成后再读取
+	var entryID cron.EntryID
+	scheduled := make(chan struct{})
+	entryID = s.cron.Schedule(schedule, cron.FuncJob(func() {
+		<-scheduled
+		s.fire(task.ID, taskRepr, s.cron.Entry(entryID).Prev, false)
+	}))
+	close(scheduled)
+
+	s.taskEntryMap.set(task.ID, entry{id: entryID, name: task.Name, spec: spec})
 	return nil
 }
 
+// 触发单次周期任务：抢占触发记录 & 下发异步任务 & 记录触发结果
+func (s *TaskScheduler) fire(periodicTaskID int64, taskRepr string, scheduledAt time.Time, catchUp bool) {
+	// 补跑的触发在日志中需明确标识
+	if catchUp {
+		taskRepr = "[catch-up] " + taskRepr
+	}
+	ctx, span := tracer.Start(s.ctx, taskRepr)
+	defer span.End()
+
+	// 按（周期任务 ID，计划触发时间）去重，避免切主期间多个副本重复下发
+	run := &model.PeriodicTaskRun{PeriodicTaskID: periodicTaskID, ScheduledAt: scheduledAt, CatchUp: catchUp}
+	claimed, err := claimFiring(ctx, run)
+	if err != nil {
+		log.Errorf(ctx, "failed to claim %s firing at %s: %s", taskRepr, scheduledAt, err)
+		return
+	} else if !claimed {
+		log.Infof(ctx, "%s firing at %s already claimed by other scheduler, skip run...", taskRepr, scheduledAt)
+		return
+	}
+
+	// 已注册任务不存在 -> 已被删除，但还没重载刷新，可以跳过，其他错误需要打印错误日志
+	var task model.PeriodicTask
+	if err = database.Client(ctx).First(&task, periodicTaskID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Infof(ctx, "%s not found in database, skip run...", taskRepr)
+			finishRun(ctx, run, model.PeriodicTaskRunStatusSkipped, 0, nil)
+		} else {
+			log.Errorf(ctx, "failed to reload %s from database: %s", taskRepr, err)
+			finishRun(ctx, run, model.PeriodicTaskRunStatusFailed, 0, err)
+		}
+		return
+	}
+	// 被禁用的已注册任务，在重载前需要跳过
+	if !task.Enabled {
+		log.Infof(ctx, "%s is disabled, skip run...", taskRepr)
+		finishRun(ctx, run, model.PeriodicTaskRunStatusSkipped, 0, nil)
+		return
+	}
+	if !task.ETA.IsZero() {
+		defer s.completeOneOff(ctx, &task, taskRepr)
+	}
+	// 下发异步任务（参数会按任务声明的参数类型校验）
+	taskID, err := ApplyTask(ctx, task.Name, json.RawMessage(task.Args), WithCreator(task.Creator))
+	// 声明了执行期间唯一的任务，上次触发的任务仍未结束时跳过本次触发
+	if errors.Is(err, ErrTaskLocked) {
+		log.Infof(ctx, "%s previous run (task id: %d) is still pending or running, skip run...", taskRepr, taskID)
+		finishRun(ctx, run, model.PeriodicTaskRunStatusSkipped, 0, err)
+		return
+	}
+	if err != nil {
+		log.Errorf(ctx, "failed to apply %s: %s", taskRepr, err)
+		// 任务记录已创建但投递失败时，仍关联任务 ID 便于排查
+		finishRun(ctx, run, model.PeriodicTaskRunStatusFailed, taskID, err)
+		return
+	}
+	log.Infof(ctx, "%s applied, task id: %d", taskRepr, taskID)
+	finishRun(ctx, run, model.PeriodicTaskRunStatusApplied, taskID, nil)
+}
+
+// 处理 ETA 已过的一次性任务：按错过触发策略补跑一次（与补跑错过的触发一样按 ETA 去重，不会重复下发），
+// skip 策略则记录为跳过，均会标记为已完成，避免任务一直处于启用状态却不再触发
+func (s *TaskScheduler) fireOverdueOneOff(task model.PeriodicTask, taskRepr string) {
+	if task.MisfirePolicy != model.MisfirePolicySkip {
+		s.fire(task.ID, taskRepr, task.ETA, true)
+		return
+	}
+
+	ctx, span := tracer.Start(s.ctx, taskRepr)
+	defer span.End()
+
+	log.Infof(ctx, "%s missed eta %s, policy: %s, skip run...", taskRepr, task.ETA, task.MisfirePolicy)
+	run := &model.PeriodicTaskRun{PeriodicTaskID: task.ID, ScheduledAt: task.ETA}
+	claimed, err := claimFiring(ctx, run)
+	if err != nil {
+		// 未记录跳过结果时不标记为已完成，下次重载时重新处理
+		log.Errorf(ctx, "failed to claim %s firing at %s: %s", taskRepr, task.ETA, err)
+		return
+	}
+	if claimed {
+		finishRun(ctx, run, model.PeriodicTaskRunStatusSkipped, 0, errors.New("eta passed before it was scheduled"))
+	}
+	s.completeOneOff(ctx, &task, taskRepr)
+}
+
+// 一次性任务触发后（无论下发成功与否，结果见触发记录）标记为已完成并禁用，不再重复触发
+func (s *TaskScheduler) completeOneOff(ctx context.Context, task *model.PeriodicTask, taskRepr string) {
+	err := database.Client(ctx).Model(task).Updates(map[string]any{
+		"enabled":      false,
+		"completed_at": time.Now(),
+	}).Error
+	if err != nil {
+		log.Errorf(ctx, "failed to mark %s completed: %s", taskRepr, err)
+		return
+	}
+	// 注销已完成的任务（一次性调度计划本身也不会再触发，这里只是清理 cron 条目）
+	if err = s.reloadTask(task.ID); err != nil {
+		log.Errorf(ctx, "failed to reload %s: %s", taskRepr, err)
+	}
+}
+
 // 注销单个周期任务
 func (s *TaskScheduler) unregister(taskID int64) {
 	// 跳过未注册的任务
@@ -176,11 +364,15 @@ func Scheduler() *TaskScheduler {
 }
 
 // newScheduler 创建调度器
-func newScheduler(ctx context.Context) (*TaskScheduler, error) {
-	return &TaskScheduler{
+func newScheduler(ctx context.Context, cfg *config.AsyncConfig) (*TaskScheduler, error) {
+	leaseTTL := defaultSchedulerLeaseTTL
+	if cfg.SchedulerLeaseTTL > 0 {
+		leaseTTL = time.Duration(cfg.SchedulerLeaseTTL) * time.Second
+	}
+	s := &TaskScheduler{
 		ctx: ctx,
-		// 注：如有需要，可使用 cron.WithSeconds() 支持秒级精度控制
-		// cron 默认使用服务部署机器时区（time.Local），这里显式指定更清晰
+		// 注：周期任务由 ParseSchedule 解析（支持可选的秒级字段，指定了时区的任务以 CRON_TZ 按该时区计算）后注册，
+		// 不使用 cron 自带的解析器；未指定时区的任务按这里的时区（scheduler 所在机器时区）计算
 		// ref: https://github.com/robfig/cron
 		cron: cron.New(
 			cron.WithLocation(time.Local),
@@ -189,17 +381,23 @@ func newScheduler(ctx context.Context) (*TaskScheduler, error) {
 		taskEntryMap: &taskEntryMap{
 			mapping: make(map[int64]entry),
 		},
-	}, nil
+		elector:           newElector(leaseTTL),
+		periodicTasksFile: cfg.PeriodicTasksFile,
+		// 每个租约周期内至少续期 3 次，避免网络抖动导致租约过期
+		campaignInterval: leaseTTL / 3,
+	}
+	s.fireOverdue = s.fireOverdueOneOff
+	return s, nil
 }
 
 // InitTaskScheduler 初始化任务调度器
-func InitTaskScheduler(ctx context.Context) {
+func InitTaskScheduler(ctx context.Context, cfg *config.AsyncConfig) {
 	if srv != nil {
 		return
 	}
 	initOnce.Do(func() {
 		var err error
-		srv, err = newScheduler(ctx)
+		srv, err = newScheduler(ctx, cfg)
 		if err != nil {
 			log.Fatalf("failed to init task server: %s", err)
 		}
@@ -0,0 +1,203 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - Go 开发框架 (BlueKing - Go Framework) available.
+ * Copyright (C) 2017 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ *	https://opensource.org/licenses/MIT
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package async
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+
+	"github.com/TencentBlueKing/blueapps-go/pkg/infras/database"
+	"github.com/TencentBlueKing/blueapps-go/pkg/model"
+)
+
+// ErrInvalidTransition 任务当前状态不允许流转到目标状态
+var ErrInvalidTransition = errors.New("invalid task status transition")
+
+// 任务状态机：目标状态 -> 允许的来源状态
+// running -> running 用于 worker 崩溃后任务被重新投递执行的场景
+// running -> pending 用于任务执行失败后等待重试的场景
+var allowedTransitions = map[model.TaskStatus][]model.TaskStatus{
+	model.TaskStatusPending:   {model.TaskStatusRunning},
+	model.TaskStatusRunning:   {model.TaskStatusPending, model.TaskStatusRunning},
+	model.TaskStatusSucceeded: {model.TaskStatusRunning},
+	model.TaskStatusFailed:    {model.TaskStatusPending, model.TaskStatusRunning},
+	model.TaskStatusCancelled: {model.TaskStatusPending, model.TaskStatusRunning},
+	model.TaskStatusTimeout:   {model.TaskStatusRunning},
+}
+
+// 将任务状态流转到 to，若任务当前状态不允许流转则返回 ErrInvalidTransition
+// 通过 WHERE status IN (...) 保证并发场景下状态流转的原子性
+func transitTask(tx *gorm.DB, taskID int64, to model.TaskStatus, values map[string]any) error {
+	return transitTaskFrom(tx, taskID, allowedTransitions[to], to, values)
+}
+
+// 仅当任务当前状态为 from 之一时将任务状态流转到 to（from 需为允许的来源状态的子集），用于需要区分来源状态的场景
+func transitTaskFrom(
+	tx *gorm.DB, taskID int64, from []model.TaskStatus, to model.TaskStatus, values map[string]any,
+) error {
+	values["status"] = to
+	tx = tx.Model(&model.Task{}).
+		Where("id = ? AND status IN ?", taskID, from).
+		Updates(values)
+	if tx.Error != nil {
+		return errors.Wrapf(tx.Error, "update task %d status to %s", taskID, to)
+	}
+	if tx.RowsAffected == 0 {
+		return errors.Wrapf(ErrInvalidTransition, "task %d -> %s", taskID, to)
+	}
+	return nil
+}
+
+// 下发时创建任务记录
+func createTaskRecord(ctx context.Context, name string, args []byte, creator string) (*model.Task, error) {
+	task := model.Task{
+		Name:   name,
+		Args:   args,
+		Status: model.TaskStatusPending,
+		BaseModel: model.BaseModel{
+			Creator: creator,
+			Updater: creator,
+		},
+	}
+	if err := database.Client(ctx).Create(&task).Error; err != nil {
+		return nil, errors.Wrapf(err, "create task %s record", name)
+	}
+	return &task, nil
+}
+
+// 删除未下发的任务记录（如下发去重命中）
+func deleteTaskRecord(ctx context.Context, taskID int64) error {
+	err := database.Client(ctx).Delete(&model.Task{}, taskID).Error
+	return errors.Wrapf(err, "delete task %d record", taskID)
+}
+
+// 标记任务开始执行，并创建本次执行记录
+func markTaskRunning(ctx context.Context, taskID int64) (*model.TaskAttempt, error) {
+	attempt := model.TaskAttempt{TaskID: taskID, Status: model.TaskStatusRunning, StartedAt: time.Now()}
+	err := database.Client(ctx).Transaction(func(tx *gorm.DB) error {
+		// 每次执行（包括重试）重新计算进度
+		err := transitTask(tx, taskID, model.TaskStatusRunning, map[string]any{
+			"started_at":       attempt.StartedAt,
+			"attempts":         gorm.Expr("attempts + 1"),
+			"progress":         0,
+			"progress_message": "",
+			"progress_data":    nil,
+		})
+		if err != nil {
+			return err
+		}
+		if err = tx.Model(&model.Task{}).Where("id = ?", taskID).Pluck("attempts", &attempt.Attempt).Error; err != nil {
+			return errors.Wrapf(err, "get task %d attempts", taskID)
+		}
+		return errors.Wrapf(tx.Create(&attempt).Error, "create task %d attempt record", taskID)
+	})
+	return &attempt, err
+}
+
+// 标记任务执行成功，并记录执行结果
+func markTaskSucceeded(ctx context.Context, attempt *model.TaskAttempt, result any) error {
+	values := finishedValues(attempt.StartedAt)
+	values["progress"] = 100
+	if result != nil {
+		rawResult, err := json.Marshal(result)
+		if err != nil {
+			return errors.Wrapf(err, "marshal task %d result", attempt.TaskID)
+		}
+		values["result"] = rawResult
+	}
+	return database.Client(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := transitTask(tx, attempt.TaskID, model.TaskStatusSucceeded, values); err != nil {
+			return err
+		}
+		return finishAttempt(tx, attempt, model.TaskStatusSucceeded, nil)
+	})
+}
+
+// 标记任务执行失败，并记录错误信息（attempt 为空表示任务未被执行，如投递失败）
+func markTaskFailed(ctx context.Context, taskID int64, attempt *model.TaskAttempt, taskErr error) error {
+	var startedAt time.Time
+	if attempt != nil {
+		startedAt = attempt.StartedAt
+	}
+	values := finishedValues(startedAt)
+	values["error"] = taskErr.Error()
+	return database.Client(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := transitTask(tx, taskID, model.TaskStatusFailed, values); err != nil {
+			return err
+		}
+		if attempt == nil {
+			return nil
+		}
+		return finishAttempt(tx, attempt, model.TaskStatusFailed, taskErr)
+	})
+}
+
+// 标记任务执行失败但等待重试，任务回到 pending 状态并记录最近一次的错误信息
+func markTaskRetrying(ctx context.Context, attempt *model.TaskAttempt, taskErr error) error {
+	return database.Client(ctx).Transaction(func(tx *gorm.DB) error {
+		err := transitTask(tx, attempt.TaskID, model.TaskStatusPending, map[string]any{"error": taskErr.Error()})
+		if err != nil {
+			return err
+		}
+		return finishAttempt(tx, attempt, model.TaskStatusFailed, taskErr)
+	})
+}
+
+// 标记任务执行超时
+func markTaskTimeout(ctx context.Context, attempt *model.TaskAttempt, timeout time.Duration) error {
+	taskErr := errors.Wrapf(ErrTaskTimeout, "exceeded %s", timeout)
+	values := finishedValues(attempt.StartedAt)
+	values["error"] = taskErr.Error()
+	return database.Client(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := transitTask(tx, attempt.TaskID, model.TaskStatusTimeout, values); err != nil {
+			return err
+		}
+		return finishAttempt(tx, attempt, model.TaskStatusTimeout, taskErr)
+	})
+}
+
+// 标记本次执行被取消（任务状态已由 CancelTask 流转，仅需更新执行记录）
+func markAttemptCancelled(ctx context.Context, attempt *model.TaskAttempt) error {
+	return finishAttempt(database.Client(ctx), attempt, model.TaskStatusCancelled, ErrTaskCancelled)
+}
+
+// 更新执行记录的结束状态
+func finishAttempt(tx *gorm.DB, attempt *model.TaskAttempt, status model.TaskStatus, taskErr error) error {
+	values := finishedValues(attempt.StartedAt)
+	values["status"] = status
+	if taskErr != nil {
+		values["error"] = taskErr.Error()
+	}
+	err := tx.Model(&model.TaskAttempt{}).Where("id = ?", attempt.ID).Updates(values).Error
+	return errors.Wrapf(err, "update task %d attempt %d record", attempt.TaskID, attempt.Attempt)
+}
+
+// 任务结束时需要更新的字段
+func finishedValues(startedAt time.Time) map[string]any {
+	finishedAt := time.Now()
+	values := map[string]any{"finished_at": finishedAt}
+	if !startedAt.IsZero() {
+		values["duration"] = finishedAt.Sub(startedAt)
+	}
+	return values
+}
@@ -0,0 +1,92 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - Go 开发框架 (BlueKing - Go Framework) available.
+ * Copyright (C) 2017 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ *	https://opensource.org/licenses/MIT
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package async
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+
+	"github.com/TencentBlueKing/blueapps-go/pkg/model"
+)
+
+// 基于 sqlmock 的 DB，用于校验执行的 SQL
+func newMockDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock) {
+	sqlDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+
+	db, err := gorm.Open(
+		mysql.New(mysql.Config{Conn: sqlDB, SkipInitializeWithVersion: true}),
+		&gorm.Config{SkipDefaultTransaction: true},
+	)
+	assert.NoError(t, err)
+	return db, mock
+}
+
+func TestTransitTask(t *testing.T) {
+	db, mock := newMockDB(t)
+
+	// 仅允许从 running 流转到 succeeded
+	mock.ExpectExec("UPDATE `tasks` SET .* WHERE id = \\? AND status IN \\(\\?\\)").
+		WithArgs(sqlmock.AnyArg(), model.TaskStatusSucceeded, sqlmock.AnyArg(), 1, model.TaskStatusRunning).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, transitTask(db, 1, model.TaskStatusSucceeded, map[string]any{"result": "{}"}))
+
+	// 当前状态不允许流转（如已结束的任务被重复标记）
+	mock.ExpectExec("UPDATE `tasks` SET .* WHERE id = \\? AND status IN \\(\\?,\\?\\)").
+		WithArgs(sqlmock.AnyArg(), model.TaskStatusFailed, sqlmock.AnyArg(), 1,
+			model.TaskStatusPending, model.TaskStatusRunning).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.ErrorIs(t, transitTask(db, 1, model.TaskStatusFailed, map[string]any{"error": "failed"}), ErrInvalidTransition)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTransitTaskFrom(t *testing.T) {
+	db, mock := newMockDB(t)
+
+	// 仅取消等待执行的任务（用于区分任务取消前的状态）
+	mock.ExpectExec("UPDATE `tasks` SET .* WHERE id = \\? AND status IN \\(\\?\\)").
+		WithArgs(model.TaskStatusCancelled, sqlmock.AnyArg(), 1, model.TaskStatusPending).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.ErrorIs(t, transitTaskFrom(
+		db, 1, []model.TaskStatus{model.TaskStatusPending}, model.TaskStatusCancelled, map[string]any{},
+	), ErrInvalidTransition)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAllowedTransitions(t *testing.T) {
+	// 结束状态不能再流转到其他状态
+	finished := []model.TaskStatus{
+		model.TaskStatusSucceeded, model.TaskStatusFailed, model.TaskStatusCancelled, model.TaskStatusTimeout,
+	}
+	for to, from := range allowedTransitions {
+		for _, status := range finished {
+			assert.NotContains(t, from, status, "%s -> %s", status, to)
+		}
+	}
+	// 等待重试的任务可以被取消 / 标记失败
+	assert.Contains(t, allowedTransitions[model.TaskStatusCancelled], model.TaskStatusPending)
+	assert.Contains(t, allowedTransitions[model.TaskStatusFailed], model.TaskStatusPending)
+}
@@ -18,44 +18,432 @@
 
 // Package async 提供一个简单的异步 / 定时任务封装：
 // 1. 使用 cron 支持定时任务（cmd: scheduler）
-// 2. 简单封装 goroutine 以支持异步任务
+// 2. 通过 Broker 下发异步任务，支持进程内 goroutine 执行或基于 Redis / RabbitMQ 的持久化队列（cmd: worker）
 package async
 
 import (
 	"context"
-	"reflect"
+	"encoding/json"
+	"fmt"
+	"time"
 
+	"github.com/pkg/errors"
+	"github.com/samber/lo"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/TencentBlueKing/blueapps-go/pkg/async/progress"
 	"github.com/TencentBlueKing/blueapps-go/pkg/async/task"
+	"github.com/TencentBlueKing/blueapps-go/pkg/common"
+	"github.com/TencentBlueKing/blueapps-go/pkg/infras/database"
 	log "github.com/TencentBlueKing/blueapps-go/pkg/logging"
+	"github.com/TencentBlueKing/blueapps-go/pkg/model"
 )
 
-// RegisteredTasks 已注册的任务
-// 注意：任务函数最后一个返回值推荐为 error 类型
-var RegisteredTasks = map[string]any{
-	"CalcFib": task.CalcFib,
-	// NOTE: SaaS 开发者可根据需求添加自定义任务
+// 注册任务：任务参数（JSON）会被解析为任务函数声明的参数类型，执行结果会被序列化记录到任务中
+func init() {
+	Register(
+		"CalcFib", task.CalcFib,
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 3, BackoffBase: 5 * time.Second, BackoffCap: time.Minute, Jitter: true}),
+		WithDefaultTimeout(time.Minute),
+	)
+	Register(
+		"PurgeSoftDeleted", task.PurgeSoftDeleted,
+		WithDefaultTimeout(30*time.Minute), WithUniqueWhileRunning(time.Hour),
+	)
+	// NOTE: SaaS 开发者可根据需求注册自定义任务
 }
 
-// ApplyTask 下发异步任务
-func ApplyTask(ctx context.Context, name string, args []any) {
-	go func() {
-		taskFunc, ok := RegisteredTasks[name]
-		if !ok {
-			log.Errorf(ctx, "task func %s not found", name)
-			return
+var taskTracer = otel.Tracer("async-task")
+
+// TaskOption 下发任务选项
+type TaskOption func(*taskOptions)
+
+type taskOptions struct {
+	creator  string
+	timeout  time.Duration
+	queue    string
+	priority int
+	dedupKey string
+	dedupTTL time.Duration
+}
+
+// WithCreator 指定下发任务的用户
+func WithCreator(creator string) TaskOption {
+	return func(o *taskOptions) {
+		o.creator = creator
+	}
+}
+
+// WithTimeout 指定任务单次执行的超时时间，覆盖任务默认的超时时间
+func WithTimeout(timeout time.Duration) TaskOption {
+	return func(o *taskOptions) {
+		o.timeout = timeout
+	}
+}
+
+// WithQueue 指定任务下发的队列（需在配置中声明），覆盖任务默认的队列
+func WithQueue(queue string) TaskOption {
+	return func(o *taskOptions) {
+		o.queue = queue
+	}
+}
+
+// WithPriority 指定任务的优先级，同一队列中数值越大越优先执行，默认为 0
+func WithPriority(priority int) TaskOption {
+	return func(o *taskOptions) {
+		o.priority = priority
+	}
+}
+
+// WithDedupKey 指定下发任务的去重 key：有效期（ttl，为 0 则默认 1min）内以相同 key 下发同名任务时，
+// 不会重复下发，而是返回首次下发的任务 ID（如避免用户重复点击导致重复下发任务）
+func WithDedupKey(key string, ttl time.Duration) TaskOption {
+	return func(o *taskOptions) {
+		o.dedupKey = key
+		o.dedupTTL = ttl
+	}
+}
+
+// ApplyTask 下发异步任务，返回任务记录 ID
+// args 可以是任务声明的参数类型，也可以是 JSON（json.RawMessage），下发前会按任务声明的参数类型校验
+// 任务会先以 pending 状态写入 DB，再投递到配置的 Broker 中，webserver / scheduler 等进程均通过该方法下发任务
+// 指定了去重 key 且命中时，返回已下发的任务 ID；任务声明了执行期间唯一且已有相同参数的任务未结束时，
+// 返回该任务 ID 及 ErrTaskLocked
+func ApplyTask(ctx context.Context, name string, args any, opts ...TaskOption) (int64, error) {
+	def, err := getTaskDef(name)
+	if err != nil {
+		return 0, err
+	}
+
+	options := taskOptions{timeout: def.timeout, queue: def.queue}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	if queue := lo.Ternary(options.queue == "", DefaultQueue, options.queue); !isQueueConfigured(queue) {
+		return 0, errors.Wrapf(ErrQueueNotFound, "queue %s", queue)
+	}
+	rawArgs, err := json.Marshal(args)
+	if err != nil {
+		return 0, errors.Wrapf(err, "marshal task %s args", name)
+	}
+	if _, err = def.decode(rawArgs); err != nil {
+		return 0, errors.Wrapf(err, "invalid task %s args", name)
+	}
+	task, err := createTaskRecord(ctx, name, rawArgs, options.creator)
+	if err != nil {
+		return 0, err
+	}
+	if holder, err := acquireTaskLocks(ctx, task, def, &options); err != nil || holder != task.ID {
+		// 未获取到锁（或获取锁失败）的任务不会被下发，删除任务记录
+		if dErr := deleteTaskRecord(ctx, task.ID); dErr != nil {
+			log.Errorf(ctx, "failed to delete task %d record: %s", task.ID, dErr)
 		}
+		return holder, err
+	}
+	return task.ID, publishTask(ctx, task, rawArgs, &options)
+}
+
+// ErrTaskNotRetryable 任务不可重新执行（如未结束 / 已执行成功 / 工作流节点）
+var ErrTaskNotRetryable = errors.New("task not retryable")
+
+// RetryTask 以相同的任务名称 & 参数重新下发已结束但未成功（failed / timeout / cancelled）的任务，返回新任务的 ID
+// 注：原任务记录保持不变，工作流节点需重新下发整个工作流
+func RetryTask(ctx context.Context, taskID int64, operator string) (int64, error) {
+	var task model.Task
+	if err := database.Client(ctx).First(&task, taskID).Error; err != nil {
+		return 0, errors.Wrapf(err, "get task %d", taskID)
+	}
+	if task.Kind != model.TaskKindTask || task.ParentID != 0 {
+		return 0, errors.Wrapf(ErrTaskNotRetryable, "task %d is a workflow node", taskID)
+	}
+	if !task.Status.IsFinished() || task.Status == model.TaskStatusSucceeded {
+		return 0, errors.Wrapf(ErrTaskNotRetryable, "task %d status: %s", taskID, task.Status)
+	}
+	return ApplyTask(ctx, task.Name, json.RawMessage(task.Args), WithCreator(operator))
+}
+
+// RunTask 在当前进程中同步执行任务并返回执行结果，不创建任务记录，也不经过 Broker（不会重试），用于调试
+// timeout 为 0 时使用任务默认的超时时间
+func RunTask(ctx context.Context, name string, rawArgs json.RawMessage, timeout time.Duration) (any, error) {
+	def, err := getTaskDef(name)
+	if err != nil {
+		return nil, err
+	}
+	if timeout == 0 {
+		timeout = def.timeout
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeoutCause(ctx, timeout, ErrTaskTimeout)
+		defer cancel()
+	}
+	result, err := def.run(ctx, rawArgs)
+	if err != nil && ctx.Err() != nil {
+		return nil, context.Cause(ctx)
+	}
+	return result, err
+}
 
-		taskArgs := []reflect.Value{reflect.ValueOf(ctx)}
-		for _, arg := range args {
-			taskArgs = append(taskArgs, reflect.ValueOf(arg))
+// 获取下发任务的去重锁 & 执行期间唯一的任务锁，返回持有锁的任务 ID（即去重命中 / 已有相同参数的任务未结束时，为已有任务的 ID）
+func acquireTaskLocks(ctx context.Context, task *model.Task, def *taskDef, options *taskOptions) (int64, error) {
+	locker := getTaskLocker()
+	if options.dedupKey != "" {
+		ttl := lo.Ternary(options.dedupTTL > 0, options.dedupTTL, defaultDedupTTL)
+		holder, acquired, err := locker.acquire(ctx, dedupLockKey(task.Name, options.dedupKey), task.ID, ttl)
+		if err != nil {
+			return 0, err
+		}
+		if !acquired {
+			log.Infof(ctx, "task %s with dedup key %s already applied, task id: %d", task.Name, options.dedupKey, holder)
+			return holder, nil
 		}
-		values := reflect.ValueOf(taskFunc).Call(taskArgs)
+	}
+	if def.uniqueTTL > 0 {
+		holder, acquired, err := locker.acquire(ctx, uniqueLockKey(task.Name, task.Args), task.ID, def.uniqueTTL)
+		if err == nil && !acquired {
+			err = errors.Wrapf(ErrTaskLocked, "task %s (id: %d)", task.Name, holder)
+		}
+		if err != nil {
+			// 去重 key 不能指向未下发的任务，需要一并释放
+			releaseDedupLock(ctx, task, options)
+			return holder, err
+		}
+	}
+	return task.ID, nil
+}
 
-		// 若任务执行有返回值，且最后一个返回值类型是 error 且不为 nil，需打印错误日志
-		if length := len(values); length != 0 {
-			if err, ok := values[length-1].Interface().(error); ok && err != nil {
-				log.Errorf(ctx, "apply task %s with args %v error: %s", name, args, err)
+// 释放下发任务的去重锁（任务未能下发时），释放失败的锁会在过期后自动失效
+func releaseDedupLock(ctx context.Context, task *model.Task, options *taskOptions) {
+	if options.dedupKey == "" {
+		return
+	}
+	if err := getTaskLocker().release(ctx, dedupLockKey(task.Name, options.dedupKey), task.ID); err != nil {
+		log.Errorf(ctx, "failed to release task %d dedup lock: %s", task.ID, err)
+	}
+}
+
+// 任务结束（等待重试的除外）后，释放执行期间唯一的任务锁，释放失败的锁会在过期后自动失效
+func releaseUniqueLock(ctx context.Context, taskID int64, name string, rawArgs []byte) {
+	def, err := getTaskDef(name)
+	if err != nil || def.uniqueTTL == 0 {
+		return
+	}
+	var task model.Task
+	if err = database.Client(ctx).Select("status").First(&task, taskID).Error; err != nil {
+		log.Errorf(ctx, "failed to get task %d status: %s", taskID, err)
+		return
+	}
+	if !task.Status.IsFinished() {
+		return
+	}
+	if err = getTaskLocker().release(ctx, uniqueLockKey(name, rawArgs), taskID); err != nil {
+		log.Errorf(ctx, "failed to release task %d unique lock: %s", taskID, err)
+	}
+}
+
+// 投递任务消息，投递失败的任务不会被执行，需要标记为失败，避免一直处于 pending 状态
+func publishTask(ctx context.Context, task *model.Task, rawArgs json.RawMessage, options *taskOptions) error {
+	queue := lo.Ternary(options.queue == "", DefaultQueue, options.queue)
+	ctx, span := taskTracer.Start(ctx, "apply task "+task.Name, trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			attribute.Int64("task.id", task.ID),
+			attribute.String("task.name", task.Name),
+			attribute.String("task.queue", queue),
+		),
+	)
+	defer span.End()
+
+	msg := newMessage(ctx, task.ID, task.Name, rawArgs)
+	msg.Timeout = options.timeout
+	msg.Queue = options.queue
+	msg.Priority = options.priority
+	msg.ParentID = task.ParentID
+	if err := getBroker().Publish(ctx, msg); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		if mErr := markTaskFailed(ctx, task.ID, nil, err); mErr != nil {
+			log.Errorf(ctx, "failed to mark task %d failed: %s", task.ID, mErr)
+		} else {
+			recordDeadLetter(ctx, msg, model.TaskStatusFailed, err)
+		}
+		releaseUniqueLock(ctx, task.ID, task.Name, rawArgs)
+		// 未投递的任务不会执行，去重 key 不能继续指向该任务，否则去重时长内相同去重 key 的下发都会返回该任务
+		releaseDedupLock(ctx, task, options)
+		return errors.Wrapf(err, "publish task %s (id: %d)", task.Name, task.ID)
+	}
+	enqueuedTasks.WithLabelValues(task.Name, queue).Inc()
+	return nil
+}
+
+// 为本次执行创建 span：链接到下发任务的 span（而非作为其子 span，避免 trace 跨度过长）
+func startTaskSpan(ctx context.Context, msg *Message) (context.Context, trace.Span) {
+	opts := []trace.SpanStartOption{
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			attribute.Int64("task.id", msg.TaskID),
+			attribute.String("task.name", msg.Name),
+			attribute.String("task.queue", msg.queue()),
+			attribute.Int("task.attempt", msg.Attempt),
+		),
+	}
+	enqueueCtx := otel.GetTextMapPropagator().Extract(context.Background(), propagation.MapCarrier(msg.TraceContext))
+	if spanCtx := trace.SpanContextFromContext(enqueueCtx); spanCtx.IsValid() {
+		opts = append(opts, trace.WithLinks(trace.Link{SpanContext: spanCtx}))
+	}
+	return taskTracer.Start(ctx, "run task "+msg.Name, opts...)
+}
+
+// 处理任务消息：流转任务状态 & 执行任务函数 & 记录执行结果
+// 任务函数返回的错误会按重试策略重新调度或记录到任务中，不会导致消息被重新投递；
+// DB 不可用等错误则会返回，由 Broker 重新投递
+func handleMessage(ctx context.Context, msg *Message) error {
+	// 在 context 中恢复下发任务时的 RequestID，便于串联日志
+	if msg.RequestID != "" {
+		ctx = context.WithValue(ctx, common.RequestIDCtxKey, msg.RequestID)
+	}
+	taskRepr := fmt.Sprintf("task %s (id: %d, attempt: %d)", msg.Name, msg.TaskID, msg.Attempt)
+	ctx, span := startTaskSpan(ctx, msg)
+	defer span.End()
+
+	attempt, err := markTaskRunning(ctx, msg.TaskID)
+	if err != nil {
+		// 任务已结束（如已被取消 / 重复投递的消息），无需执行
+		if errors.Is(err, ErrInvalidTransition) {
+			log.Infof(ctx, "%s already finished, skip run...", taskRepr)
+			releaseUniqueLock(ctx, msg.TaskID, msg.Name, msg.Args)
+			// 工作流中的任务在执行前被取消，需要推进工作流
+			if msg.ParentID != 0 {
+				advanceWorkflow(ctx, msg.TaskID)
 			}
+			return nil
 		}
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+	observeTaskStarted(msg, attempt.StartedAt)
+
+	result, taskErr := run(ctx, msg)
+	var status model.TaskStatus
+	switch {
+	case errors.Is(taskErr, ErrTaskCancelled):
+		log.Infof(ctx, "%s cancelled", taskRepr)
+		status, err = model.TaskStatusCancelled, markAttemptCancelled(ctx, attempt)
+	case errors.Is(taskErr, ErrTaskTimeout):
+		log.Errorf(ctx, "%s timeout after %s", taskRepr, msg.Timeout)
+		status, err = model.TaskStatusTimeout, markTaskTimeout(ctx, attempt, msg.Timeout)
+	case taskErr != nil:
+		log.Errorf(ctx, "apply %s with args %s error: %s", taskRepr, msg.Args, taskErr)
+		status, err = retryOrFail(ctx, msg, attempt, taskErr)
+	default:
+		status, err = model.TaskStatusSucceeded, markTaskSucceeded(ctx, attempt, result)
+	}
+	observeTaskFinished(msg, status, time.Since(attempt.StartedAt))
+	span.SetAttributes(attribute.String("task.status", string(status)))
+	if taskErr != nil && status != model.TaskStatusCancelled {
+		span.RecordError(taskErr)
+		span.SetStatus(codes.Error, taskErr.Error())
+	}
+	if err != nil {
+		log.Errorf(ctx, "failed to record %s result: %s", taskRepr, err)
+	}
+	finishMessage(ctx, msg, status, taskErr, err == nil)
+	return nil
+}
+
+// 任务消息处理结束后的收尾：最终失败（含超时）且已记录结果的任务记录死信 & 释放唯一锁 & 推进工作流
+func finishMessage(ctx context.Context, msg *Message, status model.TaskStatus, taskErr error, recorded bool) {
+	if recorded && (status == model.TaskStatusFailed || status == model.TaskStatusTimeout) {
+		recordDeadLetter(ctx, msg, status, taskErr)
+	}
+	releaseUniqueLock(ctx, msg.TaskID, msg.Name, msg.Args)
+	// 工作流中的任务结束（等待重试的除外）后，推进工作流
+	if msg.ParentID != 0 {
+		advanceWorkflow(ctx, msg.TaskID)
+	}
+}
+
+// 任务消息无法执行（如延迟任务到期时队列已满），标记任务为失败，并与执行失败的任务一样收尾，
+// 避免任务一直处于 pending 状态、唯一锁不释放、工作流无法推进
+func failMessage(ctx context.Context, msg *Message, cause error) {
+	err := markTaskFailed(ctx, msg.TaskID, nil, cause)
+	if err != nil {
+		log.Errorf(ctx, "failed to mark task %d failed: %s", msg.TaskID, err)
+	}
+	finishMessage(ctx, msg, model.TaskStatusFailed, cause, err == nil)
+}
+
+// 在可取消 / 超时的 context 中执行任务函数，任务被取消 / 超时时返回 ErrTaskCancelled / ErrTaskTimeout
+// 注：若任务函数未响应 ctx.Done()，则会在后台继续运行至结束，但不再阻塞 worker，其结果也不会被记录
+func run(ctx context.Context, msg *Message) (any, error) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	if msg.Timeout > 0 {
+		var cancelTimeout context.CancelFunc
+		ctx, cancelTimeout = context.WithTimeoutCause(ctx, msg.Timeout, ErrTaskTimeout)
+		defer cancelTimeout()
+	}
+	defer registerRunningTask(msg.TaskID, cancel)()
+	defer watchCancel(ctx, msg.TaskID, cancel)()
+	// 任务函数可通过 progress.Report 上报执行进度
+	reporter := newTaskProgressReporter(ctx, msg.TaskID)
+	defer reporter.stop()
+	ctx = progress.WithReporter(ctx, reporter)
+
+	type output struct {
+		result any
+		err    error
+	}
+	done := make(chan output, 1)
+	go func() {
+		result, err := execute(ctx, msg.Name, msg.Args)
+		done <- output{result: result, err: err}
 	}()
+
+	select {
+	case out := <-done:
+		// 任务函数响应 ctx.Done() 返回的错误，以 context 被取消的原因为准
+		if out.err != nil && ctx.Err() != nil {
+			return nil, context.Cause(ctx)
+		}
+		return out.result, out.err
+	case <-ctx.Done():
+		return nil, context.Cause(ctx)
+	}
+}
+
+// 任务执行失败：若重试策略允许则延迟重新投递（返回 pending 状态），否则标记为失败
+func retryOrFail(
+	ctx context.Context, msg *Message, attempt *model.TaskAttempt, taskErr error,
+) (model.TaskStatus, error) {
+	policy := getRetryPolicy(msg.Name)
+	if !policy.shouldRetry(msg.Attempt, taskErr) {
+		return model.TaskStatusFailed, markTaskFailed(ctx, msg.TaskID, attempt, taskErr)
+	}
+
+	if err := markTaskRetrying(ctx, attempt, taskErr); err != nil {
+		return model.TaskStatusPending, err
+	}
+	backoff := policy.Backoff(msg.Attempt)
+	if err := getBroker().Publish(ctx, msg.retry(backoff)); err != nil {
+		// 重试消息投递失败，任务无法继续执行，直接标记为失败
+		err = markTaskFailed(ctx, msg.TaskID, nil, errors.Wrapf(err, "publish retry (last error: %s)", taskErr))
+		return model.TaskStatusFailed, err
+	}
+	log.Infof(ctx, "task %s (id: %d) will retry after %s", msg.Name, msg.TaskID, backoff)
+	return model.TaskStatusPending, nil
+}
+
+// 解析参数 & 调用任务函数
+func execute(ctx context.Context, name string, rawArgs json.RawMessage) (any, error) {
+	def, err := getTaskDef(name)
+	if err != nil {
+		return nil, NonRetryable(err)
+	}
+	return def.run(ctx, rawArgs)
 }
@@ -0,0 +1,72 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - Go 开发框架 (BlueKing - Go Framework) available.
+ * Copyright (C) 2017 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ *	https://opensource.org/licenses/MIT
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package task
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/TencentBlueKing/blueapps-go/pkg/infras/database"
+	"github.com/TencentBlueKing/blueapps-go/pkg/model"
+)
+
+// 单次清理的记录数量，避免大事务 & 长时间锁表
+const purgeBatchSize = 1000
+
+// PurgeSoftDeletedArgs 清理回收站任务参数
+type PurgeSoftDeletedArgs struct {
+	// 回收站中记录的保留天数，删除时间早于该期限的记录会被彻底删除
+	RetentionDays int `json:"retentionDays"`
+}
+
+// Validate ...
+func (a PurgeSoftDeletedArgs) Validate() error {
+	if a.RetentionDays <= 0 {
+		return errors.Errorf("retentionDays must be positive, got %d", a.RetentionDays)
+	}
+	return nil
+}
+
+// PurgeSoftDeleted 清理回收站：彻底删除 model.SoftDeleteModels 中超过保留期限的已删除记录，返回各表的清理数量
+// 通常作为周期任务执行（参见 configs/periodic_tasks.yaml）
+func PurgeSoftDeleted(ctx context.Context, args PurgeSoftDeletedArgs) (map[string]int64, error) {
+	deadline := time.Now().AddDate(0, 0, -args.RetentionDays)
+	purged := map[string]int64{}
+	for _, m := range model.SoftDeleteModels {
+		for {
+			if err := ctx.Err(); err != nil {
+				return purged, err
+			}
+			result := database.Client(ctx).Unscoped().
+				Where("deleted_at < ?", deadline).
+				Limit(purgeBatchSize).
+				Delete(m)
+			if result.Error != nil {
+				return purged, errors.Wrapf(result.Error, "purge %s", result.Statement.Table)
+			}
+			purged[result.Statement.Table] += result.RowsAffected
+			if result.RowsAffected < purgeBatchSize {
+				break
+			}
+		}
+	}
+	return purged, nil
+}
@@ -22,11 +22,10 @@ package task
 import (
 	"context"
 	"fmt"
-	"strconv"
-	"time"
 
-	"github.com/TencentBlueKing/blueapps-go/pkg/infras/database"
-	"github.com/TencentBlueKing/blueapps-go/pkg/model"
+	"github.com/pkg/errors"
+
+	"github.com/TencentBlueKing/blueapps-go/pkg/async/progress"
 )
 
 // Fibonacci 斐波那契数的递归实现，因为性能很差所以适合模拟需要长时间运行的后台任务
@@ -37,29 +36,51 @@ func fibonacci(n int) int {
 	return fibonacci(n-1) + fibonacci(n-2)
 }
 
-// CalcFib 计算斐波那契数任务
-func CalcFib(ctx context.Context, n float64) (int, error) {
-	// 由于 json Unmarshal 会把整数 & 浮点数都解析为 float64 类型，这由任务处理类型转换
-	nInt := int(n)
+// CalcFibArgs 计算斐波那契数任务参数
+type CalcFibArgs struct {
+	N int `json:"n"`
+}
 
-	task := model.Task{
-		Name:      "CalcFib",
-		Args:      []byte(fmt.Sprintf("{\"n\": %d}", nInt)),
-		StartedAt: time.Now(),
+// Validate ...
+func (a CalcFibArgs) Validate() error {
+	if a.N < 0 {
+		return errors.Errorf("n must be non-negative, got %d", a.N)
 	}
-	if err := database.Client(ctx).Create(&task).Error; err != nil {
-		return 0, err
-	}
-
-	// 执行计算任务
-	fibN := fibonacci(nInt)
+	return nil
+}
 
-	// 回填执行结果
-	task.Result = []byte(strconv.Itoa(fibN))
-	task.Duration = time.Since(task.StartedAt)
-	if err := database.Client(ctx).Save(&task).Error; err != nil {
-		return 0, err
+// 递归计算第 0 ~ n 个斐波那契数的累计调用次数，用于估算计算进度
+func fibonacciCosts(n int) []float64 {
+	calls := make([]float64, n+1)
+	costs := make([]float64, n+1)
+	for i := 0; i <= n; i++ {
+		calls[i] = 1
+		if i > 1 {
+			calls[i] += calls[i-1] + calls[i-2]
+		}
+		costs[i] = calls[i]
+		if i > 0 {
+			costs[i] += costs[i-1]
+		}
 	}
+	return costs
+}
 
-	return fibN, nil
+// CalcFib 计算斐波那契数任务
+// 任务记录（model.Task）由异步任务框架维护，任务函数只需返回执行结果；
+// 这里逐个计算第 0 ~ n 个斐波那契数，以演示长时间运行的任务如何上报进度 & 响应取消
+func CalcFib(ctx context.Context, args CalcFibArgs) (int, error) {
+	costs := fibonacciCosts(args.N)
+	result := 0
+	for i := 0; i <= args.N; i++ {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+		result = fibonacci(i)
+		progress.Report(
+			ctx, costs[i]/costs[args.N]*100, fmt.Sprintf("fib(%d) = %d", i, result),
+			map[string]int{"n": i, "fib": result},
+		)
+	}
+	return result, nil
 }
@@ -0,0 +1,66 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - Go 开发框架 (BlueKing - Go Framework) available.
+ * Copyright (C) 2017 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ *	https://opensource.org/licenses/MIT
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package async
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTaskSpanLinkedToEnqueueSpan(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	originalProvider, originalPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	defer func() {
+		otel.SetTracerProvider(originalProvider)
+		otel.SetTextMapPropagator(originalPropagator)
+	}()
+
+	// 下发任务的 span（如 HTTP 请求）
+	ctx, enqueueSpan := provider.Tracer("test").Start(context.Background(), "enqueue")
+	msg := newMessage(ctx, 1, "greet", nil)
+	enqueueSpan.End()
+	assert.NotEmpty(t, msg.TraceContext)
+
+	// 重试的消息仍链接到下发任务的 span
+	_, span := startTaskSpan(context.Background(), msg.retry(0))
+	span.End()
+
+	spans := exporter.GetSpans()
+	assert.Len(t, spans, 2)
+	runSpan := spans[1]
+	assert.Equal(t, "run task greet", runSpan.Name)
+	// 执行任务的 span 为新的 trace，链接到下发任务的 span
+	assert.NotEqual(t, enqueueSpan.SpanContext().TraceID(), runSpan.SpanContext.TraceID())
+	assert.Len(t, runSpan.Links, 1)
+	assert.Equal(t, enqueueSpan.SpanContext().SpanID(), runSpan.Links[0].SpanContext.SpanID())
+
+	// 无 trace 上下文的消息不添加链接
+	_, span = startTaskSpan(context.Background(), &Message{Name: "greet"})
+	span.End()
+	assert.Empty(t, exporter.GetSpans()[2].Links)
+}
@@ -27,6 +27,8 @@ import (
 type entry struct {
 	id   cron.EntryID
 	name string
+	// 注册时的调度配置，配置变更后需要重新注册
+	spec string
 }
 
 // 任务 ID 与 cron.EntryID + 任务名称的映射表
@@ -48,6 +50,16 @@ func (m *taskEntryMap) set(taskID int64, entry entry) {
 	m.mapping[taskID] = entry
 }
 
+func (m *taskEntryMap) ids() []int64 {
+	m.RLock()
+	defer m.RUnlock()
+	ids := make([]int64, 0, len(m.mapping))
+	for taskID := range m.mapping {
+		ids = append(ids, taskID)
+	}
+	return ids
+}
+
 func (m *taskEntryMap) delete(taskID int64) {
 	m.Lock()
 	defer m.Unlock()
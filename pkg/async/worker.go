@@ -0,0 +1,79 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - Go 开发框架 (BlueKing - Go Framework) available.
+ * Copyright (C) 2017 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ *	https://opensource.org/licenses/MIT
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package async
+
+import (
+	"context"
+	"sync"
+
+	"github.com/TencentBlueKing/blueapps-go/pkg/config"
+	log "github.com/TencentBlueKing/blueapps-go/pkg/logging"
+)
+
+// Worker 任务消费者，从 Broker 中拉取各队列的任务消息，交由 worker pool 按优先级 & 并发数限制执行
+type Worker struct {
+	broker Broker
+	queues []config.AsyncQueueConfig
+	pool   *workerPool
+}
+
+// NewWorker 创建 worker，worker pool 大小为配置的并发数
+func NewWorker(cfg *config.AsyncConfig) *Worker {
+	w := &Worker{broker: getBroker(), queues: queueConfigs(cfg)}
+	w.pool = newWorkerPool(cfg, w.handle)
+	return w
+}
+
+// Run 启动 worker，阻塞直到 ctx 被取消且执行中的任务全部完成
+func (w *Worker) Run(ctx context.Context) {
+	if w.broker.Name() == BrokerLocal {
+		log.Warn(ctx, "local broker executes tasks in publisher process, worker has nothing to consume")
+		<-ctx.Done()
+		return
+	}
+	log.Infof(
+		ctx, "worker started with broker: %s, concurrency: %d, queues: %v",
+		w.broker.Name(), w.pool.workers, w.queues,
+	)
+
+	w.pool.start()
+	// 每个队列的消费者数量与 worker pool 大小一致：消费者将消息提交到 pool 后等待执行完成再确认，
+	// 队列满（背压）时消费者阻塞，不再从 Broker 拉取该队列的消息
+	var wg sync.WaitGroup
+	for _, queue := range w.queues {
+		for range w.pool.workers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if err := w.broker.Consume(ctx, queue.Name, w.pool.execute); err != nil {
+					log.Errorf(ctx, "consume queue %s task messages error: %s", queue.Name, err)
+				}
+			}()
+		}
+	}
+	wg.Wait()
+	w.pool.stop()
+
+	log.Info(ctx, "worker stopped")
+}
+
+// 执行任务，ctx 被取消（worker 退出）时不中断执行中的任务，以便其正常完成并确认
+func (w *Worker) handle(ctx context.Context, msg *Message) error {
+	return handleMessage(context.WithoutCancel(ctx), msg)
+}
@@ -0,0 +1,98 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - Go 开发框架 (BlueKing - Go Framework) available.
+ * Copyright (C) 2017 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ *	https://opensource.org/licenses/MIT
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package async
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/TencentBlueKing/blueapps-go/pkg/config"
+)
+
+// 每个消费者投递一条消息后阻塞，直到 ctx 被取消
+type fakeBroker struct {
+	lock      sync.Mutex
+	consumers map[string]int
+	acked     atomic.Int32
+}
+
+func (b *fakeBroker) Name() string {
+	return "fake"
+}
+
+func (b *fakeBroker) Publish(context.Context, *Message) error {
+	return nil
+}
+
+func (b *fakeBroker) Consume(ctx context.Context, queue string, handler Handler) error {
+	b.lock.Lock()
+	b.consumers[queue]++
+	b.lock.Unlock()
+
+	if err := handler(ctx, &Message{ID: queue, Queue: queue}); err == nil {
+		b.acked.Add(1)
+	}
+	<-ctx.Done()
+	return nil
+}
+
+func TestWorkerRun(t *testing.T) {
+	cfg := &config.AsyncConfig{
+		Concurrency: 2,
+		Queues:      []config.AsyncQueueConfig{{Name: "low", Capacity: 10}, {Name: "high", Priority: 10, Capacity: 10}},
+	}
+	broker := &fakeBroker{consumers: map[string]int{}}
+	started := make(chan struct{}, 4)
+	var finished atomic.Int32
+	w := &Worker{broker: broker, queues: queueConfigs(cfg)}
+	w.pool = newWorkerPool(cfg, func(ctx context.Context, _ *Message) error {
+		started <- struct{}{}
+		// worker 退出时，执行中的任务不会被中断
+		time.Sleep(100 * time.Millisecond)
+		finished.Add(1)
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		w.Run(ctx)
+		close(done)
+	}()
+
+	// 等待任务开始执行后退出
+	<-started
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("worker not stopped")
+	}
+
+	// 每个队列的消费者数量与并发数一致
+	assert.Equal(t, map[string]int{"low": 2, "high": 2}, broker.consumers)
+	// 退出前等待执行中的任务完成并确认
+	assert.Positive(t, finished.Load())
+	assert.Equal(t, finished.Load(), broker.acked.Load())
+}
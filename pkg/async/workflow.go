@@ -0,0 +1,348 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - Go 开发框架 (BlueKing - Go Framework) available.
+ * Copyright (C) 2017 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ *	https://opensource.org/licenses/MIT
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package async
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+
+	"github.com/TencentBlueKing/blueapps-go/pkg/infras/database"
+	log "github.com/TencentBlueKing/blueapps-go/pkg/logging"
+	"github.com/TencentBlueKing/blueapps-go/pkg/model"
+)
+
+// ErrEmptyWorkflow 工作流节点没有子节点
+var ErrEmptyWorkflow = errors.New("workflow has no steps")
+
+// Signature 工作流节点：单个任务（NewSignature），或由子节点组成的 chain / group / chord，可任意嵌套
+//
+// 示例：导出 -> 压缩 -> 上传到制品库 -> 发送邮件
+//
+//	async.ApplyWorkflow(ctx, async.Chain(
+//		async.NewSignature("Export", ExportArgs{...}),
+//		async.NewSignature("Compress", nil),
+//		async.NewSignature("Upload", nil),
+//		async.NewSignature("SendMail", nil),
+//	))
+type Signature struct {
+	kind     model.TaskKind
+	name     string
+	args     any
+	children []*Signature
+}
+
+// NewSignature 单个任务节点，args 为 nil 时以上游节点的结果作为参数
+// （chain 中上一个节点的结果，chord 回调为 group 的结果列表）
+func NewSignature(name string, args any) *Signature {
+	return &Signature{kind: model.TaskKindTask, name: name, args: args}
+}
+
+// Chain 顺序执行各节点，上一个节点的结果作为下一个节点的参数，结果为最后一个节点的结果；任一节点失败则终止
+func Chain(steps ...*Signature) *Signature {
+	return &Signature{kind: model.TaskKindChain, name: string(model.TaskKindChain), children: steps}
+}
+
+// Group 并行执行各节点，全部成功后结果为各节点结果组成的列表（按节点顺序）；任一节点失败则 group 失败
+func Group(members ...*Signature) *Signature {
+	return &Signature{kind: model.TaskKindGroup, name: string(model.TaskKindGroup), children: members}
+}
+
+// Chord 并行执行 header 中的各节点，全部成功后以结果列表为参数执行 callback
+func Chord(header, callback *Signature) *Signature {
+	if header.kind != model.TaskKindGroup {
+		header = Group(header)
+	}
+	return &Signature{
+		kind:     model.TaskKindChord,
+		name:     string(model.TaskKindChord),
+		children: []*Signature{header, callback},
+	}
+}
+
+// 校验工作流：任务需已注册，指定的参数需符合任务声明的参数类型
+func (s *Signature) validate() error {
+	if s.kind != model.TaskKindTask {
+		if len(s.children) == 0 {
+			return errors.Wrap(ErrEmptyWorkflow, s.name)
+		}
+		for _, child := range s.children {
+			if err := child.validate(); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	def, err := getTaskDef(s.name)
+	if err != nil {
+		return err
+	}
+	if queue := def.queue; queue != "" && !isQueueConfigured(queue) {
+		return errors.Wrapf(ErrQueueNotFound, "queue %s", queue)
+	}
+	if s.args == nil {
+		return nil
+	}
+	rawArgs, err := json.Marshal(s.args)
+	if err != nil {
+		return errors.Wrapf(err, "marshal task %s args", s.name)
+	}
+	if _, err = def.decode(rawArgs); err != nil {
+		return errors.Wrapf(err, "invalid task %s args", s.name)
+	}
+	return nil
+}
+
+// ApplyWorkflow 下发工作流，返回根节点的任务记录 ID
+// 工作流的各节点会在下发时全部写入 DB，组成任务树（可通过 GET /api/tasks/{id} 查看），并在子节点结束时逐步推进
+// 注：opts 中仅 WithCreator 生效，各任务的队列 / 超时时间等使用任务注册时的默认值
+func ApplyWorkflow(ctx context.Context, sig *Signature, opts ...TaskOption) (int64, error) {
+	var options taskOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if err := sig.validate(); err != nil {
+		return 0, err
+	}
+
+	var root *model.Task
+	err := database.Client(ctx).Transaction(func(tx *gorm.DB) (err error) {
+		root, err = createWorkflowNode(tx, sig, nil, 0, options.creator)
+		return err
+	})
+	if err != nil {
+		return 0, errors.Wrap(err, "create workflow task records")
+	}
+
+	startNode(ctx, root, nil)
+	return root.ID, nil
+}
+
+// 递归创建工作流节点的任务记录
+func createWorkflowNode(
+	tx *gorm.DB, sig *Signature, parent *model.Task, position int, creator string,
+) (*model.Task, error) {
+	task := model.Task{
+		Kind:     sig.kind,
+		Name:     sig.name,
+		Status:   model.TaskStatusPending,
+		Position: position,
+		BaseModel: model.BaseModel{
+			Creator: creator,
+			Updater: creator,
+		},
+	}
+	if sig.args != nil {
+		task.Args, _ = json.Marshal(sig.args)
+	}
+	if parent != nil {
+		task.RootID, task.ParentID = parent.RootID, parent.ID
+	}
+	if err := tx.Create(&task).Error; err != nil {
+		return nil, err
+	}
+	if parent == nil {
+		task.RootID = task.ID
+		if err := tx.Model(&task).Update("root_id", task.ID).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	for i, child := range sig.children {
+		if _, err := createWorkflowNode(tx, child, &task, i, creator); err != nil {
+			return nil, err
+		}
+	}
+	return &task, nil
+}
+
+// 开始执行工作流节点，input 为上游节点的结果
+func startNode(ctx context.Context, node *model.Task, input json.RawMessage) {
+	// 节点在开始前已结束（如已被取消），直接推进工作流
+	if node.Status.IsFinished() {
+		advanceWorkflow(ctx, node.ID)
+		return
+	}
+
+	if node.Kind == model.TaskKindTask {
+		startTaskNode(ctx, node, input)
+		return
+	}
+
+	now := time.Now()
+	if err := transitTask(database.Client(ctx), node.ID, model.TaskStatusRunning, map[string]any{
+		"started_at": now,
+	}); err != nil {
+		// 并发推进 / 已被取消的节点无需处理
+		if !errors.Is(err, ErrInvalidTransition) {
+			log.Errorf(ctx, "failed to start workflow %s (id: %d): %s", node.Name, node.ID, err)
+		}
+		return
+	}
+	node.StartedAt = now
+
+	children, err := loadChildren(ctx, node.ID)
+	if err != nil {
+		log.Errorf(ctx, "failed to load workflow %s (id: %d) steps: %s", node.Name, node.ID, err)
+		return
+	}
+	if node.Kind == model.TaskKindGroup {
+		for i := range children {
+			startNode(ctx, &children[i], input)
+		}
+		return
+	}
+	// chain / chord 从第一个节点开始执行
+	startNode(ctx, &children[0], input)
+}
+
+// 下发工作流中的单个任务，未指定参数时以上游节点的结果作为参数
+func startTaskNode(ctx context.Context, node *model.Task, input json.RawMessage) {
+	rawArgs := json.RawMessage(node.Args)
+	if len(rawArgs) == 0 && len(input) != 0 {
+		rawArgs = input
+		if err := database.Client(ctx).Model(node).Update("args", []byte(input)).Error; err != nil {
+			log.Errorf(ctx, "failed to record task %d args: %s", node.ID, err)
+		}
+	}
+
+	options := taskOptions{}
+	if def, err := getTaskDef(node.Name); err == nil {
+		options.timeout, options.queue = def.timeout, def.queue
+	}
+	if err := publishTask(ctx, node, rawArgs, &options); err != nil {
+		log.Errorf(ctx, "failed to start workflow step: %s", err)
+		advanceWorkflow(ctx, node.ID)
+	}
+}
+
+// 子节点结束后推进工作流：执行下一个节点 / 结束父节点，并继续向上推进
+// 注：在消息重复投递等场景下可能被重复调用，任务函数需要保证幂等（同 at-least-once 语义）
+func advanceWorkflow(ctx context.Context, childID int64) {
+	var child model.Task
+	if err := database.Client(ctx).First(&child, childID).Error; err != nil {
+		log.Errorf(ctx, "failed to load workflow step %d: %s", childID, err)
+		return
+	}
+	if !child.Status.IsFinished() || child.ParentID == 0 {
+		return
+	}
+
+	var parent model.Task
+	if err := database.Client(ctx).First(&parent, child.ParentID).Error; err != nil {
+		log.Errorf(ctx, "failed to load workflow %d: %s", child.ParentID, err)
+		return
+	}
+	// 父节点已结束（如 group 中其他节点已失败 / 已被取消），无需推进
+	if parent.Status.IsFinished() {
+		return
+	}
+	children, err := loadChildren(ctx, parent.ID)
+	if err != nil {
+		log.Errorf(ctx, "failed to load workflow %s (id: %d) steps: %s", parent.Name, parent.ID, err)
+		return
+	}
+
+	// 任一子节点未成功，则取消尚未开始的子节点，并以相同的结果结束父节点
+	if child.Status != model.TaskStatusSucceeded {
+		cancelPendingNodes(ctx, children)
+		status := model.TaskStatusFailed
+		if child.Status == model.TaskStatusCancelled {
+			status = model.TaskStatusCancelled
+		}
+		stepErr := fmt.Sprintf("step %s (id: %d) %s: %s", child.Name, child.ID, child.Status, child.Error)
+		finishWorkflowNode(ctx, &parent, status, nil, stepErr)
+		return
+	}
+
+	switch parent.Kind {
+	case model.TaskKindGroup:
+		// 全部子节点成功后，结果为各子节点结果组成的列表
+		results := make([]json.RawMessage, 0, len(children))
+		for _, c := range children {
+			if c.Status != model.TaskStatusSucceeded {
+				return
+			}
+			results = append(results, resultOrNull(c.Result))
+		}
+		result, _ := json.Marshal(results)
+		finishWorkflowNode(ctx, &parent, model.TaskStatusSucceeded, result, "")
+	default:
+		// chain / chord：执行下一个节点，最后一个节点的结果即为父节点的结果
+		if next := child.Position + 1; next < len(children) {
+			startNode(ctx, &children[next], resultOrNull(child.Result))
+			return
+		}
+		finishWorkflowNode(ctx, &parent, model.TaskStatusSucceeded, child.Result, "")
+	}
+}
+
+// 结束工作流节点，并继续向上推进
+func finishWorkflowNode(
+	ctx context.Context, node *model.Task, status model.TaskStatus, result []byte, stepErr string,
+) {
+	values := finishedValues(node.StartedAt)
+	if result != nil {
+		values["result"] = result
+	}
+	if stepErr != "" {
+		values["error"] = stepErr
+	}
+	if err := transitTask(database.Client(ctx), node.ID, status, values); err != nil {
+		// 并发推进时，节点可能已被其他子节点结束
+		if !errors.Is(err, ErrInvalidTransition) {
+			log.Errorf(ctx, "failed to finish workflow %s (id: %d): %s", node.Name, node.ID, err)
+		}
+		return
+	}
+	log.Infof(ctx, "workflow %s (id: %d) %s", node.Name, node.ID, status)
+	advanceWorkflow(ctx, node.ID)
+}
+
+// 取消尚未开始的节点（含其子节点）
+func cancelPendingNodes(ctx context.Context, nodes []model.Task) {
+	for _, node := range nodes {
+		if node.Status != model.TaskStatusPending {
+			continue
+		}
+		if _, err := cancelTask(ctx, node.ID, ""); err != nil && !errors.Is(err, ErrInvalidTransition) {
+			log.Errorf(ctx, "failed to cancel workflow step %s (id: %d): %s", node.Name, node.ID, err)
+		}
+	}
+}
+
+// 按位置顺序加载子节点
+func loadChildren(ctx context.Context, parentID int64) ([]model.Task, error) {
+	var children []model.Task
+	err := database.Client(ctx).Where("parent_id = ?", parentID).Order("position").Find(&children).Error
+	return children, err
+}
+
+// 任务结果，未记录结果时为 JSON null
+func resultOrNull(result []byte) json.RawMessage {
+	if len(result) == 0 {
+		return json.RawMessage("null")
+	}
+	return result
+}
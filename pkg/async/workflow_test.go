@@ -0,0 +1,73 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - Go 开发框架 (BlueKing - Go Framework) available.
+ * Copyright (C) 2017 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ *	https://opensource.org/licenses/MIT
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package async
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/TencentBlueKing/blueapps-go/pkg/model"
+)
+
+func TestChord(t *testing.T) {
+	callback := NewSignature("sum", nil)
+
+	// header 不是 group 时会被包装为 group
+	sig := Chord(NewSignature("double", 1), callback)
+	assert.Equal(t, model.TaskKindChord, sig.kind)
+	assert.Len(t, sig.children, 2)
+	assert.Equal(t, model.TaskKindGroup, sig.children[0].kind)
+	assert.Len(t, sig.children[0].children, 1)
+	assert.Equal(t, callback, sig.children[1])
+
+	header := Group(NewSignature("double", 1), NewSignature("double", 2))
+	sig = Chord(header, callback)
+	assert.Equal(t, header, sig.children[0])
+}
+
+func TestSignatureValidate(t *testing.T) {
+	Register("greet", greet)
+	defer unregister("greet")
+
+	// 嵌套的工作流，未指定参数的任务以上游节点的结果作为参数
+	sig := Chain(
+		NewSignature("greet", greetArgs{Name: "foo", Times: 1}),
+		Group(NewSignature("greet", nil), NewSignature("greet", map[string]any{"name": "bar"})),
+		Chord(Group(NewSignature("greet", nil)), NewSignature("greet", nil)),
+	)
+	assert.NoError(t, sig.validate())
+
+	// 空的工作流
+	assert.ErrorIs(t, Chain(NewSignature("greet", nil), Group()).validate(), ErrEmptyWorkflow)
+	// 未注册的任务
+	assert.ErrorIs(t, Group(NewSignature("unknown", nil)).validate(), ErrTaskNotRegistered)
+	// 参数不合法
+	assert.Error(t, Chain(NewSignature("greet", greetArgs{})).validate())
+}
+
+func TestResultOrNull(t *testing.T) {
+	assert.Equal(t, "null", string(resultOrNull(nil)))
+	assert.Equal(t, "[1,2]", string(resultOrNull([]byte("[1,2]"))))
+
+	// 确保 context 可用于 ApplyWorkflow 的参数校验（未创建任何任务记录）
+	_, err := ApplyWorkflow(context.Background(), Chain())
+	assert.ErrorIs(t, err, ErrEmptyWorkflow)
+}
@@ -312,6 +312,21 @@ func loadServiceConfigFromEnv() (ServiceConfig, error) {
 	if val := envx.Get("AUTH_TYPES", ""); val != "" {
 		authTypes = strings.Split(val, ",")
 	}
+	// 异步任务队列在环境变量中格式如 "default:0:1000,critical:10:100"（名称:优先级:容量）
+	asyncQueues := []AsyncQueueConfig{}
+	if val := envx.Get("ASYNC_TASK_QUEUES", ""); val != "" {
+		for _, queue := range strings.Split(val, ",") {
+			parts := strings.Split(queue, ":")
+			queueCfg := AsyncQueueConfig{Name: parts[0]}
+			if len(parts) > 1 {
+				queueCfg.Priority = cast.ToInt(parts[1])
+			}
+			if len(parts) > 2 {
+				queueCfg.Capacity = cast.ToInt(parts[2])
+			}
+			asyncQueues = append(asyncQueues, queueCfg)
+		}
+	}
 	return ServiceConfig{
 		Server: ServerConfig{
 			Port:         cast.ToInt(envx.Get("PORT", "5000")),
@@ -331,6 +346,16 @@ func loadServiceConfigFromEnv() (ServiceConfig, error) {
 				lo.Ternary(isLocalDev, BaseDir+"/logs/", "/app/v3logs/"),
 			),
 		},
+		Async: AsyncConfig{
+			Broker:            envx.Get("ASYNC_TASK_BROKER", "local"),
+			VisibilityTimeout: cast.ToInt(envx.Get("ASYNC_TASK_VISIBILITY_TIMEOUT", "60")),
+			Prefetch:          cast.ToInt(envx.Get("ASYNC_TASK_PREFETCH", "1")),
+			Concurrency:       cast.ToInt(envx.Get("ASYNC_TASK_CONCURRENCY", "10")),
+			Queues:            asyncQueues,
+			SchedulerLeaseTTL: cast.ToInt(envx.Get("ASYNC_SCHEDULER_LEASE_TTL", "15")),
+			PeriodicTasksFile: envx.Get("ASYNC_PERIODIC_TASKS_FILE", ""),
+			MinCronInterval:   cast.ToInt(envx.Get("ASYNC_MIN_CRON_INTERVAL", "60")),
+		},
 		AllowedOrigins: allowedOrigins,
 		AllowedUsers:   allowedUsers,
 		// DB 加密密钥，若未使用加密功能可不配置
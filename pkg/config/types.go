@@ -205,12 +205,48 @@ type ServerConfig struct {
 	GinRunMode string
 }
 
+// AsyncConfig 异步任务配置
+type AsyncConfig struct {
+	// 任务消息队列类型，可选值为：local（进程内 goroutine）、redis、rabbitmq
+	// 注：local 模式下任务随进程重启 / 崩溃丢失，redis / rabbitmq 模式下需启动 worker 进程消费任务
+	Broker string
+	// 任务可见性超时（单位：s），worker 超过该时间未确认（且未续期）的任务会被重新投递
+	// 注：仅 redis 生效，rabbitmq 会在连接断开时重新投递未确认的任务
+	VisibilityTimeout int
+	// 单个消费者预取（未确认）的任务数量，仅 rabbitmq 生效
+	Prefetch int
+	// 单个进程（worker，或 local 模式下的 webserver）执行任务的并发数，即 worker pool 大小
+	Concurrency int
+	// 任务队列，任务可通过 async.WithQueue 指定下发的队列，未配置时仅有一个 default 队列
+	Queues []AsyncQueueConfig
+	// scheduler 选主租约时长（单位：s），leader 异常退出后，备用副本最迟在该时间后接管
+	SchedulerLeaseTTL int
+	// 声明式周期任务定义文件（如 configs/periodic_tasks.yaml），为空表示不启用
+	// scheduler 成为 leader 时会将其同步到 DB，也可通过 `sync-periodic-tasks` 命令手动同步
+	PeriodicTasksFile string
+	// 周期任务 cron 表达式的最小触发间隔（单位：s），cron 预览会提示触发过于频繁的表达式
+	MinCronInterval int
+}
+
+// AsyncQueueConfig 异步任务队列配置
+type AsyncQueueConfig struct {
+	// 队列名称
+	Name string
+	// 优先级，数值越大越优先执行（严格优先级：高优先级队列有任务时，低优先级队列的任务需等待）
+	Priority int
+	// 容量，即单个进程内等待执行的任务数量上限
+	// 队列满时，local 模式下拒绝下发任务，redis / rabbitmq 模式下 worker 暂停拉取该队列的任务
+	Capacity int
+}
+
 // ServiceConfig 服务配置
 type ServiceConfig struct {
 	// Web Server 配置
 	Server ServerConfig
 	// 日志配置
 	Log LogConfig
+	// 异步任务配置
+	Async AsyncConfig
 
 	// CORS 允许来源列表
 	AllowedOrigins []string
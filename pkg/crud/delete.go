@@ -0,0 +1,181 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - Go 开发框架 (BlueKing - Go Framework) available.
+ * Copyright (C) 2017 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ *	https://opensource.org/licenses/MIT
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package crud
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+
+	"github.com/TencentBlueKing/blueapps-go/pkg/i18n"
+)
+
+// DeletePolicy 删除资源时对子资源（通过外键引用该资源的记录）的处理策略
+type DeletePolicy string
+
+const (
+	// DeleteRestrict 存在子资源时拒绝删除（409）
+	DeleteRestrict DeletePolicy = "restrict"
+	// DeleteCascade 一并删除子资源（子资源支持软删除时同样为软删除，恢复资源时一并恢复）
+	DeleteCascade DeletePolicy = "cascade"
+	// DeleteSetNull 将子资源（包括回收站中的）的外键置空，外键列需允许为 NULL
+	DeleteSetNull DeletePolicy = "set-null"
+)
+
+// Dependent 子资源声明
+// 注：DB 未启用外键约束（迁移时禁用），子资源的一致性由删除策略维护；级联删除仅处理直接子资源，不会递归
+type Dependent struct {
+	// 子资源模型，如 &model.Entry{}
+	Model any
+	// 子资源中引用该资源主键的外键列，如 category_id
+	ForeignKey string
+	Policy     DeletePolicy
+}
+
+// 校验声明：外键列需存在，set-null 策略的外键列需允许为 NULL（否则删除时才会失败）
+func (d *Dependent) check() error {
+	sch, err := schema.Parse(d.Model, &sync.Map{}, schema.NamingStrategy{})
+	if err != nil {
+		return errors.Wrapf(err, "parse dependent model %T", d.Model)
+	}
+	field := sch.LookUpField(d.ForeignKey)
+	if field == nil {
+		return errors.Errorf("dependent model %s has no column %s", sch.Name, d.ForeignKey)
+	}
+	if d.Policy == DeleteSetNull && !nullable(field) {
+		return errors.Errorf("column %s.%s is not nullable, cannot use %s policy", sch.Table, d.ForeignKey, d.Policy)
+	}
+	return nil
+}
+
+// 按删除策略处理子资源（在删除资源的事务中执行）
+func (d *Dependent) apply(ctx context.Context, tx *gorm.DB, id any, marker deletion) error {
+	// Dependent 为多个请求共享的声明，gorm 会回写模型字段（如软删除的 deleted_at），需每次使用新的模型实例
+	value := newModel(d.Model)
+	query := d.ForeignKey + " = ?"
+	switch d.Policy {
+	case DeleteCascade:
+		return deleteRecords(tx, value, marker, query, id)
+	case DeleteSetNull:
+		return tx.Unscoped().Model(value).Where(query, id).UpdateColumn(d.ForeignKey, gorm.Expr("NULL")).Error
+	default:
+		var count int64
+		if err := tx.Model(value).Where(query, id).Count(&count).Error; err != nil {
+			return err
+		}
+		if count != 0 {
+			return NewError(http.StatusConflict, fmt.Sprintf(
+				i18n.T(ctx, "record %v cannot be deleted: %d dependent records exist"), id, count,
+			))
+		}
+		return nil
+	}
+}
+
+// 恢复随资源一并（级联）删除的子资源，即删除标记与资源相同的子资源，此前单独删除的子资源仍留在回收站中
+func (d *Dependent) restore(tx *gorm.DB, id any, marker deletion, user string) error {
+	value := newModel(d.Model)
+	if _, ok := value.(softDeleter); !ok || d.Policy != DeleteCascade {
+		return nil
+	}
+	return tx.Unscoped().Model(value).
+		Where(d.ForeignKey+" = ? AND deleted_at = ? AND deleted_by = ?", id, marker.at, marker.by).
+		Updates(restoredValues(value, user)).Error
+}
+
+// Parent 父资源声明（资源通过外键引用的记录），父资源在回收站中时不能恢复资源，需先恢复父资源
+type Parent struct {
+	// 父资源模型，如 &model.Category{}
+	Model any
+	// 资源中引用父资源主键的外键列，如 category_id
+	ForeignKey string
+}
+
+// 检查资源（resource 为资源模型，如 &model.Entry{}）的父资源是否在回收站中
+func (p *Parent) checkRestorable(ctx context.Context, tx *gorm.DB, resource any, id any) error {
+	value := newModel(p.Model)
+	if _, ok := value.(softDeleter); !ok {
+		return nil
+	}
+	foreignKey := tx.Unscoped().Model(resource).Select(p.ForeignKey).Where("id = ?", id)
+	var count int64
+	err := tx.Unscoped().Model(value).Where("id = (?) AND deleted_at IS NOT NULL", foreignKey).Count(&count).Error
+	if err != nil {
+		return err
+	}
+	if count != 0 {
+		return NewError(http.StatusConflict, fmt.Sprintf(
+			i18n.T(ctx, "record %v cannot be restored: its parent record is in the trash, restore it first"), id,
+		))
+	}
+	return nil
+}
+
+// 删除标记：同一次删除的资源及级联删除的子资源使用相同的删除时间 & 删除者，恢复资源时据此一并恢复子资源
+type deletion struct {
+	at time.Time
+	by string
+}
+
+// 删除满足条件的记录：支持软删除的模型记录删除标记（仅标记未删除的记录，已在回收站中的保留原有标记）
+func deleteRecords(tx *gorm.DB, value any, marker deletion, query string, args ...any) error {
+	if _, ok := value.(softDeleter); ok {
+		return tx.Model(value).Where(query, args...).
+			UpdateColumns(map[string]any{"deleted_at": marker.at, "deleted_by": marker.by}).Error
+	}
+	return tx.Where(query, args...).Delete(value).Error
+}
+
+// 恢复回收站中的记录时更新的字段：清除删除标记，可记录更新者的模型同时记录更新者
+func restoredValues(value any, user string) map[string]any {
+	values := map[string]any{"deleted_at": nil, "deleted_by": ""}
+	if _, ok := value.(userStamper); ok {
+		values["updater"] = user
+	}
+	return values
+}
+
+// 新建模型（如 &model.Entry{}）的零值实例
+func newModel(model any) any {
+	return reflect.New(reflect.TypeOf(model).Elem()).Interface()
+}
+
+// 列是否允许为 NULL：未声明 not null，且 Go 类型可表示 NULL（指针 / sql.Null* 等实现了 sql.Scanner 的类型）
+func nullable(field *schema.Field) bool {
+	if field.NotNull {
+		return false
+	}
+	return field.FieldType.Kind() == reflect.Pointer ||
+		reflect.PointerTo(field.FieldType).Implements(reflect.TypeFor[sql.Scanner]())
+}
+
+// softDeleter 支持软删除的模型（如内嵌 model.SoftDeleteModel）
+type softDeleter interface {
+	IsDeleted() bool
+	// 删除时间 & 删除者
+	Deletion() (time.Time, string)
+}
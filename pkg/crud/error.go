@@ -0,0 +1,52 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - Go 开发框架 (BlueKing - Go Framework) available.
+ * Copyright (C) 2017 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ *	https://opensource.org/licenses/MIT
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package crud
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+
+	"github.com/TencentBlueKing/blueapps-go/pkg/utils/ginx"
+)
+
+// Error 携带 HTTP 状态码的错误，钩子（如 Validate）可通过其指定响应的状态码
+type Error struct {
+	StatusCode int
+	Message    string
+}
+
+// NewError ...
+func NewError(statusCode int, message string) *Error {
+	return &Error{StatusCode: statusCode, Message: message}
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// 设置错误响应：*Error 使用其状态码，其余错误视为请求不合法（400）
+func setErrResp(c *gin.Context, err error) {
+	if e := (*Error)(nil); errors.As(err, &e) {
+		ginx.SetErrResp(c, e.StatusCode, e.Message)
+		return
+	}
+	ginx.SetErrResp(c, http.StatusBadRequest, err.Error())
+}
@@ -0,0 +1,418 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - Go 开发框架 (BlueKing - Go Framework) available.
+ * Copyright (C) 2017 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ *	https://opensource.org/licenses/MIT
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package crud 提供基于 gorm 模型的通用 CRUD 资源：声明模型、请求 / 响应结构及少量钩子后，
+// 即可注册列表（分页）、创建、详情、更新、删除五个 API（支持软删除的模型另有回收站列表 & 恢复），
+// 并统一错误响应 & 记录创建者 / 更新者 / 删除者
+package crud
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	"github.com/samber/lo"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/TencentBlueKing/blueapps-go/pkg/i18n"
+	"github.com/TencentBlueKing/blueapps-go/pkg/infras/database"
+	"github.com/TencentBlueKing/blueapps-go/pkg/utils/ginx"
+)
+
+// Resource 通用 CRUD 资源
+//
+// M 为 gorm 模型（需以 id 为主键，内嵌 model.BaseModel 时会自动记录创建者 / 更新者，
+// 内嵌 model.SoftDeleteModel 时删除为软删除，并记录删除者），
+// CreateReq / UpdateReq 为创建 / 更新请求体（支持 binding 标签校验），Resp 为列表 & 详情的响应结构
+type Resource[M, CreateReq, UpdateReq, Resp any] struct {
+	// 根据创建请求构建模型（必填）
+	NewModel func(req *CreateReq) *M
+	// 将更新请求应用到模型（必填）
+	ApplyUpdate func(m *M, req *UpdateReq)
+	// 将模型转换为响应（必填）
+	ToResponse func(m *M) Resp
+	// 写入 DB 前的校验（如名称唯一、关联数据存在），返回 *Error 时使用其状态码，否则为 400
+	Validate func(c *gin.Context, m *M) error
+	// 查询范围，对列表 / 详情 / 更新 / 删除均生效，可用于过滤、预加载关联、数据权限等
+	Scope func(c *gin.Context, tx *gorm.DB) *gorm.DB
+	// 列表 API 的查询白名单（过滤 / 排序 / 关键字搜索 / 字段选择），为空表示不支持这些查询参数
+	Query *ginx.QuerySchema
+	// 列表 API 是否支持游标分页（携带 cursor 参数时启用，响应为 ginx.CursorPaginatedResp），适用于数据量较大的资源
+	CursorPagination bool
+	// 列表 API 是否不分页（直接响应全部资源 []Resp），适用于数据量较小的资源，不能与 CursorPagination 同时开启
+	Unpaginated bool
+	// 创建 API 的响应（如仅响应 ID），为空则响应创建后的资源
+	CreateResponse func(m *M) any
+	// 更新 API 是否响应 204（无响应体），默认响应更新后的资源
+	UpdateNoContent bool
+	// 子资源及删除策略（restrict / cascade / set-null），删除资源时在同一事务中处理
+	Dependents []Dependent
+	// 父资源，父资源在回收站中时不能恢复资源
+	Parents []Parent
+}
+
+// Register 在路由组上注册资源的 API：GET "" / POST "" / GET "/:id" / PUT "/:id" / DELETE "/:id"，
+// 支持软删除的模型另注册 POST "/:id/restore"
+func (r *Resource[M, CreateReq, UpdateReq, Resp]) Register(rg *gin.RouterGroup) {
+	r.MustCheck()
+	rg.GET("", r.List)
+	rg.POST("", r.Create)
+	rg.GET("/:id", r.Retrieve)
+	rg.PUT("/:id", r.Update)
+	rg.DELETE("/:id", r.Destroy)
+	if r.softDelete() {
+		rg.POST("/:id/restore", r.Restore)
+	}
+}
+
+// MustCheck 校验资源声明（如 set-null 策略的外键列需允许为 NULL），不合法时 panic
+// 注：Register 会自动校验，逐个注册 handler（如为生成 swagger 文档）时需在注册路由时调用
+func (r *Resource[M, CreateReq, UpdateReq, Resp]) MustCheck() {
+	if r.Unpaginated && r.CursorPagination {
+		panic("crud: Unpaginated and CursorPagination cannot be enabled at the same time")
+	}
+	for _, dep := range r.Dependents {
+		if err := dep.check(); err != nil {
+			panic(fmt.Sprintf("crud: invalid dependent of %T: %s", new(M), err))
+		}
+	}
+}
+
+// List 分页获取资源列表，响应为 ginx.PaginatedResp{results=[]Resp}，声明了 Query 时支持过滤 / 排序 / 字段选择，
+// 开启 CursorPagination 且携带 cursor 参数时使用游标分页，响应为 ginx.CursorPaginatedResp{results=[]Resp}，
+// 开启 Unpaginated 时不分页，响应为 []Resp；
+// 支持软删除的模型可通过 trashed=true 获取回收站（已删除）中的资源
+func (r *Resource[M, CreateReq, UpdateReq, Resp]) List(c *gin.Context) {
+	tx := r.query(c)
+	if r.softDelete() && c.Query("trashed") == "true" {
+		tx = tx.Unscoped().Where("deleted_at IS NOT NULL")
+	}
+	var listQuery *ginx.ListQuery
+	if r.Query != nil {
+		var err error
+		if listQuery, err = ginx.ParseListQuery(c, r.Query); err != nil {
+			ginx.SetErrResp(c, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	if r.CursorPagination && ginx.UseCursorPagination(c) {
+		r.listByCursor(c, tx, listQuery)
+		return
+	}
+	if listQuery != nil {
+		tx = tx.Scopes(listQuery.Scope)
+	}
+	if r.Unpaginated {
+		r.listAll(c, tx, listQuery)
+		return
+	}
+
+	// 总数量
+	var total int64
+	if err := tx.Count(&total).Error; err != nil {
+		ginx.SetErrResp(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	// 分页对应数据
+	var records []*M
+	if err := tx.Offset(ginx.GetOffset(c)).Limit(ginx.GetLimit(c)).Find(&records).Error; err != nil {
+		ginx.SetErrResp(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	results, err := r.toResults(records, listQuery)
+	if err != nil {
+		ginx.SetErrResp(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	ginx.SetResp(c, http.StatusOK, ginx.NewPaginatedRespData(total, results))
+}
+
+// 不分页获取全部资源
+func (r *Resource[M, CreateReq, UpdateReq, Resp]) listAll(c *gin.Context, tx *gorm.DB, listQuery *ginx.ListQuery) {
+	var records []*M
+	if err := tx.Find(&records).Error; err != nil {
+		ginx.SetErrResp(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	results, err := r.toResults(records, listQuery)
+	if err != nil {
+		ginx.SetErrResp(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	ginx.SetResp(c, http.StatusOK, results)
+}
+
+// 游标分页获取资源列表，以排序字段 + 主键作为排序键，不统计总数量
+func (r *Resource[M, CreateReq, UpdateReq, Resp]) listByCursor(
+	c *gin.Context, tx *gorm.DB, listQuery *ginx.ListQuery,
+) {
+	keys := []ginx.SortKey{}
+	if listQuery != nil {
+		tx = tx.Scopes(listQuery.FilterScope)
+		keys = append(keys, listQuery.SortKeys...)
+	}
+	// 以主键结尾，保证排序键能唯一确定行的顺序
+	if !lo.ContainsBy(keys, func(key ginx.SortKey) bool { return key.Column == "id" }) {
+		keys = append(keys, ginx.SortKey{Column: "id", Type: ginx.FieldTypeInt})
+	}
+	paginator, err := ginx.NewCursorPaginator(c, keys)
+	if err != nil {
+		statusCode := lo.Ternary(
+			errors.Is(err, ginx.ErrCursorSecretNotConfigured), http.StatusInternalServerError, http.StatusBadRequest,
+		)
+		ginx.SetErrResp(c, statusCode, err.Error())
+		return
+	}
+
+	var records []*M
+	result := tx.Scopes(paginator.Scope).Find(&records)
+	if result.Error != nil {
+		ginx.SetErrResp(c, http.StatusInternalServerError, result.Error.Error())
+		return
+	}
+	ctx, sch := c.Request.Context(), result.Statement.Schema
+	records, next, prev := ginx.CursorPaginate(paginator, records, func(m *M) []any {
+		return lo.Map(keys, func(key ginx.SortKey, _ int) any {
+			value, _ := sch.LookUpField(key.Column).ValueOf(ctx, reflect.ValueOf(m).Elem())
+			return value
+		})
+	})
+
+	results, err := r.toResults(records, listQuery)
+	if err != nil {
+		ginx.SetErrResp(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	ginx.SetResp(c, http.StatusOK, ginx.NewCursorPaginatedRespData(results, next, prev))
+}
+
+// 将模型转换为列表响应，仅返回 fields 参数中选择的字段
+func (r *Resource[M, CreateReq, UpdateReq, Resp]) toResults(records []*M, listQuery *ginx.ListQuery) (any, error) {
+	respData := make([]Resp, 0, len(records))
+	for _, m := range records {
+		respData = append(respData, r.ToResponse(m))
+	}
+	if listQuery == nil {
+		return respData, nil
+	}
+	return listQuery.SelectFields(respData)
+}
+
+// Create 创建资源，响应为 201 & 创建后的资源（声明了 CreateResponse 时为其返回值）
+func (r *Resource[M, CreateReq, UpdateReq, Resp]) Create(c *gin.Context) {
+	var req CreateReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ginx.SetErrResp(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	m := r.NewModel(&req)
+	if err := r.validate(c, m); err != nil {
+		setErrResp(c, err)
+		return
+	}
+	if s, ok := any(m).(userStamper); ok {
+		s.SetCreator(ginx.GetUserID(c))
+	}
+	// 关联数据由各自的资源维护，不随之写入
+	if err := database.Client(c.Request.Context()).Omit(clause.Associations).Create(m).Error; err != nil {
+		ginx.SetErrResp(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if r.CreateResponse != nil {
+		ginx.SetResp(c, http.StatusCreated, r.CreateResponse(m))
+		return
+	}
+	r.respond(c, http.StatusCreated, m)
+}
+
+// Retrieve 获取单个资源
+func (r *Resource[M, CreateReq, UpdateReq, Resp]) Retrieve(c *gin.Context) {
+	m, err := r.get(c)
+	if err != nil {
+		setErrResp(c, err)
+		return
+	}
+	ginx.SetResp(c, http.StatusOK, r.ToResponse(m))
+}
+
+// Update 更新资源，响应为更新后的资源（开启 UpdateNoContent 时为 204）
+func (r *Resource[M, CreateReq, UpdateReq, Resp]) Update(c *gin.Context) {
+	var req UpdateReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ginx.SetErrResp(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	m, err := r.get(c)
+	if err != nil {
+		setErrResp(c, err)
+		return
+	}
+	r.ApplyUpdate(m, &req)
+	if err = r.validate(c, m); err != nil {
+		setErrResp(c, err)
+		return
+	}
+	if s, ok := any(m).(userStamper); ok {
+		s.SetUpdater(ginx.GetUserID(c))
+	}
+	// 注：Scope 中预加载的关联数据可能已过期（如修改了外键），不能随之写入
+	if err = database.Client(c.Request.Context()).Omit(clause.Associations).Save(m).Error; err != nil {
+		ginx.SetErrResp(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if r.UpdateNoContent {
+		ginx.SetResp(c, http.StatusNoContent, nil)
+		return
+	}
+	r.respond(c, http.StatusOK, m)
+}
+
+// Destroy 删除资源（支持软删除的模型移入回收站），并按删除策略处理子资源，响应为 204
+func (r *Resource[M, CreateReq, UpdateReq, Resp]) Destroy(c *gin.Context) {
+	m, err := r.get(c)
+	if err != nil {
+		setErrResp(c, err)
+		return
+	}
+	ctx, id := c.Request.Context(), c.Param("id")
+	marker := deletion{at: time.Now(), by: ginx.GetUserID(c)}
+	err = database.Client(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, dep := range r.Dependents {
+			if err := dep.apply(ctx, tx, id, marker); err != nil {
+				return err
+			}
+		}
+		return deleteRecords(tx, m, marker, "id = ?", id)
+	})
+	if err != nil {
+		if e := (*Error)(nil); !errors.As(err, &e) {
+			err = NewError(http.StatusInternalServerError, err.Error())
+		}
+		setErrResp(c, err)
+		return
+	}
+	ginx.SetResp(c, http.StatusNoContent, nil)
+}
+
+// Restore 恢复回收站中的资源（父资源需不在回收站中，且需重新通过校验，如名称未被占用、关联数据存在），
+// 随之级联删除的子资源一并恢复，响应为恢复后的资源
+func (r *Resource[M, CreateReq, UpdateReq, Resp]) Restore(c *gin.Context) {
+	ctx, id := c.Request.Context(), c.Param("id")
+	m := new(M)
+	err := r.query(c).Unscoped().Where("id = ? AND deleted_at IS NOT NULL", id).First(m).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		ginx.SetErrResp(c, http.StatusNotFound, fmt.Sprintf(i18n.T(ctx, "deleted record %s not found"), id))
+		return
+	} else if err != nil {
+		ginx.SetErrResp(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	for _, parent := range r.Parents {
+		if err = parent.checkRestorable(ctx, database.Client(ctx), new(M), id); err != nil {
+			if e := (*Error)(nil); !errors.As(err, &e) {
+				err = NewError(http.StatusInternalServerError, err.Error())
+			}
+			setErrResp(c, err)
+			return
+		}
+	}
+	if err = r.validate(c, m); err != nil {
+		setErrResp(c, err)
+		return
+	}
+
+	// 恢复前记录删除标记（更新后会被清除），用于恢复随之级联删除的子资源
+	deletedAt, deletedBy := any(m).(softDeleter).Deletion()
+	marker, user := deletion{at: deletedAt, by: deletedBy}, ginx.GetUserID(c)
+	err = database.Client(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Model(m).Updates(restoredValues(m, user)).Error; err != nil {
+			return err
+		}
+		for _, dep := range r.Dependents {
+			if err := dep.restore(tx, id, marker, user); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		ginx.SetErrResp(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	r.respond(c, http.StatusOK, m)
+}
+
+// 应用查询范围后的查询
+func (r *Resource[M, CreateReq, UpdateReq, Resp]) query(c *gin.Context) *gorm.DB {
+	tx := database.Client(c.Request.Context()).Model(new(M))
+	if r.Scope != nil {
+		tx = r.Scope(c, tx)
+	}
+	return tx
+}
+
+// 模型是否支持软删除
+func (r *Resource[M, CreateReq, UpdateReq, Resp]) softDelete() bool {
+	_, ok := any(new(M)).(softDeleter)
+	return ok
+}
+
+// 获取路径参数 id 对应的资源（受查询范围限制）
+func (r *Resource[M, CreateReq, UpdateReq, Resp]) get(c *gin.Context) (*M, error) {
+	m := new(M)
+	if err := r.query(c).Where("id = ?", c.Param("id")).First(m).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewError(
+				http.StatusNotFound, fmt.Sprintf(i18n.T(c.Request.Context(), "record %s not found"), c.Param("id")),
+			)
+		}
+		return nil, NewError(http.StatusInternalServerError, err.Error())
+	}
+	return m, nil
+}
+
+// 执行校验钩子
+func (r *Resource[M, CreateReq, UpdateReq, Resp]) validate(c *gin.Context, m *M) error {
+	if r.Validate == nil {
+		return nil
+	}
+	return r.Validate(c, m)
+}
+
+// 重新获取写入后的资源（以加载关联数据）并响应，若其不在查询范围内则直接响应写入的数据
+func (r *Resource[M, CreateReq, UpdateReq, Resp]) respond(c *gin.Context, statusCode int, m *M) {
+	if err := r.query(c).First(m).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		ginx.SetErrResp(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	ginx.SetResp(c, statusCode, r.ToResponse(m))
+}
+
+// userStamper 可记录创建者 / 更新者的模型（如内嵌 model.BaseModel）
+type userStamper interface {
+	SetCreator(user string)
+	SetUpdater(user string)
+}
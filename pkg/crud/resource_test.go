@@ -0,0 +1,239 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - Go 开发框架 (BlueKing - Go Framework) available.
+ * Copyright (C) 2017 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ *	https://opensource.org/licenses/MIT
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package crud
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+
+	"github.com/TencentBlueKing/blueapps-go/pkg/model"
+)
+
+type book struct {
+	model.BaseModel
+	ID   int64
+	Name string
+}
+
+type note struct {
+	model.BaseModel
+	model.SoftDeleteModel
+	ID     int64
+	BookID int64
+}
+
+type bookReq struct {
+	Name string `json:"name" binding:"required"`
+}
+
+func TestRegister(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	resource := &Resource[book, bookReq, bookReq, string]{}
+	resource.Register(router.Group("/books"))
+
+	routes := lo.Map(router.Routes(), func(r gin.RouteInfo, _ int) string { return r.Method + " " + r.Path })
+	assert.ElementsMatch(t, []string{
+		"GET /books", "POST /books", "GET /books/:id", "PUT /books/:id", "DELETE /books/:id",
+	}, routes)
+
+	// 请求体不合法时，不会访问 DB
+	req, _ := http.NewRequest(http.MethodPost, "/books", strings.NewReader(`{}`))
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+
+	// 支持软删除的模型可恢复
+	(&Resource[note, bookReq, bookReq, string]{}).Register(router.Group("/notes"))
+	routes = lo.Map(router.Routes(), func(r gin.RouteInfo, _ int) string { return r.Method + " " + r.Path })
+	assert.Contains(t, routes, "POST /notes/:id/restore")
+	assert.NotContains(t, routes, "POST /books/:id/restore")
+
+	// 不分页与游标分页不能同时开启
+	assert.Panics(t, func() {
+		(&Resource[book, bookReq, bookReq, string]{Unpaginated: true, CursorPagination: true}).Register(router.Group("/x"))
+	})
+}
+
+// 记录执行的 SQL（DryRun，不连接 DB）
+func recordSQL(t *testing.T) (*gorm.DB, *[]string) {
+	db, err := gorm.Open(
+		mysql.New(mysql.Config{DSN: "user:pass@tcp(127.0.0.1:3306)/db", SkipInitializeWithVersion: true}),
+		// 默认事务需要连接 DB，这里跳过
+		&gorm.Config{DryRun: true, DisableAutomaticPing: true, SkipDefaultTransaction: true},
+	)
+	assert.NoError(t, err)
+
+	sqls := []string{}
+	record := func(tx *gorm.DB) {
+		sqls = append(sqls, tx.Dialector.Explain(tx.Statement.SQL.String(), tx.Statement.Vars...))
+	}
+	assert.NoError(t, db.Callback().Query().After("gorm:query").Register("test:record", record))
+	assert.NoError(t, db.Callback().Update().After("gorm:update").Register("test:record", record))
+	assert.NoError(t, db.Callback().Delete().After("gorm:delete").Register("test:record", record))
+	return db, &sqls
+}
+
+var marker = deletion{at: time.Date(2026, 10, 18, 10, 0, 0, 0, time.Local), by: "admin"}
+
+func TestDeletePolicy(t *testing.T) {
+	for _, tc := range []struct {
+		dep  Dependent
+		sqls []string
+	}{
+		{
+			Dependent{Model: &note{}, ForeignKey: "book_id", Policy: DeleteRestrict},
+			[]string{"SELECT count(*) FROM `notes` WHERE book_id = 1 AND `notes`.`deleted_at` IS NULL"},
+		},
+		{
+			// 软删除的子资源记录与资源相同的删除标记（删除时间 & 删除者），已在回收站中的子资源保留原有标记
+			Dependent{Model: &note{}, ForeignKey: "book_id", Policy: DeleteCascade},
+			[]string{
+				"UPDATE `notes` SET `deleted_at`='2026-10-18 10:00:00',`deleted_by`='admin' " +
+					"WHERE book_id = 1 AND `notes`.`deleted_at` IS NULL",
+			},
+		},
+		{
+			Dependent{Model: &book{}, ForeignKey: "parent_id", Policy: DeleteCascade},
+			[]string{"DELETE FROM `books` WHERE parent_id = 1"},
+		},
+		{
+			// 回收站中的子资源同样置空
+			Dependent{Model: &note{}, ForeignKey: "book_id", Policy: DeleteSetNull},
+			[]string{"UPDATE `notes` SET `book_id`=NULL WHERE book_id = 1"},
+		},
+	} {
+		db, sqls := recordSQL(t)
+		model := reflect.ValueOf(tc.dep.Model).Elem().Interface()
+		assert.NoError(t, tc.dep.apply(context.Background(), db, 1, marker))
+		assert.Len(t, *sqls, len(tc.sqls), tc.dep.Policy)
+		for i, sql := range tc.sqls {
+			assert.True(t, strings.HasPrefix((*sqls)[i], sql), (*sqls)[i])
+		}
+		// 共享的子资源模型声明不会被修改（如回写 deleted_by / deleted_at）
+		assert.Equal(t, model, reflect.ValueOf(tc.dep.Model).Elem().Interface(), tc.dep.Policy)
+	}
+}
+
+func TestDependentCheck(t *testing.T) {
+	type comment struct {
+		ID     int64
+		NoteID *int64
+	}
+	// 外键列需允许为 NULL 才能使用 set-null 策略
+	assert.NoError(t, (&Dependent{Model: &comment{}, ForeignKey: "note_id", Policy: DeleteSetNull}).check())
+	assert.Error(t, (&Dependent{Model: &note{}, ForeignKey: "book_id", Policy: DeleteSetNull}).check())
+	assert.Error(t, (&Dependent{Model: &model.Entry{}, ForeignKey: "category_id", Policy: DeleteSetNull}).check())
+	assert.NoError(t, (&Dependent{Model: &model.Entry{}, ForeignKey: "category_id", Policy: DeleteCascade}).check())
+	// 外键列需存在
+	assert.Error(t, (&Dependent{Model: &note{}, ForeignKey: "book", Policy: DeleteRestrict}).check())
+
+	// 注册时校验
+	gin.SetMode(gin.TestMode)
+	resource := &Resource[book, bookReq, bookReq, string]{
+		Dependents: []Dependent{{Model: &model.Entry{}, ForeignKey: "category_id", Policy: DeleteSetNull}},
+	}
+	assert.Panics(t, func() { resource.Register(gin.New().Group("/books")) })
+}
+
+func TestDependentRestore(t *testing.T) {
+	// 仅恢复删除标记与资源相同（随之级联删除）的子资源
+	db, sqls := recordSQL(t)
+	dep := &Dependent{Model: &note{}, ForeignKey: "book_id", Policy: DeleteCascade}
+	assert.NoError(t, dep.restore(db, 1, marker, "blueking"))
+	assert.Len(t, *sqls, 1)
+	assert.Regexp(t, "^UPDATE `notes` SET `deleted_at`=NULL,`deleted_by`='',`updater`='blueking',`updated_at`='.+' "+
+		"WHERE book_id = 1 AND deleted_at = '2026-10-18 10:00:00' AND deleted_by = 'admin'$", (*sqls)[0])
+
+	// 其他策略 / 不支持软删除的子资源无需恢复
+	db, sqls = recordSQL(t)
+	assert.NoError(t, (&Dependent{Model: &note{}, ForeignKey: "book_id", Policy: DeleteRestrict}).restore(
+		db, 1, marker, "blueking",
+	))
+	assert.NoError(t, (&Dependent{Model: &book{}, ForeignKey: "parent_id", Policy: DeleteCascade}).restore(
+		db, 1, marker, "blueking",
+	))
+	assert.Empty(t, *sqls)
+}
+
+func TestParentCheckRestorable(t *testing.T) {
+	type page struct {
+		ID     int64
+		NoteID int64
+	}
+	db, sqls := recordSQL(t)
+	parent := &Parent{Model: &note{}, ForeignKey: "note_id"}
+	assert.NoError(t, parent.checkRestorable(context.Background(), db, &page{}, 1))
+	// 注：构建子查询时同样会执行（DryRun）查询回调，因此最后一条为实际执行的 SQL
+	assert.Equal(t,
+		"SELECT count(*) FROM `notes` WHERE id = (SELECT `note_id` FROM `pages` WHERE id = 1) AND deleted_at IS NOT NULL",
+		(*sqls)[len(*sqls)-1],
+	)
+
+	// 不支持软删除的父资源无需检查
+	db, sqls = recordSQL(t)
+	assert.NoError(t, (&Parent{Model: &book{}, ForeignKey: "book_id"}).checkRestorable(
+		context.Background(), db, &page{}, 1,
+	))
+	assert.Empty(t, *sqls)
+}
+
+func TestSetErrResp(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	for _, tc := range []struct {
+		err        error
+		statusCode int
+	}{
+		{errors.New("name already used"), http.StatusBadRequest},
+		{NewError(http.StatusNotFound, "record 1 not found"), http.StatusNotFound},
+		{errors.Wrap(NewError(http.StatusConflict, "conflict"), "wrapped"), http.StatusConflict},
+	} {
+		recorder := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(recorder)
+		setErrResp(c, tc.err)
+		assert.Equal(t, tc.statusCode, recorder.Code, tc.err.Error())
+	}
+}
+
+func TestUserStamper(t *testing.T) {
+	b := &book{}
+	s, ok := any(b).(userStamper)
+	assert.True(t, ok)
+
+	s.SetCreator("admin")
+	assert.Equal(t, "admin", b.Creator)
+	assert.Equal(t, "admin", b.Updater)
+	s.SetUpdater("blueking")
+	assert.Equal(t, "admin", b.Creator)
+	assert.Equal(t, "blueking", b.Updater)
+}
@@ -89,6 +89,44 @@ const docTemplate = `{
                     "crud"
                 ],
                 "summary": "获取分类列表",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "关键字（名称 / 更新者）",
+                        "name": "keyword",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "名称，支持 name__contains",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "更新时间，支持 updatedAt__{gt,gte,lt,lte}",
+                        "name": "updatedAt",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "排序字段（逗号分隔，- 表示降序），可选 id / name / createdAt / updatedAt",
+                        "name": "ordering",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "返回字段（逗号分隔），默认返回全部字段",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "为 true 时获取回收站（已删除）中的分类",
+                        "name": "trashed",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -103,7 +141,7 @@ const docTemplate = `{
                                         "data": {
                                             "type": "array",
                                             "items": {
-                                                "$ref": "#/definitions/serializer.CategoryListResponse"
+                                                "$ref": "#/definitions/serializer.CategoryResponse"
                                             }
                                         }
                                     }
@@ -141,7 +179,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "object"
+                                            "$ref": "#/definitions/serializer.CategoryCreateResponse"
                                         }
                                     }
                                 }
@@ -178,7 +216,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/serializer.CategoryRetrieveResponse"
+                                            "$ref": "#/definitions/serializer.CategoryResponse"
                                         }
                                     }
                                 }
@@ -220,7 +258,7 @@ const docTemplate = `{
                 "tags": [
                     "crud"
                 ],
-                "summary": "删除分类",
+                "summary": "删除分类（连同其下的条目移入回收站）",
                 "parameters": [
                     {
                         "type": "integer",
@@ -237,26 +275,73 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/emails": {
+        "/api/categories/{id}/restore": {
             "post": {
                 "tags": [
-                    "cloud-api"
+                    "crud"
                 ],
-                "summary": "发送邮件",
+                "summary": "恢复回收站中的分类（连同随之删除的条目）",
                 "parameters": [
                     {
-                        "description": "邮件配置",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
+                        "type": "integer",
+                        "description": "分类 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/serializer.SendEmailRequest"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/ginx.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/serializer.CategoryResponse"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     }
+                }
+            }
+        },
+        "/api/cron/preview": {
+            "get": {
+                "tags": [
+                    "async-task"
+                ],
+                "summary": "预览 cron 表达式（接下来的触发时间 \u0026 自然语言描述）",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "cron 表达式",
+                        "name": "expr",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA 时区，如 Asia/Shanghai",
+                        "name": "tz",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "预览的触发次数，默认 5 次",
+                        "name": "count",
+                        "in": "query"
+                    }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
                             "allOf": [
                                 {
@@ -266,7 +351,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "object"
+                                            "$ref": "#/definitions/serializer.CronPreviewResponse"
                                         }
                                     }
                                 }
@@ -276,12 +361,30 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/entries": {
+        "/api/dead-letters": {
             "get": {
                 "tags": [
-                    "crud"
+                    "async-task"
+                ],
+                "summary": "获取死信列表",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "任务名称",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "true",
+                            "false"
+                        ],
+                        "type": "string",
+                        "description": "是否已重新下发",
+                        "name": "replayed",
+                        "in": "query"
+                    }
                 ],
-                "summary": "获取条目列表",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -304,7 +407,7 @@ const docTemplate = `{
                                                         "results": {
                                                             "type": "array",
                                                             "items": {
-                                                                "$ref": "#/definitions/serializer.EntryListResponse"
+                                                                "$ref": "#/definitions/serializer.DeadLetterListResponse"
                                                             }
                                                         }
                                                     }
@@ -317,26 +420,28 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/api/dead-letters/replay": {
             "post": {
                 "tags": [
-                    "crud"
+                    "async-task"
                 ],
-                "summary": "创建条目",
+                "summary": "批量重新下发死信中的任务（可逐个修正任务参数），返回每个死信的下发结果",
                 "parameters": [
                     {
-                        "description": "创建条目请求体",
+                        "description": "待重新下发的死信",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/serializer.EntryCreateRequest"
+                            "$ref": "#/definitions/serializer.DeadLetterBulkReplayRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
                             "allOf": [
                                 {
@@ -346,7 +451,10 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "object"
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/serializer.DeadLetterReplayResponse"
+                                            }
                                         }
                                     }
                                 }
@@ -356,16 +464,16 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/entries/{id}": {
+        "/api/dead-letters/{id}": {
             "get": {
                 "tags": [
-                    "crud"
+                    "async-task"
                 ],
-                "summary": "获取单个条目",
+                "summary": "获取单个死信",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "条目 ID",
+                        "description": "死信 ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -383,7 +491,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/serializer.EntryRetrieveResponse"
+                                            "$ref": "#/definitions/serializer.DeadLetterRetrieveResponse"
                                         }
                                     }
                                 }
@@ -391,71 +499,68 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/api/dead-letters/{id}/replay": {
+            "post": {
                 "tags": [
-                    "crud"
+                    "async-task"
                 ],
-                "summary": "更新条目",
+                "summary": "重新下发死信中的任务（可修正任务参数）",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "条目 ID",
+                        "description": "死信 ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "更新条目请求体",
+                        "description": "修正后的任务参数",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/serializer.EntryUpdateRequest"
+                            "$ref": "#/definitions/serializer.DeadLetterReplayRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
-                    }
-                }
-            },
-            "delete": {
-                "tags": [
-                    "crud"
-                ],
-                "summary": "删除条目",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "条目 ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/ginx.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/serializer.DeadLetterReplayResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
                     }
                 }
             }
         },
-        "/api/obj-storage/dirs": {
+        "/api/emails": {
             "post": {
                 "tags": [
-                    "object-storage"
+                    "cloud-api"
                 ],
-                "summary": "创建目录",
+                "summary": "发送邮件",
                 "parameters": [
                     {
-                        "description": "创建目录请求体",
+                        "description": "邮件配置",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/serializer.CreateDirRequest"
+                            "$ref": "#/definitions/serializer.SendEmailRequest"
                         }
                     }
                 ],
@@ -479,39 +584,80 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "tags": [
-                    "object-storage"
-                ],
-                "summary": "删除目录",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "name": "dirPath",
-                        "in": "query",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "204": {
-                        "description": "No Content"
-                    }
-                }
             }
         },
-        "/api/obj-storage/objects": {
+        "/api/entries": {
             "get": {
                 "tags": [
-                    "object-storage"
+                    "crud"
                 ],
-                "summary": "获取已上传对象列表",
+                "summary": "获取条目列表",
                 "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "分类 ID，支持 categoryID__in（逗号分隔）",
+                        "name": "categoryID",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
-                        "name": "dirPath",
-                        "in": "query",
-                        "required": true
+                        "description": "关键字（名称 / 描述 / 更新者）",
+                        "name": "keyword",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "名称，支持 name__contains",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "价格，支持 price__{gt,gte,lt,lte,in}",
+                        "name": "price",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "更新时间，支持 updatedAt__{gt,gte,lt,lte}",
+                        "name": "updatedAt",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "排序字段（逗号分隔，- 表示降序），可选 id / name / price / createdAt / updatedAt",
+                        "name": "ordering",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "返回字段（逗号分隔），默认返回全部字段",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "为 true 时获取回收站（已删除）中的条目",
+                        "name": "trashed",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "页码",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "每页数量",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "游标（首页传空值，之后传响应中的 next / prev），携带时使用游标分页，响应为 ginx.CursorPaginatedResp（不支持按 price 排序）",
+                        "name": "cursor",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -536,7 +682,7 @@ const docTemplate = `{
                                                         "results": {
                                                             "type": "array",
                                                             "items": {
-                                                                "$ref": "#/definitions/serializer.CategoryListResponse"
+                                                                "$ref": "#/definitions/serializer.EntryResponse"
                                                             }
                                                         }
                                                     }
@@ -552,17 +698,17 @@ const docTemplate = `{
             },
             "post": {
                 "tags": [
-                    "object-storage"
+                    "crud"
                 ],
-                "summary": "上传对象",
+                "summary": "创建条目",
                 "parameters": [
                     {
-                        "description": "上传对象请求体",
+                        "description": "创建条目请求体",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/serializer.UploadObjectRequest"
+                            "$ref": "#/definitions/serializer.EntryCreateRequest"
                         }
                     }
                 ],
@@ -578,7 +724,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "object"
+                                            "$ref": "#/definitions/serializer.EntryCreateResponse"
                                         }
                                     }
                                 }
@@ -586,22 +732,65 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "tags": [
-                    "object-storage"
+            }
+        },
+        "/api/entries/{id}": {
+            "get": {
+                "tags": [
+                    "crud"
                 ],
-                "summary": "删除已上传对象",
+                "summary": "获取单个条目",
                 "parameters": [
                     {
-                        "type": "string",
-                        "name": "dirPath",
-                        "in": "query"
+                        "type": "integer",
+                        "description": "条目 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/ginx.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/serializer.EntryResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "put": {
+                "tags": [
+                    "crud"
+                ],
+                "summary": "更新条目",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "条目 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "type": "string",
-                        "name": "objName",
-                        "in": "query"
+                        "description": "更新条目请求体",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/serializer.EntryUpdateRequest"
+                        }
                     }
                 ],
                 "responses": {
@@ -609,42 +798,43 @@ const docTemplate = `{
                         "description": "No Content"
                     }
                 }
-            }
-        },
-        "/api/obj-storage/objects/download": {
-            "get": {
+            },
+            "delete": {
                 "tags": [
-                    "object-storage"
+                    "crud"
                 ],
-                "summary": "下载对象",
+                "summary": "删除条目（移入回收站）",
                 "parameters": [
                     {
-                        "type": "string",
-                        "name": "dirPath",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "name": "objName",
-                        "in": "query"
+                        "type": "integer",
+                        "description": "条目 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "文件内容",
-                        "schema": {
-                            "type": "file"
-                        }
+                    "204": {
+                        "description": "No Content"
                     }
                 }
             }
         },
-        "/api/periodic-tasks": {
-            "get": {
+        "/api/entries/{id}/restore": {
+            "post": {
                 "tags": [
-                    "async-task"
+                    "crud"
+                ],
+                "summary": "恢复回收站中的条目（所属分类需不在回收站中）",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "条目 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "获取定时任务列表",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -657,10 +847,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/serializer.PeriodicTaskListResponse"
-                                            }
+                                            "$ref": "#/definitions/serializer.EntryResponse"
                                         }
                                     }
                                 }
@@ -668,20 +855,22 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/api/obj-storage/dirs": {
             "post": {
                 "tags": [
-                    "async-task"
+                    "object-storage"
                 ],
-                "summary": "创建定时任务",
+                "summary": "创建目录",
                 "parameters": [
                     {
-                        "description": "定时任务配置",
+                        "description": "创建目录请求体",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/serializer.PeriodicTaskCreateRequest"
+                            "$ref": "#/definitions/serializer.CreateDirRequest"
                         }
                     }
                 ],
@@ -705,20 +894,17 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/periodic-tasks/{id}": {
+            },
             "delete": {
                 "tags": [
-                    "async-task"
+                    "object-storage"
                 ],
-                "summary": "删除定时任务",
+                "summary": "删除目录",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "定时任务 ID",
-                        "name": "id",
-                        "in": "path",
+                        "type": "string",
+                        "name": "dirPath",
+                        "in": "query",
                         "required": true
                     }
                 ],
@@ -729,34 +915,20 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/periodic-tasks/{id}/enabled": {
-            "put": {
+        "/api/obj-storage/objects": {
+            "get": {
                 "tags": [
-                    "async-task"
+                    "object-storage"
                 ],
-                "summary": "切换定时任务启用状态",
+                "summary": "获取已上传对象列表",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "定时任务 ID",
-                        "name": "id",
-                        "in": "path",
+                        "type": "string",
+                        "name": "dirPath",
+                        "in": "query",
                         "required": true
                     }
                 ],
-                "responses": {
-                    "204": {
-                        "description": "No Content"
-                    }
-                }
-            }
-        },
-        "/api/tasks": {
-            "get": {
-                "tags": [
-                    "async-task"
-                ],
-                "summary": "获取任务列表",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -779,7 +951,7 @@ const docTemplate = `{
                                                         "results": {
                                                             "type": "array",
                                                             "items": {
-                                                                "$ref": "#/definitions/serializer.TaskListResponse"
+                                                                "$ref": "#/definitions/serializer.ListObjectsResponse"
                                                             }
                                                         }
                                                     }
@@ -795,17 +967,17 @@ const docTemplate = `{
             },
             "post": {
                 "tags": [
-                    "async-task"
+                    "object-storage"
                 ],
-                "summary": "创建异步任务",
+                "summary": "上传对象",
                 "parameters": [
                     {
-                        "description": "异步任务配置",
+                        "description": "上传对象请求体",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/serializer.TaskCreateRequest"
+                            "$ref": "#/definitions/serializer.UploadObjectRequest"
                         }
                     }
                 ],
@@ -829,39 +1001,24 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/healthz": {
-            "get": {
+            },
+            "delete": {
                 "tags": [
-                    "basic"
+                    "object-storage"
                 ],
-                "summary": "提供服务健康状态",
+                "summary": "删除已上传对象",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "healthz api token",
-                        "name": "token",
-                        "in": "query",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/serializer.HealthzResponse"
-                        }
+                        "name": "dirPath",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "objName",
+                        "in": "query"
                     }
-                }
-            }
-        },
-        "/language": {
-            "put": {
-                "tags": [
-                    "basic"
                 ],
-                "summary": "修改语言",
                 "responses": {
                     "204": {
                         "description": "No Content"
@@ -869,61 +1026,499 @@ const docTemplate = `{
                 }
             }
         },
-        "/metrics": {
+        "/api/obj-storage/objects/download": {
             "get": {
-                "produces": [
-                    "text/plain"
-                ],
                 "tags": [
-                    "basic"
+                    "object-storage"
                 ],
-                "summary": "Prometheus 指标",
+                "summary": "下载对象",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "metrics api token",
-                        "name": "token",
-                        "in": "query",
-                        "required": true
+                        "name": "dirPath",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "objName",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "文件内容",
                         "schema": {
-                            "type": "string"
+                            "type": "file"
                         }
                     }
                 }
             }
         },
-        "/ping": {
+        "/api/periodic-tasks": {
             "get": {
-                "produces": [
-                    "text/plain"
-                ],
                 "tags": [
-                    "basic"
+                    "async-task"
                 ],
-                "summary": "服务探活",
+                "summary": "获取定时任务列表",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "string"
-                        }
-                    }
-                }
-            }
-        },
-        "/version": {
-            "get": {
-                "tags": [
-                    "basic"
-                ],
-                "summary": "服务版本信息",
-                "responses": {
-                    "200": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/ginx.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/serializer.PeriodicTaskListResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "post": {
+                "tags": [
+                    "async-task"
+                ],
+                "summary": "创建定时任务",
+                "parameters": [
+                    {
+                        "description": "定时任务配置",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/serializer.PeriodicTaskCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/ginx.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/periodic-tasks/{id}": {
+            "delete": {
+                "tags": [
+                    "async-task"
+                ],
+                "summary": "删除定时任务",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "定时任务 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/api/periodic-tasks/{id}/enabled": {
+            "put": {
+                "tags": [
+                    "async-task"
+                ],
+                "summary": "切换定时任务启用状态",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "定时任务 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/api/periodic-tasks/{id}/runs": {
+            "get": {
+                "tags": [
+                    "async-task"
+                ],
+                "summary": "获取定时任务触发记录",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "定时任务 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/ginx.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "allOf": [
+                                                {
+                                                    "$ref": "#/definitions/ginx.PaginatedResp"
+                                                },
+                                                {
+                                                    "type": "object",
+                                                    "properties": {
+                                                        "results": {
+                                                            "type": "array",
+                                                            "items": {
+                                                                "$ref": "#/definitions/serializer.PeriodicTaskRunListResponse"
+                                                            }
+                                                        }
+                                                    }
+                                                }
+                                            ]
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/tasks": {
+            "get": {
+                "tags": [
+                    "async-task"
+                ],
+                "summary": "获取任务列表",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "任务名称",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "pending",
+                            "running",
+                            "succeeded",
+                            "failed",
+                            "cancelled",
+                            "timeout"
+                        ],
+                        "type": "string",
+                        "description": "任务状态",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "页码",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "每页数量",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "游标（首页传空值，之后传响应中的 next / prev），携带时使用游标分页，响应为 ginx.CursorPaginatedResp",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/ginx.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "allOf": [
+                                                {
+                                                    "$ref": "#/definitions/ginx.PaginatedResp"
+                                                },
+                                                {
+                                                    "type": "object",
+                                                    "properties": {
+                                                        "results": {
+                                                            "type": "array",
+                                                            "items": {
+                                                                "$ref": "#/definitions/serializer.TaskListResponse"
+                                                            }
+                                                        }
+                                                    }
+                                                }
+                                            ]
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "post": {
+                "tags": [
+                    "async-task"
+                ],
+                "summary": "创建异步任务",
+                "parameters": [
+                    {
+                        "description": "异步任务配置",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/serializer.TaskCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/ginx.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/serializer.TaskCreateResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/tasks/{id}": {
+            "get": {
+                "tags": [
+                    "async-task"
+                ],
+                "summary": "获取单个任务",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "任务 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/ginx.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/serializer.TaskRetrieveResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/tasks/{id}/cancel": {
+            "post": {
+                "tags": [
+                    "async-task"
+                ],
+                "summary": "取消任务（等待执行 / 执行中）",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "任务 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/api/tasks/{id}/events": {
+            "get": {
+                "description": "事件类型：progress（data 为 serializer.TaskProgressEvent），status（data 为 serializer.TaskStatusEvent）；\n连接建立后会先推送当前的状态 \u0026 进度，任务结束后事件流随之结束",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "async-task"
+                ],
+                "summary": "订阅任务执行进度 \u0026 状态变更（Server-Sent Events）",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "任务 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/serializer.TaskProgressEvent"
+                        }
+                    }
+                }
+            }
+        },
+        "/healthz": {
+            "get": {
+                "tags": [
+                    "basic"
+                ],
+                "summary": "提供服务健康状态",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "healthz api token",
+                        "name": "token",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/serializer.HealthzResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/language": {
+            "put": {
+                "tags": [
+                    "basic"
+                ],
+                "summary": "修改语言",
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/metrics": {
+            "get": {
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "basic"
+                ],
+                "summary": "Prometheus 指标",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "metrics api token",
+                        "name": "token",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/ping": {
+            "get": {
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "basic"
+                ],
+                "summary": "服务探活",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/version": {
+            "get": {
+                "tags": [
+                    "basic"
+                ],
+                "summary": "服务版本信息",
+                "responses": {
+                    "200": {
                         "description": "OK",
                         "schema": {
                             "$ref": "#/definitions/serializer.VersionResponse"
@@ -1016,24 +1611,15 @@ const docTemplate = `{
                 }
             }
         },
-        "serializer.CategoryListResponse": {
+        "serializer.CategoryCreateResponse": {
             "type": "object",
             "properties": {
                 "id": {
                     "type": "integer"
-                },
-                "name": {
-                    "type": "string"
-                },
-                "updatedAt": {
-                    "type": "string"
-                },
-                "updater": {
-                    "type": "string"
                 }
             }
         },
-        "serializer.CategoryRetrieveResponse": {
+        "serializer.CategoryResponse": {
             "type": "object",
             "properties": {
                 "createdAt": {
@@ -1042,6 +1628,13 @@ const docTemplate = `{
                 "creator": {
                     "type": "string"
                 },
+                "deletedAt": {
+                    "description": "删除时间 \u0026 删除者（仅回收站中的分类有值）",
+                    "type": "string"
+                },
+                "deletedBy": {
+                    "type": "string"
+                },
                 "id": {
                     "type": "integer"
                 },
@@ -1080,6 +1673,198 @@ const docTemplate = `{
                 }
             }
         },
+        "serializer.CronPreviewResponse": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "description": "自然语言描述（按用户语言国际化）",
+                    "type": "string"
+                },
+                "minAllowedInterval": {
+                    "description": "配置的最小触发间隔（单位：s）",
+                    "type": "integer"
+                },
+                "minInterval": {
+                    "description": "最小触发间隔（单位：s），不会重复触发时为 0",
+                    "type": "number"
+                },
+                "nextRunAt": {
+                    "description": "接下来的触发时间（RFC3339 格式，位于表达式的时区）",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "tooFrequent": {
+                    "description": "是否触发过于频繁（最小触发间隔小于配置值）",
+                    "type": "boolean"
+                }
+            }
+        },
+        "serializer.DeadLetterBulkReplayRequest": {
+            "type": "object",
+            "required": [
+                "items"
+            ],
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "maxItems": 100,
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/serializer.DeadLetterReplayItem"
+                    }
+                }
+            }
+        },
+        "serializer.DeadLetterListResponse": {
+            "type": "object",
+            "properties": {
+                "args": {
+                    "type": "string"
+                },
+                "attempts": {
+                    "type": "integer"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "failedAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "panicked": {
+                    "description": "是否因 panic 失败（记录了调用栈）",
+                    "type": "boolean"
+                },
+                "replayCount": {
+                    "description": "重新下发的次数，及最近一次下发的任务 ID \u0026 时间",
+                    "type": "integer"
+                },
+                "replayedAt": {
+                    "type": "string"
+                },
+                "replayedTaskID": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "taskID": {
+                    "type": "integer"
+                }
+            }
+        },
+        "serializer.DeadLetterReplayItem": {
+            "type": "object",
+            "required": [
+                "id"
+            ],
+            "properties": {
+                "args": {
+                    "description": "修正后的任务参数，为空（或 null）则使用原参数",
+                    "type": "object"
+                },
+                "id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "serializer.DeadLetterReplayRequest": {
+            "type": "object",
+            "properties": {
+                "args": {
+                    "description": "修正后的任务参数，为空（或 null）则使用原参数",
+                    "type": "object"
+                }
+            }
+        },
+        "serializer.DeadLetterReplayResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "description": "下发失败的原因",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "taskID": {
+                    "description": "重新下发的任务 ID，下发失败为 0",
+                    "type": "integer"
+                }
+            }
+        },
+        "serializer.DeadLetterRetrieveResponse": {
+            "type": "object",
+            "properties": {
+                "args": {
+                    "type": "string"
+                },
+                "attemptHistory": {
+                    "description": "每次执行（包括重试）的记录",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/serializer.TaskAttemptResponse"
+                    }
+                },
+                "attempts": {
+                    "type": "integer"
+                },
+                "creator": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "failedAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "queue": {
+                    "type": "string"
+                },
+                "replayCount": {
+                    "description": "重新下发的次数，及最近一次下发的操作人、任务 ID \u0026 时间",
+                    "type": "integer"
+                },
+                "replayedAt": {
+                    "type": "string"
+                },
+                "replayedBy": {
+                    "type": "string"
+                },
+                "replayedTaskID": {
+                    "type": "integer"
+                },
+                "requestID": {
+                    "description": "下发任务的请求 ID 及执行任务的 trace ID",
+                    "type": "string"
+                },
+                "stack": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "taskID": {
+                    "type": "integer"
+                },
+                "traceID": {
+                    "type": "string"
+                }
+            }
+        },
         "serializer.EntryCreateRequest": {
             "type": "object",
             "required": [
@@ -1104,36 +1889,15 @@ const docTemplate = `{
                 }
             }
         },
-        "serializer.EntryListResponse": {
+        "serializer.EntryCreateResponse": {
             "type": "object",
             "properties": {
-                "categoryID": {
-                    "type": "integer"
-                },
-                "categoryName": {
-                    "type": "string"
-                },
-                "desc": {
-                    "type": "string"
-                },
                 "id": {
                     "type": "integer"
-                },
-                "name": {
-                    "type": "string"
-                },
-                "price": {
-                    "type": "number"
-                },
-                "updatedAt": {
-                    "type": "string"
-                },
-                "updater": {
-                    "type": "string"
                 }
             }
         },
-        "serializer.EntryRetrieveResponse": {
+        "serializer.EntryResponse": {
             "type": "object",
             "properties": {
                 "categoryID": {
@@ -1148,6 +1912,13 @@ const docTemplate = `{
                 "creator": {
                     "type": "string"
                 },
+                "deletedAt": {
+                    "description": "删除时间 \u0026 删除者（仅回收站中的条目有值）",
+                    "type": "string"
+                },
+                "deletedBy": {
+                    "type": "string"
+                },
                 "desc": {
                     "type": "string"
                 },
@@ -1211,18 +1982,68 @@ const docTemplate = `{
                 }
             }
         },
+        "serializer.ListObjectsResponse": {
+            "type": "object",
+            "properties": {
+                "isDir": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "sha256": {
+                    "type": "string"
+                },
+                "size": {
+                    "type": "integer"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
         "serializer.PeriodicTaskCreateRequest": {
             "type": "object",
             "properties": {
                 "args": {
-                    "type": "array",
-                    "items": {}
+                    "description": "任务参数，需符合任务声明的参数类型",
+                    "type": "object"
                 },
                 "cron": {
+                    "description": "cron 表达式，支持可选的秒级字段（6 位），与 eta 二选一",
+                    "type": "string"
+                },
+                "endAt": {
+                    "type": "string"
+                },
+                "eta": {
+                    "description": "一次性任务的执行时间（RFC3339 格式），与 cron 二选一",
                     "type": "string"
                 },
+                "misfireLimit": {
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 1
+                },
+                "misfirePolicy": {
+                    "description": "scheduler 停机期间错过触发的处理策略：skip（默认）/ run_once / run_all，及 run_all 最多补跑次数（默认 10）",
+                    "type": "string",
+                    "enum": [
+                        "skip",
+                        "run_once",
+                        "run_all"
+                    ]
+                },
                 "name": {
                     "type": "string"
+                },
+                "startAt": {
+                    "description": "生效时间窗口（RFC3339 格式），为空表示不限制",
+                    "type": "string"
+                },
+                "timezone": {
+                    "description": "IANA 时区（如 Asia/Shanghai），为空表示使用 scheduler 所在机器时区",
+                    "type": "string"
                 }
             }
         },
@@ -1232,6 +2053,9 @@ const docTemplate = `{
                 "args": {
                     "type": "string"
                 },
+                "completedAt": {
+                    "type": "string"
+                },
                 "creator": {
                     "type": "string"
                 },
@@ -1241,11 +2065,78 @@ const docTemplate = `{
                 "enabled": {
                     "type": "boolean"
                 },
+                "endAt": {
+                    "type": "string"
+                },
+                "eta": {
+                    "description": "一次性任务的执行 \u0026 完成时间",
+                    "type": "string"
+                },
                 "id": {
                     "type": "integer"
                 },
+                "lastRunAt": {
+                    "description": "最近一次触发时间 \u0026 结果",
+                    "type": "string"
+                },
+                "lastStatus": {
+                    "type": "string"
+                },
+                "managedKey": {
+                    "description": "声明式定义（periodic_tasks.yaml）中的 key，不为空时只能通过修改定义文件变更",
+                    "type": "string"
+                },
+                "misfireLimit": {
+                    "type": "integer"
+                },
+                "misfirePolicy": {
+                    "description": "错过触发的处理策略",
+                    "type": "string"
+                },
                 "name": {
                     "type": "string"
+                },
+                "nextRunAt": {
+                    "description": "下次触发时间（未启用 / 不再触发则为空）",
+                    "type": "string"
+                },
+                "startAt": {
+                    "type": "string"
+                },
+                "timezone": {
+                    "type": "string"
+                }
+            }
+        },
+        "serializer.PeriodicTaskRunListResponse": {
+            "type": "object",
+            "properties": {
+                "catchUp": {
+                    "description": "是否为 scheduler 启动时对错过触发的补跑",
+                    "type": "boolean"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "manual": {
+                    "description": "是否为手动触发",
+                    "type": "boolean"
+                },
+                "scheduledAt": {
+                    "type": "string"
+                },
+                "startedAt": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "触发结果：已下发的为关联任务的状态，否则为 firing / skipped / failed",
+                    "type": "string"
+                },
+                "taskID": {
+                    "type": "integer"
                 }
             }
         },
@@ -1268,15 +2159,70 @@ const docTemplate = `{
                 }
             }
         },
+        "serializer.TaskAttemptResponse": {
+            "type": "object",
+            "properties": {
+                "attempt": {
+                    "type": "integer"
+                },
+                "duration": {
+                    "type": "number"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "finishedAt": {
+                    "type": "string"
+                },
+                "startedAt": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
         "serializer.TaskCreateRequest": {
             "type": "object",
             "properties": {
                 "args": {
-                    "type": "array",
-                    "items": {}
+                    "description": "任务参数，需符合任务声明的参数类型",
+                    "type": "object"
+                },
+                "dedupKey": {
+                    "description": "去重 key，有效期内以相同 key 下发同名任务时，返回首次下发的任务 ID（如避免重复点击）",
+                    "type": "string",
+                    "maxLength": 128
+                },
+                "dedupTTL": {
+                    "description": "去重有效期（单位：s），为 0 则默认 60s",
+                    "type": "integer",
+                    "maximum": 86400,
+                    "minimum": 0
                 },
                 "name": {
                     "type": "string"
+                },
+                "priority": {
+                    "description": "优先级，同一队列中数值越大越优先执行",
+                    "type": "integer"
+                },
+                "queue": {
+                    "description": "任务队列（需在配置中声明），为空则使用任务默认的队列",
+                    "type": "string"
+                },
+                "timeout": {
+                    "description": "单次执行超时时间（单位：s），为 0 则使用任务默认的超时时间",
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
+        "serializer.TaskCreateResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
                 }
             }
         },
@@ -1295,14 +2241,154 @@ const docTemplate = `{
                 "id": {
                     "type": "integer"
                 },
+                "kind": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "parentID": {
+                    "type": "integer"
+                },
+                "progress": {
+                    "description": "执行进度（百分比）及进度说明",
+                    "type": "number"
+                },
+                "progressMessage": {
+                    "type": "string"
+                },
+                "result": {
+                    "type": "string"
+                },
+                "startedAt": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "serializer.TaskNodeResponse": {
+            "type": "object",
+            "properties": {
+                "args": {
+                    "type": "string"
+                },
+                "children": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/serializer.TaskNodeResponse"
+                    }
+                },
+                "error": {
+                    "type": "string"
+                },
+                "finishedAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "result": {
+                    "type": "string"
+                },
+                "startedAt": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "serializer.TaskProgressEvent": {
+            "type": "object",
+            "properties": {
+                "progress": {
+                    "type": "number"
+                },
+                "progressData": {
+                    "type": "object"
+                },
+                "progressMessage": {
+                    "type": "string"
+                }
+            }
+        },
+        "serializer.TaskRetrieveResponse": {
+            "type": "object",
+            "properties": {
+                "args": {
+                    "type": "string"
+                },
+                "attemptHistory": {
+                    "description": "每次执行（包括重试）的记录",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/serializer.TaskAttemptResponse"
+                    }
+                },
+                "attempts": {
+                    "type": "integer"
+                },
+                "children": {
+                    "description": "工作流节点的子任务树",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/serializer.TaskNodeResponse"
+                    }
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "creator": {
+                    "type": "string"
+                },
+                "duration": {
+                    "type": "number"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "finishedAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "kind": {
+                    "description": "任务类型：task（单个任务）/ chain / group / chord（工作流节点）",
+                    "type": "string"
+                },
                 "name": {
                     "type": "string"
                 },
+                "parentID": {
+                    "type": "integer"
+                },
+                "progress": {
+                    "description": "执行进度（百分比），进度说明及阶段性数据（JSON）",
+                    "type": "number"
+                },
+                "progressData": {
+                    "type": "string"
+                },
+                "progressMessage": {
+                    "type": "string"
+                },
                 "result": {
                     "type": "string"
                 },
                 "startedAt": {
                     "type": "string"
+                },
+                "status": {
+                    "type": "string"
                 }
             }
         },
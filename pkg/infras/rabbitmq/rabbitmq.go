@@ -0,0 +1,134 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - Go 开发框架 (BlueKing - Go Framework) available.
+ * Copyright (C) 2017 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ *	https://opensource.org/licenses/MIT
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package rabbitmq 提供了 RabbitMQ 相关的封装（基于 rabbitmq/amqp091-go）
+// SaaS 开发者查阅该文档以了解使用方法：https://pkg.go.dev/github.com/rabbitmq/amqp091-go
+package rabbitmq
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	amqp "github.com/rabbitmq/amqp091-go"
+
+	"github.com/TencentBlueKing/blueapps-go/pkg/config"
+	log "github.com/TencentBlueKing/blueapps-go/pkg/logging"
+)
+
+var (
+	conn     *amqp.Connection
+	connCfg  *config.RabbitMQConfig
+	connLock sync.Mutex
+	initOnce sync.Once
+)
+
+const (
+	// 尝试连接超时 单位：s
+	dialTimeout = 5
+	// 心跳间隔 单位：s
+	heartbeat = 10
+)
+
+// 生成 TLS 配置（CA 证书必须，客户端证书可选）
+func buildTLSConfig(cfg *config.RabbitMQConfig) (*tls.Config, error) {
+	// 服务器证书
+	caCert, err := os.ReadFile(cfg.TLS.CertCaFile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read ca cert: %s", cfg.TLS.CertCaFile)
+	}
+	pool := x509.NewCertPool()
+	if ok := pool.AppendCertsFromPEM(caCert); !ok {
+		return nil, errors.Errorf("failed to append ca cert: %s", cfg.TLS.CertCaFile)
+	}
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		RootCAs:            pool,
+		InsecureSkipVerify: cfg.TLS.InsecureSkipVerify,
+	}
+
+	// 客户端证书
+	if cfg.TLS.CertFile != "" && cfg.TLS.CertKeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.TLS.CertFile, cfg.TLS.CertKeyFile)
+		if err != nil {
+			return nil, errors.Wrapf(
+				err, "failed to load x509 key pair, cert: %s, key: %s", cfg.TLS.CertFile, cfg.TLS.CertKeyFile,
+			)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
+
+// 建立 RabbitMQ 连接
+func dial(cfg *config.RabbitMQConfig) (*amqp.Connection, error) {
+	amqpCfg := amqp.Config{
+		Heartbeat: time.Duration(heartbeat) * time.Second,
+		Locale:    "en_US",
+		Dial:      amqp.DefaultDial(time.Duration(dialTimeout) * time.Second),
+	}
+	// TLS 配置（DSN scheme 为 amqps 时，amqp 会使用 TLSClientConfig 建立连接）
+	if cfg.TLS.Enabled {
+		tlsConfig, err := buildTLSConfig(cfg)
+		if err != nil {
+			return nil, err
+		}
+		amqpCfg.TLSClientConfig = tlsConfig
+	}
+	return amqp.DialConfig(cfg.DSN(), amqpCfg)
+}
+
+// InitRabbitMQClient init rabbitmq connection with config.RabbitMQConfig
+func InitRabbitMQClient(ctx context.Context, cfg *config.RabbitMQConfig) {
+	if cfg == nil {
+		log.Fatal("rabbitmq config is required when init rabbitmq client")
+	}
+
+	initOnce.Do(func() {
+		var err error
+		if conn, err = dial(cfg); err != nil {
+			log.Fatalf("rabbitmq connect error: %s", err.Error())
+		}
+		connCfg = cfg
+		log.Infof(ctx, "rabbitmq: %s:%d/%s connected", cfg.Host, cfg.Port, cfg.Vhost)
+	})
+}
+
+// Client 获取 rabbitmq 连接，若连接已断开则尝试重新建立
+func Client() (*amqp.Connection, error) {
+	connLock.Lock()
+	defer connLock.Unlock()
+
+	if conn == nil {
+		log.Fatal("rabbitmq client not init")
+	}
+	if !conn.IsClosed() {
+		return conn, nil
+	}
+
+	newConn, err := dial(connCfg)
+	if err != nil {
+		return nil, errors.Wrap(err, "rabbitmq reconnect")
+	}
+	conn = newConn
+	return conn, nil
+}
@@ -0,0 +1,59 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - Go 开发框架 (BlueKing - Go Framework) available.
+ * Copyright (C) 2017 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ *	https://opensource.org/licenses/MIT
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package migration stores all database migrations
+package migration
+
+import (
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+
+	"github.com/TencentBlueKing/blueapps-go/pkg/infras/database"
+	"github.com/TencentBlueKing/blueapps-go/pkg/model"
+)
+
+func init() {
+	// Do Not Edit Migration ID!
+	migrationID := "20261017_101502"
+
+	database.RegisterMigration(&gormigrate.Migration{
+		ID: migrationID,
+		Migrate: func(tx *gorm.DB) error {
+			logApplying(migrationID)
+
+			// 新增任务状态，错误信息，执行次数，结束时间字段
+			if err := tx.AutoMigrate(&model.Task{}); err != nil {
+				return err
+			}
+			// 存量任务均为执行完成后才写入结果的，视为执行成功
+			return tx.Model(&model.Task{}).
+				Where("result IS NOT NULL").
+				Updates(map[string]any{"status": model.TaskStatusSucceeded, "attempts": 1}).Error
+		},
+		Rollback: func(tx *gorm.DB) error {
+			logRollingBack(migrationID)
+
+			for _, column := range []string{"Status", "Error", "Attempts", "FinishedAt"} {
+				if err := tx.Migrator().DropColumn(&model.Task{}, column); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	})
+}
@@ -0,0 +1,86 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - Go 开发框架 (BlueKing - Go Framework) available.
+ * Copyright (C) 2017 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ *	https://opensource.org/licenses/MIT
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package migration stores all database migrations
+package migration
+
+import (
+	"encoding/json"
+
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+
+	"github.com/TencentBlueKing/blueapps-go/pkg/infras/database"
+	"github.com/TencentBlueKing/blueapps-go/pkg/model"
+)
+
+func init() {
+	// Do Not Edit Migration ID!
+	migrationID := "20261017_160245"
+
+	database.RegisterMigration(&gormigrate.Migration{
+		ID: migrationID,
+		Migrate: func(tx *gorm.DB) error {
+			logApplying(migrationID)
+
+			// CalcFib 任务参数由位置参数（[n]）调整为结构体（{"n": n}），需要转换存量周期任务的参数
+			return convertCalcFibArgs(tx, func(args json.RawMessage) (any, bool) {
+				var positional []int
+				if err := json.Unmarshal(args, &positional); err != nil || len(positional) != 1 {
+					return nil, false
+				}
+				return map[string]int{"n": positional[0]}, true
+			})
+		},
+		Rollback: func(tx *gorm.DB) error {
+			logRollingBack(migrationID)
+
+			return convertCalcFibArgs(tx, func(args json.RawMessage) (any, bool) {
+				var keyword struct {
+					N *int `json:"n"`
+				}
+				if err := json.Unmarshal(args, &keyword); err != nil || keyword.N == nil {
+					return nil, false
+				}
+				return []int{*keyword.N}, true
+			})
+		},
+	})
+}
+
+// 转换 CalcFib 周期任务的参数，convert 返回 false 表示无需转换
+func convertCalcFibArgs(tx *gorm.DB, convert func(args json.RawMessage) (any, bool)) error {
+	var periodicTasks []model.PeriodicTask
+	if err := tx.Where("name = ?", "CalcFib").Find(&periodicTasks).Error; err != nil {
+		return err
+	}
+	for _, task := range periodicTasks {
+		args, ok := convert(json.RawMessage(task.Args))
+		if !ok {
+			continue
+		}
+		rawArgs, err := json.Marshal(args)
+		if err != nil {
+			return err
+		}
+		if err = tx.Model(&task).Update("args", rawArgs).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - Go 开发框架 (BlueKing - Go Framework) available.
+ * Copyright (C) 2017 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ *	https://opensource.org/licenses/MIT
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package migration stores all database migrations
+package migration
+
+import (
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+
+	"github.com/TencentBlueKing/blueapps-go/pkg/infras/database"
+	"github.com/TencentBlueKing/blueapps-go/pkg/model"
+)
+
+func init() {
+	// Do Not Edit Migration ID!
+	migrationID := "20261023_103027"
+
+	database.RegisterMigration(&gormigrate.Migration{
+		ID: migrationID,
+		Migrate: func(tx *gorm.DB) error {
+			logApplying(migrationID)
+
+			// 触发记录新增是否为手动触发，手动触发与 scheduler 的触发分别去重（唯一索引加入 manual 列）
+			if err := tx.AutoMigrate(&model.PeriodicTaskRun{}); err != nil {
+				return err
+			}
+			if tx.Migrator().HasIndex(&model.PeriodicTaskRun{}, "idx_periodic_task_scheduled") {
+				return tx.Migrator().DropIndex(&model.PeriodicTaskRun{}, "idx_periodic_task_scheduled")
+			}
+			return nil
+		},
+		Rollback: func(tx *gorm.DB) error {
+			logRollingBack(migrationID)
+
+			if err := tx.Migrator().DropIndex(&model.PeriodicTaskRun{}, "idx_periodic_task_firing"); err != nil {
+				return err
+			}
+			if err := tx.Migrator().DropColumn(&model.PeriodicTaskRun{}, "Manual"); err != nil {
+				return err
+			}
+			return tx.Exec(
+				"CREATE UNIQUE INDEX idx_periodic_task_scheduled ON periodic_task_runs (periodic_task_id, scheduled_at)",
+			).Error
+		},
+	})
+}
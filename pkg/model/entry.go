@@ -21,6 +21,7 @@ package model
 // Entry 条目
 type Entry struct {
 	BaseModel
+	SoftDeleteModel
 	CategoryID int64    `json:"categoryID" gorm:"not null"`
 	Category   Category `json:"category" gorm:"foreignKey:CategoryID"`
 
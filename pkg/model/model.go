@@ -19,7 +19,11 @@
 // Package model 用于存放数据库模型
 package model
 
-import "time"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 // BaseModel 基础模型
 type BaseModel struct {
@@ -28,3 +32,34 @@ type BaseModel struct {
 	CreatedAt time.Time `json:"createdAt"`
 	UpdatedAt time.Time `json:"updatedAt"`
 }
+
+// SetCreator 设置创建者（同时为更新者）
+func (m *BaseModel) SetCreator(user string) {
+	m.Creator, m.Updater = user, user
+}
+
+// SetUpdater 设置更新者
+func (m *BaseModel) SetUpdater(user string) {
+	m.Updater = user
+}
+
+// SoftDeleteModel 软删除模型（可选，与 BaseModel 一同内嵌）：删除时仅记录删除时间 & 删除者，
+// 查询时默认排除已删除的记录（查询回收站中的记录需使用 Unscoped），超过保留期限后由 PurgeSoftDeleted 任务清理
+// 注：内嵌后需在 SoftDeleteModels 中声明该模型
+type SoftDeleteModel struct {
+	DeletedAt gorm.DeletedAt `json:"deletedAt" gorm:"index"`
+	DeletedBy string         `json:"deletedBy" gorm:"type:varchar(32);null"`
+}
+
+// IsDeleted 是否已被（软）删除
+func (m *SoftDeleteModel) IsDeleted() bool {
+	return m.DeletedAt.Valid
+}
+
+// Deletion 删除时间 & 删除者，同一次删除的记录（如级联删除的子资源）相同，恢复时据此一并恢复
+func (m *SoftDeleteModel) Deletion() (time.Time, string) {
+	return m.DeletedAt.Time, m.DeletedBy
+}
+
+// SoftDeleteModels 支持软删除的模型，PurgeSoftDeleted 任务会按顺序清理其中超过保留期限的已删除记录
+var SoftDeleteModels = []any{&Entry{}, &Category{}}
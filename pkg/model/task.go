@@ -24,23 +24,183 @@ import (
 	"gorm.io/datatypes"
 )
 
-// Task 后台任务
+// TaskStatus 后台任务状态
+type TaskStatus string
+
+const (
+	// TaskStatusPending 已下发，等待执行
+	TaskStatusPending TaskStatus = "pending"
+	// TaskStatusRunning 执行中
+	TaskStatusRunning TaskStatus = "running"
+	// TaskStatusSucceeded 执行成功
+	TaskStatusSucceeded TaskStatus = "succeeded"
+	// TaskStatusFailed 执行失败
+	TaskStatusFailed TaskStatus = "failed"
+	// TaskStatusCancelled 已取消
+	TaskStatusCancelled TaskStatus = "cancelled"
+	// TaskStatusTimeout 执行超时
+	TaskStatusTimeout TaskStatus = "timeout"
+)
+
+// IsFinished 是否为终止状态
+func (s TaskStatus) IsFinished() bool {
+	return s == TaskStatusSucceeded || s == TaskStatusFailed || s == TaskStatusCancelled || s == TaskStatusTimeout
+}
+
+// TaskKind 任务类型：单个任务，或由多个子任务组成的工作流节点
+type TaskKind string
+
+const (
+	// TaskKindTask 单个任务
+	TaskKindTask TaskKind = "task"
+	// TaskKindChain 顺序执行子任务，上一个子任务的结果作为下一个子任务的参数
+	TaskKindChain TaskKind = "chain"
+	// TaskKindGroup 并行执行子任务，结果为各子任务结果组成的列表
+	TaskKindGroup TaskKind = "group"
+	// TaskKindChord 并行执行 group 中的子任务，全部成功后以结果列表为参数执行回调任务
+	TaskKindChord TaskKind = "chord"
+)
+
+// Task 后台任务，由异步任务框架在下发时创建，并随任务执行更新状态
+// 工作流（chain / group / chord）的各节点也以任务记录保存，通过 RootID / ParentID 组成任务树
 type Task struct {
 	BaseModel
-	ID        int64          `json:"id" gorm:"primaryKey"`
-	Name      string         `json:"name" gorm:"type:varchar(128);not null"`
-	Args      datatypes.JSON `json:"args" gorm:"type:json"`
-	Result    datatypes.JSON `json:"result" gorm:"type:json"`
-	StartedAt time.Time      `json:"startedAt" gorm:"type:datetime;default:null"`
-	Duration  time.Duration  `json:"duration" gorm:"type:bigint;default:null"`
+	ID   int64    `json:"id" gorm:"primaryKey"`
+	Kind TaskKind `json:"kind" gorm:"type:varchar(16);not null;default:task"`
+	// 所属工作流的根任务 ID，不属于工作流的任务为 0
+	RootID int64 `json:"rootID" gorm:"not null;default:0;index"`
+	// 父节点任务 ID 及在父节点中的位置（从 0 开始）
+	ParentID   int64          `json:"parentID" gorm:"not null;default:0;index"`
+	Position   int            `json:"position" gorm:"not null;default:0"`
+	Name       string         `json:"name" gorm:"type:varchar(128);not null"`
+	Args       datatypes.JSON `json:"args" gorm:"type:json"`
+	Result     datatypes.JSON `json:"result" gorm:"type:json"`
+	Status     TaskStatus     `json:"status" gorm:"type:varchar(32);not null;default:pending;index"`
+	Error      string         `json:"error" gorm:"type:text;null"`
+	Attempts   int            `json:"attempts" gorm:"not null;default:0"`
+	StartedAt  time.Time      `json:"startedAt" gorm:"type:datetime;default:null"`
+	FinishedAt time.Time      `json:"finishedAt" gorm:"type:datetime;default:null"`
+	Duration   time.Duration  `json:"duration" gorm:"type:bigint;default:null"`
+	// 执行进度（由任务函数通过 progress.Report 上报）：百分比（0 - 100），进度说明及阶段性数据
+	Progress        float64        `json:"progress" gorm:"not null;default:0"`
+	ProgressMessage string         `json:"progressMessage" gorm:"type:varchar(255);not null;default:''"`
+	ProgressData    datatypes.JSON `json:"progressData" gorm:"type:json"`
+}
+
+// TaskAttempt 后台任务的单次执行记录，任务每次执行（包括重试）都会记录一条
+type TaskAttempt struct {
+	ID         int64         `json:"id" gorm:"primaryKey"`
+	TaskID     int64         `json:"taskID" gorm:"not null;index"`
+	Attempt    int           `json:"attempt" gorm:"not null"`
+	Status     TaskStatus    `json:"status" gorm:"type:varchar(32);not null"`
+	Error      string        `json:"error" gorm:"type:text;null"`
+	StartedAt  time.Time     `json:"startedAt" gorm:"type:datetime;not null"`
+	FinishedAt time.Time     `json:"finishedAt" gorm:"type:datetime;default:null"`
+	Duration   time.Duration `json:"duration" gorm:"type:bigint;default:null"`
 }
 
-// PeriodicTask 周期任务
+// PeriodicTask 周期任务（设置 ETA 时为一次性任务）
 type PeriodicTask struct {
 	BaseModel
-	ID      int64          `json:"id" gorm:"primaryKey"`
-	Cron    string         `json:"cron" gorm:"type:varchar(32);not null"`
-	Name    string         `json:"name" gorm:"type:varchar(128);not null"`
-	Args    datatypes.JSON `json:"args" gorm:"type:json"`
-	Enabled bool           `json:"enabled" gorm:"not null;default:true"`
+	ID int64 `json:"id" gorm:"primaryKey"`
+	// cron 表达式，支持可选的秒级字段（6 位），一次性任务为空
+	Cron string `json:"cron" gorm:"type:varchar(64);not null;default:''"`
+	// IANA 时区（如 Asia/Shanghai），为空表示使用 scheduler 所在机器时区
+	Timezone string `json:"timezone" gorm:"type:varchar(64);not null;default:''"`
+	// 生效时间窗口，为空表示不限制
+	StartAt time.Time `json:"startAt" gorm:"type:datetime;default:null"`
+	EndAt   time.Time `json:"endAt" gorm:"type:datetime;default:null"`
+	// 一次性任务的执行时间，执行后会记录完成时间并自动禁用
+	ETA         time.Time `json:"eta" gorm:"column:eta;type:datetime;default:null"`
+	CompletedAt time.Time `json:"completedAt" gorm:"type:datetime;default:null"`
+	// scheduler 停机期间错过触发的处理策略，及 run_all 策略下最多补跑的次数
+	MisfirePolicy MisfirePolicy  `json:"misfirePolicy" gorm:"type:varchar(16);not null;default:skip"`
+	MisfireLimit  int            `json:"misfireLimit" gorm:"not null;default:10"`
+	Name          string         `json:"name" gorm:"type:varchar(128);not null"`
+	Args          datatypes.JSON `json:"args" gorm:"type:json"`
+	Enabled       bool           `json:"enabled" gorm:"not null;default:true"`
+	// 声明式定义（periodic_tasks.yaml）中的 key，为空表示通过 API / 页面创建；
+	// 由声明式定义管理的周期任务只能通过修改定义文件变更，API / 页面的修改会被拒绝
+	ManagedKey string `json:"managedKey" gorm:"type:varchar(128);not null;default:'';index"`
+}
+
+// Managed 是否由声明式定义（periodic_tasks.yaml）管理
+func (t *PeriodicTask) Managed() bool {
+	return t.ManagedKey != ""
+}
+
+// MisfirePolicy 周期任务错过触发（如 scheduler 停机 / 发布期间）时的处理策略
+type MisfirePolicy string
+
+const (
+	// MisfirePolicySkip 跳过错过的触发
+	MisfirePolicySkip MisfirePolicy = "skip"
+	// MisfirePolicyRunOnce 无论错过多少次，仅补跑一次
+	MisfirePolicyRunOnce MisfirePolicy = "run_once"
+	// MisfirePolicyRunAll 补跑所有错过的触发（最多 MisfireLimit 次）
+	MisfirePolicyRunAll MisfirePolicy = "run_all"
+)
+
+// PeriodicTaskRunStatus 周期任务触发结果
+type PeriodicTaskRunStatus string
+
+const (
+	// PeriodicTaskRunStatusFiring 下发中
+	PeriodicTaskRunStatusFiring PeriodicTaskRunStatus = "firing"
+	// PeriodicTaskRunStatusApplied 已下发异步任务（执行结果以关联的 Task 为准）
+	PeriodicTaskRunStatusApplied PeriodicTaskRunStatus = "applied"
+	// PeriodicTaskRunStatusSkipped 已跳过（如周期任务已被禁用）
+	PeriodicTaskRunStatusSkipped PeriodicTaskRunStatus = "skipped"
+	// PeriodicTaskRunStatusFailed 下发异步任务失败
+	PeriodicTaskRunStatusFailed PeriodicTaskRunStatus = "failed"
+)
+
+// PeriodicTaskRun 周期任务的单次触发记录，(PeriodicTaskID, ScheduledAt, Manual) 唯一，用于多个 scheduler 副本间的触发去重
+type PeriodicTaskRun struct {
+	ID             int64                 `json:"id" gorm:"primaryKey"`
+	PeriodicTaskID int64                 `json:"periodicTaskID" gorm:"not null;uniqueIndex:idx_periodic_task_firing"`
+	ScheduledAt    time.Time             `json:"scheduledAt" gorm:"type:datetime;not null;uniqueIndex:idx_periodic_task_firing"`
+	StartedAt      time.Time             `json:"startedAt" gorm:"type:datetime;default:null"`
+	TaskID         int64                 `json:"taskID" gorm:"default:null;index"`
+	Status         PeriodicTaskRunStatus `json:"status" gorm:"type:varchar(32);not null;default:applied"`
+	Error          string                `json:"error" gorm:"type:text;null"`
+	// 是否为 scheduler 启动时对错过触发的补跑
+	CatchUp bool `json:"catchUp" gorm:"not null;default:false"`
+	// 是否为手动触发（TriggerPeriodicTask），与 scheduler 的触发分别去重，且不计入错过触发的补跑
+	Manual    bool      `json:"manual" gorm:"not null;default:false;uniqueIndex:idx_periodic_task_firing"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// TaskLock 任务锁（未启用 Redis 时使用），用于下发任务时的去重（dedup key）及任务执行期间的唯一性约束
+// 锁过期后可被重新获取，持有者为最近一次获取锁的任务
+type TaskLock struct {
+	Key       string    `json:"key" gorm:"column:lock_key;type:varchar(255);primaryKey"`
+	TaskID    int64     `json:"taskID" gorm:"not null"`
+	ExpiresAt time.Time `json:"expiresAt" gorm:"type:datetime;not null;index"`
+}
+
+// DeadLetter 死信：最终执行失败（含超时）或投递失败的任务快照，用于排查问题及修正参数后重新下发
+type DeadLetter struct {
+	BaseModel
+	ID     int64          `json:"id" gorm:"primaryKey"`
+	TaskID int64          `json:"taskID" gorm:"not null;index"`
+	Name   string         `json:"name" gorm:"type:varchar(128);not null;index"`
+	Args   datatypes.JSON `json:"args" gorm:"type:json"`
+	Queue  string         `json:"queue" gorm:"type:varchar(64);not null;default:''"`
+	// 任务最终状态（failed / timeout）
+	Status TaskStatus `json:"status" gorm:"type:varchar(32);not null"`
+	Error  string     `json:"error" gorm:"type:text;null"`
+	// 任务函数 panic 时的调用栈
+	Stack    string `json:"stack" gorm:"type:text;null"`
+	Attempts int    `json:"attempts" gorm:"not null;default:0"`
+	// 每次执行的记录（[]TaskAttempt）
+	AttemptHistory datatypes.JSON `json:"attemptHistory" gorm:"type:json"`
+	// 下发任务的请求 ID 及执行任务的 trace ID，用于串联日志 & 调用链
+	RequestID string    `json:"requestID" gorm:"type:varchar(64);not null;default:''"`
+	TraceID   string    `json:"traceID" gorm:"type:varchar(32);not null;default:''"`
+	FailedAt  time.Time `json:"failedAt" gorm:"type:datetime;not null"`
+	// 重新下发的次数，及最近一次重新下发的任务 ID & 时间（操作人记录为 Updater）
+	ReplayCount    int       `json:"replayCount" gorm:"not null;default:0"`
+	ReplayedTaskID int64     `json:"replayedTaskID" gorm:"not null;default:0"`
+	ReplayedAt     time.Time `json:"replayedAt" gorm:"type:datetime;default:null"`
 }
@@ -0,0 +1,253 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - Go 开发框架 (BlueKing - Go Framework) available.
+ * Copyright (C) 2017 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ *	https://opensource.org/licenses/MIT
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package ginx
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	"github.com/samber/lo"
+	"github.com/spf13/cast"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/TencentBlueKing/blueapps-go/pkg/config"
+	"github.com/TencentBlueKing/blueapps-go/pkg/i18n"
+)
+
+// 游标分页参数
+const cursorParam = "cursor"
+
+// 游标方向
+const (
+	cursorNext = "next"
+	cursorPrev = "prev"
+)
+
+// 游标签名长度（HMAC-SHA256 截断）
+const cursorSignatureLength = 16
+
+// ErrCursorSecretNotConfigured 未配置游标签名密钥（应用密钥），无法签发 / 校验游标
+var ErrCursorSecretNotConfigured = errors.New("cursor secret (app secret) not configured")
+
+// 游标内容，编码并签名后作为不透明的字符串返回给客户端
+type cursorPayload struct {
+	// 方向：next（当前页之后）/ prev（当前页之前）
+	Direction string `json:"d"`
+	// 排序键（列 & 方向），避免更换排序后沿用旧游标
+	Keys string `json:"k"`
+	// 当前页边界行的排序键值
+	Values []string `json:"v"`
+}
+
+// CursorPaginator 游标（keyset）分页：根据上一页边界行的排序键值定位，无需 COUNT 及 OFFSET 扫描
+//
+// 使用方式：tx.Scopes(paginator.Scope).Find(&rows)，再通过 CursorPaginate 获取当页数据及前后页游标
+type CursorPaginator struct {
+	keys   []SortKey
+	limit  int
+	cursor *cursorPayload
+	// 解析后的排序键值
+	values []any
+	// 游标签名密钥
+	secret []byte
+}
+
+// UseCursorPagination 请求是否使用游标分页（携带 cursor 参数，第一页为空值）
+// 支持游标分页的 API 可据此选择分页方式，未携带 cursor 参数的请求仍使用 page / limit 分页
+func UseCursorPagination(c *gin.Context) bool {
+	_, ok := c.GetQuery(cursorParam)
+	return ok
+}
+
+// NewCursorPaginator 根据 cursor & limit 参数创建游标分页，未配置应用密钥时返回 ErrCursorSecretNotConfigured
+// 注：keys 需能唯一确定行的顺序（通常以主键结尾）；浮点数列（如 MySQL FLOAT）的值无法在游标中精确表示，
+// 以其作为排序键会导致翻页时重复 / 遗漏边界上的行，因此不支持
+func NewCursorPaginator(c *gin.Context, keys []SortKey) (*CursorPaginator, error) {
+	ctx := c.Request.Context()
+	for _, key := range keys {
+		if key.Type == FieldTypeFloat {
+			return nil, errors.Errorf(i18n.T(ctx, "cursor pagination does not support sorting by float field %s"), key.Column)
+		}
+	}
+
+	// 禁止使用空密钥签名，否则客户端可以伪造游标
+	secret := cursorSecret()
+	if len(secret) == 0 {
+		return nil, ErrCursorSecretNotConfigured
+	}
+
+	p := &CursorPaginator{keys: keys, limit: GetLimit(c), secret: secret}
+	raw := c.Query(cursorParam)
+	if raw == "" {
+		return p, nil
+	}
+
+	invalidErr := errors.Errorf(i18n.T(ctx, "invalid cursor: %s"), raw)
+	payload, err := decodeCursor(raw, secret)
+	if err != nil || payload.Keys != sortKeysSignature(keys) || len(payload.Values) != len(keys) ||
+		!lo.Contains([]string{cursorNext, cursorPrev}, payload.Direction) {
+		return nil, invalidErr
+	}
+	for i, key := range keys {
+		value, err := QueryField{Type: key.Type}.parseValue(payload.Values[i])
+		if err != nil {
+			return nil, invalidErr
+		}
+		p.values = append(p.values, value)
+	}
+	p.cursor = payload
+	return p, nil
+}
+
+// Scope 应用游标条件、排序及数量限制（多查询一条，用于判断是否还有更多数据）
+func (p *CursorPaginator) Scope(tx *gorm.DB) *gorm.DB {
+	// 向前翻页时按相反的顺序查询，再由 CursorPaginate 恢复顺序
+	reverse := p.reverse()
+	if p.cursor != nil {
+		conditions := []clause.Expression{}
+		// (k1 > v1) OR (k1 = v1 AND k2 > v2) OR ...（降序时为 <）
+		for i, key := range p.keys {
+			exprs := []clause.Expression{}
+			for j := range i {
+				exprs = append(exprs, clause.Eq{Column: clause.Column{Name: p.keys[j].Column}, Value: p.values[j]})
+			}
+			column := clause.Column{Name: key.Column}
+			if key.Desc != reverse {
+				exprs = append(exprs, clause.Lt{Column: column, Value: p.values[i]})
+			} else {
+				exprs = append(exprs, clause.Gt{Column: column, Value: p.values[i]})
+			}
+			conditions = append(conditions, clause.And(exprs...))
+		}
+		tx = tx.Where(clause.Or(conditions...))
+	}
+	for _, key := range p.keys {
+		tx = tx.Order(clause.OrderByColumn{Column: clause.Column{Name: key.Column}, Desc: key.Desc != reverse})
+	}
+	return tx.Limit(p.limit + 1)
+}
+
+// 是否向前翻页
+func (p *CursorPaginator) reverse() bool {
+	return p.cursor != nil && p.cursor.Direction == cursorPrev
+}
+
+// 生成游标
+func (p *CursorPaginator) encode(direction string, values []any) string {
+	return encodeCursor(&cursorPayload{
+		Direction: direction,
+		Keys:      sortKeysSignature(p.keys),
+		Values:    lo.Map(values, func(v any, _ int) string { return formatCursorValue(v) }),
+	}, p.secret)
+}
+
+// CursorPaginate 根据 Scope 查询到的数据生成当页数据及前后页游标（为空表示没有更多数据）
+// keyValues 返回一行数据的排序键值，需与 keys 的顺序一致
+func CursorPaginate[T any](p *CursorPaginator, rows []T, keyValues func(T) []any) (page []T, next, prev string) {
+	hasMore := len(rows) > p.limit
+	if hasMore {
+		rows = rows[:p.limit]
+	}
+	if p.reverse() {
+		slices.Reverse(rows)
+	}
+	if len(rows) == 0 {
+		return rows, "", ""
+	}
+
+	first, last := rows[0], rows[len(rows)-1]
+	switch {
+	// 第一页
+	case p.cursor == nil:
+		next = lo.Ternary(hasMore, p.encode(cursorNext, keyValues(last)), "")
+	case p.cursor.Direction == cursorNext:
+		next = lo.Ternary(hasMore, p.encode(cursorNext, keyValues(last)), "")
+		prev = p.encode(cursorPrev, keyValues(first))
+	default:
+		next = p.encode(cursorNext, keyValues(last))
+		prev = lo.Ternary(hasMore, p.encode(cursorPrev, keyValues(first)), "")
+	}
+	return rows, next, prev
+}
+
+// 排序键签名，如 price:desc,id:asc
+func sortKeysSignature(keys []SortKey) string {
+	return strings.Join(lo.Map(keys, func(key SortKey, _ int) string {
+		return key.Column + lo.Ternary(key.Desc, ":desc", ":asc")
+	}), ",")
+}
+
+// 将排序键值格式化为字符串（时间保留纳秒精度）
+func formatCursorValue(v any) string {
+	if t, ok := v.(time.Time); ok {
+		return t.Format(time.RFC3339Nano)
+	}
+	return cast.ToString(v)
+}
+
+// 编码 & 签名游标：base64(payload).base64(signature)
+func encodeCursor(payload *cursorPayload, secret []byte) string {
+	raw, _ := json.Marshal(payload)
+	data := base64.RawURLEncoding.EncodeToString(raw)
+	return data + "." + base64.RawURLEncoding.EncodeToString(signCursor(data, secret))
+}
+
+// 校验签名 & 解码游标
+func decodeCursor(cursor string, secret []byte) (*cursorPayload, error) {
+	data, signature, ok := strings.Cut(cursor, ".")
+	if !ok {
+		return nil, errors.New("malformed cursor")
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(sig, signCursor(data, secret)) {
+		return nil, errors.New("cursor signature mismatch")
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(data)
+	if err != nil {
+		return nil, err
+	}
+	var payload cursorPayload
+	if err = json.Unmarshal(raw, &payload); err != nil {
+		return nil, errors.Wrap(err, "unmarshal cursor")
+	}
+	return &payload, nil
+}
+
+// 使用应用密钥签名游标，避免客户端伪造游标
+func signCursor(data string, secret []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)[:cursorSignatureLength]
+}
+
+// 游标签名密钥（应用密钥），未配置时为空
+func cursorSecret() []byte {
+	if config.G == nil {
+		return nil
+	}
+	return []byte(config.G.Platform.AppSecret)
+}
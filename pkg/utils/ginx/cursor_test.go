@@ -0,0 +1,151 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - Go 开发框架 (BlueKing - Go Framework) available.
+ * Copyright (C) 2017 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ *	https://opensource.org/licenses/MIT
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package ginx_test
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+
+	"github.com/TencentBlueKing/blueapps-go/pkg/config"
+	"github.com/TencentBlueKing/blueapps-go/pkg/utils/ginx"
+)
+
+var entrySortKeys = []ginx.SortKey{
+	{Column: "updated_at", Type: ginx.FieldTypeTime, Desc: true},
+	{Column: "id", Type: ginx.FieldTypeInt},
+}
+
+func entryKeyValues(e entry) []any {
+	return []any{e.UpdatedAt, e.ID}
+}
+
+// 设置游标签名密钥（应用密钥），测试结束后恢复
+func setCursorSecret(t *testing.T, secret string) {
+	g := config.G
+	config.G = &config.Config{Platform: config.PlatformConfig{AppSecret: secret}}
+	t.Cleanup(func() { config.G = g })
+}
+
+// 生成游标分页查询的 SQL（不执行）
+func cursorSQL(t *testing.T, p *ginx.CursorPaginator) string {
+	return dryRunDB(t).ToSQL(func(tx *gorm.DB) *gorm.DB {
+		return tx.Model(&entry{}).Where("category_id = ?", 1).Scopes(p.Scope).Find(&[]entry{})
+	})
+}
+
+func TestCursorPaginate(t *testing.T) {
+	setCursorSecret(t, "secret")
+	updatedAt := time.Date(2026, 10, 1, 8, 0, 0, 123000000, time.UTC)
+	entries := []entry{}
+	for i := range 6 {
+		entries = append(entries, entry{ID: int64(i + 1), UpdatedAt: updatedAt.Add(-time.Duration(i/2) * time.Hour)})
+	}
+
+	// 第一页：多查询一条用于判断是否有下一页
+	p, err := ginx.NewCursorPaginator(newQueryContext("limit=5"), entrySortKeys)
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM `entries` WHERE category_id = 1 "+
+		"ORDER BY `updated_at` DESC,`id` LIMIT 6", cursorSQL(t, p))
+
+	page, next, prev := ginx.CursorPaginate(p, entries, entryKeyValues)
+	assert.Equal(t, entries[:5], page)
+	assert.NotEmpty(t, next)
+	assert.Empty(t, prev)
+
+	// 下一页：从上一页最后一行之后开始
+	p, err = ginx.NewCursorPaginator(newQueryContext("limit=5&cursor="+url.QueryEscape(next)), entrySortKeys)
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM `entries` WHERE category_id = 1 AND "+
+		"(`updated_at` < '2026-10-01 06:00:00.123' OR (`updated_at` = '2026-10-01 06:00:00.123' AND `id` > 5)) "+
+		"ORDER BY `updated_at` DESC,`id` LIMIT 6", cursorSQL(t, p))
+
+	page, next, prev = ginx.CursorPaginate(p, entries[5:], entryKeyValues)
+	assert.Equal(t, entries[5:], page)
+	assert.Empty(t, next)
+	assert.NotEmpty(t, prev)
+
+	// 上一页：按相反的顺序查询，返回时恢复顺序
+	p, err = ginx.NewCursorPaginator(newQueryContext("limit=5&cursor="+url.QueryEscape(prev)), entrySortKeys)
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM `entries` WHERE category_id = 1 AND "+
+		"(`updated_at` > '2026-10-01 06:00:00.123' OR (`updated_at` = '2026-10-01 06:00:00.123' AND `id` < 6)) "+
+		"ORDER BY `updated_at`,`id` DESC LIMIT 6", cursorSQL(t, p))
+
+	reversed := []entry{entries[4], entries[3], entries[2], entries[1], entries[0]}
+	page, next, prev = ginx.CursorPaginate(p, reversed, entryKeyValues)
+	assert.Equal(t, entries[:5], page)
+	assert.NotEmpty(t, next)
+	assert.Empty(t, prev)
+
+	// 排序变化 / 篡改的游标不可用
+	_, err = ginx.NewCursorPaginator(newQueryContext("cursor="+url.QueryEscape(next)), entrySortKeys[1:])
+	assert.Error(t, err)
+	_, err = ginx.NewCursorPaginator(newQueryContext("cursor="+url.QueryEscape(next+"x")), entrySortKeys)
+	assert.Error(t, err)
+	_, err = ginx.NewCursorPaginator(newQueryContext("cursor=abc"), entrySortKeys)
+	assert.Error(t, err)
+}
+
+func TestUseCursorPagination(t *testing.T) {
+	assert.True(t, ginx.UseCursorPagination(newQueryContext("cursor=")))
+	assert.False(t, ginx.UseCursorPagination(newQueryContext("page=1")))
+}
+
+func TestCursorPaginatorFloatKey(t *testing.T) {
+	setCursorSecret(t, "secret")
+	// 价格为 MySQL FLOAT，相同 / 无法精确表示的价格（如 19.99）格式化到游标后再解析，与 DB 中存储的值不相等，
+	// 会导致边界上的行在降序翻页时重复（limit=1 时死循环）、升序翻页时被遗漏，因此不支持以浮点数列作为游标排序键
+	keys := []ginx.SortKey{{Column: "price", Type: ginx.FieldTypeFloat, Desc: true}, {Column: "id", Type: ginx.FieldTypeInt}}
+	for _, query := range []string{"limit=1&cursor=", "limit=1&cursor=abc"} {
+		_, err := ginx.NewCursorPaginator(newQueryContext(query), keys)
+		assert.ErrorContains(t, err, "price", query)
+	}
+
+	// 非首个排序键同样不支持
+	_, err := ginx.NewCursorPaginator(newQueryContext("cursor="), []ginx.SortKey{
+		{Column: "updated_at", Type: ginx.FieldTypeTime},
+		{Column: "price", Type: ginx.FieldTypeFloat},
+		{Column: "id", Type: ginx.FieldTypeInt},
+	})
+	assert.Error(t, err)
+}
+
+func TestCursorPaginatorSecret(t *testing.T) {
+	// 未配置应用密钥时，拒绝签发 / 校验游标（空密钥签名的游标可被伪造）
+	setCursorSecret(t, "")
+	_, err := ginx.NewCursorPaginator(newQueryContext("cursor="), entrySortKeys)
+	assert.ErrorIs(t, err, ginx.ErrCursorSecretNotConfigured)
+
+	// 其他密钥签名的游标不可用
+	setCursorSecret(t, "secret")
+	p, err := ginx.NewCursorPaginator(newQueryContext("limit=5&cursor="), entrySortKeys)
+	assert.NoError(t, err)
+	_, next, _ := ginx.CursorPaginate(p, make([]entry, 6), entryKeyValues)
+	assert.NotEmpty(t, next)
+	_, err = ginx.NewCursorPaginator(newQueryContext("cursor="+url.QueryEscape(next)), entrySortKeys)
+	assert.NoError(t, err)
+
+	setCursorSecret(t, "another-secret")
+	_, err = ginx.NewCursorPaginator(newQueryContext("cursor="+url.QueryEscape(next)), entrySortKeys)
+	assert.Error(t, err)
+}
@@ -0,0 +1,320 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - Go 开发框架 (BlueKing - Go Framework) available.
+ * Copyright (C) 2017 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ *	https://opensource.org/licenses/MIT
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package ginx
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	"github.com/samber/lo"
+	"github.com/spf13/cast"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/TencentBlueKing/blueapps-go/pkg/i18n"
+)
+
+// 列表查询的保留参数
+const (
+	// 排序，如 ordering=-price,name（- 表示降序）
+	orderingParam = "ordering"
+	// 返回字段，如 fields=id,name
+	fieldsParam = "fields"
+	// 关键字搜索
+	keywordParam = "keyword"
+	// 过滤参数中字段与操作的分隔符，如 price__gte
+	lookupSep = "__"
+)
+
+// Lookup 过滤操作
+type Lookup string
+
+const (
+	// LookupExact 等于，如 price=1
+	LookupExact Lookup = "exact"
+	// LookupGt 大于，如 price__gt=1
+	LookupGt Lookup = "gt"
+	// LookupGte 大于等于
+	LookupGte Lookup = "gte"
+	// LookupLt 小于
+	LookupLt Lookup = "lt"
+	// LookupLte 小于等于
+	LookupLte Lookup = "lte"
+	// LookupIn 属于列表（逗号分隔），如 categoryID__in=1,2
+	LookupIn Lookup = "in"
+	// LookupContains 包含（忽略大小写），如 name__contains=go
+	LookupContains Lookup = "contains"
+)
+
+// ComparisonLookups 适用于数值 / 时间字段的过滤操作
+var ComparisonLookups = []Lookup{LookupExact, LookupGt, LookupGte, LookupLt, LookupLte, LookupIn}
+
+// FieldType 字段值类型，用于解析过滤参数
+type FieldType int
+
+const (
+	// FieldTypeString 字符串
+	FieldTypeString FieldType = iota
+	// FieldTypeInt 整数
+	FieldTypeInt
+	// FieldTypeFloat 浮点数
+	FieldTypeFloat
+	// FieldTypeBool 布尔值
+	FieldTypeBool
+	// FieldTypeTime 时间（RFC3339 格式或 2006-01-02）
+	FieldTypeTime
+)
+
+// QueryField 列表 API 中的字段
+type QueryField struct {
+	// 字段名，与响应中的 json key 一致（如 updatedAt）
+	Name string
+	// DB 列名（如 updated_at），为空表示仅能在 fields 中选择，不能过滤 / 排序
+	Column string
+	// 值类型
+	Type FieldType
+	// 允许的过滤操作，为空表示不能过滤
+	Lookups []Lookup
+	// 是否允许排序
+	Sortable bool
+}
+
+// QuerySchema 列表 API 的查询白名单，只有声明的字段可以过滤 / 排序 / 选择
+type QuerySchema struct {
+	Fields []QueryField
+	// keyword 参数搜索的 DB 列（忽略大小写的模糊匹配，任一列匹配即可）
+	SearchColumns []string
+	// 默认排序（格式同 ordering 参数，如 -id），为空表示不排序
+	DefaultOrdering string
+}
+
+// 获取字段定义
+func (s *QuerySchema) field(name string) (QueryField, bool) {
+	return lo.Find(s.Fields, func(f QueryField) bool { return f.Name == name })
+}
+
+// SortKey 排序键
+type SortKey struct {
+	// DB 列名
+	Column string
+	// 值类型（用于游标分页中解析排序键的值）
+	Type FieldType
+	// 是否降序
+	Desc bool
+}
+
+// ListQuery 解析后的列表查询
+type ListQuery struct {
+	// 过滤条件
+	conditions []clause.Expression
+	// 排序键
+	SortKeys []SortKey
+	// 选择返回的字段，为空表示返回全部字段
+	Fields []string
+}
+
+// ParseListQuery 按白名单解析列表查询参数：
+//
+//   - 过滤：field=value / field__{gt,gte,lt,lte,in,contains}=value，in 的值以逗号分隔
+//   - 排序：ordering=-price,name
+//   - 搜索：keyword=xxx
+//   - 字段选择：fields=id,name
+//
+// 白名单中的字段使用了不支持的过滤方式、带有 __ 但未在白名单中的过滤参数、不允许的排序 / 选择字段均会返回错误，
+// 其余未声明的参数会被忽略（如分页参数）
+func ParseListQuery(c *gin.Context, schema *QuerySchema) (*ListQuery, error) {
+	ctx, params := c.Request.Context(), c.Request.URL.Query()
+	query := &ListQuery{}
+
+	// 过滤（按参数名排序，保证生成的 SQL 稳定）
+	paramNames := lo.Keys(params)
+	slices.Sort(paramNames)
+	for _, param := range paramNames {
+		if param == orderingParam || param == fieldsParam || param == keywordParam {
+			continue
+		}
+		name, lookupStr, hasLookup := strings.Cut(param, lookupSep)
+		lookup := lo.Ternary(hasLookup, Lookup(lookupStr), LookupExact)
+
+		field, ok := schema.field(name)
+		// 非过滤参数（如 page / limit）
+		if !ok && !hasLookup {
+			continue
+		}
+		// 未声明的字段，或声明的字段不支持该过滤方式（如仅可选择的字段、仅支持 contains 的字段按 exact 过滤）
+		if !ok || field.Column == "" || !lo.Contains(field.Lookups, lookup) {
+			return nil, errors.Errorf(i18n.T(ctx, "unsupported filter: %s"), param)
+		}
+		cond, err := field.condition(lookup, params.Get(param))
+		if err != nil {
+			return nil, errors.Wrapf(err, i18n.T(ctx, "invalid value of %s"), param)
+		}
+		query.conditions = append(query.conditions, cond)
+	}
+
+	// 关键字搜索：任一列匹配即可（作为一个整体，不影响其他过滤条件）
+	if keyword := params.Get(keywordParam); keyword != "" && len(schema.SearchColumns) != 0 {
+		pattern := "%" + escapeLike(strings.ToLower(keyword)) + "%"
+		query.conditions = append(query.conditions, clause.Or(
+			lo.Map(schema.SearchColumns, func(column string, _ int) clause.Expression {
+				return clause.Expr{SQL: "LOWER(?) LIKE ?", Vars: []any{clause.Column{Name: column}, pattern}}
+			})...,
+		))
+	}
+
+	// 排序
+	ordering := lo.Ternary(params.Has(orderingParam), params.Get(orderingParam), schema.DefaultOrdering)
+	for _, item := range splitList(ordering) {
+		name, desc := strings.TrimPrefix(item, "-"), strings.HasPrefix(item, "-")
+		field, ok := schema.field(name)
+		if !ok || field.Column == "" || !field.Sortable {
+			return nil, errors.Errorf(i18n.T(ctx, "unsupported ordering field: %s"), name)
+		}
+		query.SortKeys = append(query.SortKeys, SortKey{Column: field.Column, Type: field.Type, Desc: desc})
+	}
+
+	// 字段选择
+	for _, name := range splitList(params.Get(fieldsParam)) {
+		if _, ok := schema.field(name); !ok {
+			return nil, errors.Errorf(i18n.T(ctx, "unsupported field: %s"), name)
+		}
+		query.Fields = append(query.Fields, name)
+	}
+	return query, nil
+}
+
+// Scope 将过滤条件 & 排序应用到查询，可通过 tx.Scopes(query.Scope) 使用
+func (q *ListQuery) Scope(tx *gorm.DB) *gorm.DB {
+	tx = q.FilterScope(tx)
+	for _, key := range q.SortKeys {
+		tx = tx.Order(clause.OrderByColumn{Column: clause.Column{Name: key.Column}, Desc: key.Desc})
+	}
+	return tx
+}
+
+// FilterScope 仅将过滤条件应用到查询（如游标分页时，排序由 CursorPaginator 负责）
+// 注：所有过滤条件以 AND 连接，关键字搜索的 OR 条件会被括号包裹，因此不会绕过其他过滤条件
+func (q *ListQuery) FilterScope(tx *gorm.DB) *gorm.DB {
+	if len(q.conditions) != 0 {
+		tx = tx.Where(clause.And(q.conditions...))
+	}
+	return tx
+}
+
+// SelectFields 仅保留 data（结构体或其切片）中选择的字段，未选择字段时原样返回
+func (q *ListQuery) SelectFields(data any) (any, error) {
+	if len(q.Fields) == 0 {
+		return data, nil
+	}
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	pick := func(item map[string]json.RawMessage) map[string]json.RawMessage {
+		return lo.PickByKeys(item, q.Fields)
+	}
+	if strings.HasPrefix(strings.TrimSpace(string(raw)), "[") {
+		var items []map[string]json.RawMessage
+		if err = json.Unmarshal(raw, &items); err != nil {
+			return nil, err
+		}
+		return lo.Map(items, func(item map[string]json.RawMessage, _ int) map[string]json.RawMessage {
+			return pick(item)
+		}), nil
+	}
+	var item map[string]json.RawMessage
+	if err = json.Unmarshal(raw, &item); err != nil {
+		return nil, err
+	}
+	return pick(item), nil
+}
+
+// 生成过滤条件
+func (f QueryField) condition(lookup Lookup, raw string) (clause.Expression, error) {
+	column := clause.Column{Name: f.Column}
+	if lookup == LookupContains {
+		return clause.Expr{
+			SQL: "LOWER(?) LIKE ?", Vars: []any{column, "%" + escapeLike(strings.ToLower(raw)) + "%"},
+		}, nil
+	}
+	if lookup == LookupIn {
+		values := []any{}
+		for _, item := range splitList(raw) {
+			value, err := f.parseValue(item)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+		}
+		return clause.IN{Column: column, Values: values}, nil
+	}
+
+	value, err := f.parseValue(raw)
+	if err != nil {
+		return nil, err
+	}
+	switch lookup {
+	case LookupGt:
+		return clause.Gt{Column: column, Value: value}, nil
+	case LookupGte:
+		return clause.Gte{Column: column, Value: value}, nil
+	case LookupLt:
+		return clause.Lt{Column: column, Value: value}, nil
+	case LookupLte:
+		return clause.Lte{Column: column, Value: value}, nil
+	default:
+		return clause.Eq{Column: column, Value: value}, nil
+	}
+}
+
+// 按字段类型解析值
+func (f QueryField) parseValue(raw string) (any, error) {
+	switch f.Type {
+	case FieldTypeInt:
+		return cast.ToInt64E(raw)
+	case FieldTypeFloat:
+		return cast.ToFloat64E(raw)
+	case FieldTypeBool:
+		return cast.ToBoolE(raw)
+	case FieldTypeTime:
+		if t, err := time.Parse(time.RFC3339, raw); err == nil {
+			return t, nil
+		}
+		return time.ParseInLocation(time.DateOnly, raw, time.Local)
+	default:
+		return raw, nil
+	}
+}
+
+// 拆分逗号分隔的列表（忽略空项）
+func splitList(s string) []string {
+	return lo.Compact(lo.Map(strings.Split(s, ","), func(item string, _ int) string {
+		return strings.TrimSpace(item)
+	}))
+}
+
+// 转义 LIKE 中的通配符
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
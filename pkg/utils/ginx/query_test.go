@@ -0,0 +1,155 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - Go 开发框架 (BlueKing - Go Framework) available.
+ * Copyright (C) 2017 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ *	https://opensource.org/licenses/MIT
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package ginx_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+
+	"github.com/TencentBlueKing/blueapps-go/pkg/utils/ginx"
+)
+
+type entry struct {
+	ID         int64     `json:"id"`
+	CategoryID int64     `json:"categoryID"`
+	Name       string    `json:"name"`
+	Desc       string    `json:"desc"`
+	Price      float32   `json:"price"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
+
+var entrySchema = &ginx.QuerySchema{
+	Fields: []ginx.QueryField{
+		{Name: "id", Column: "id", Type: ginx.FieldTypeInt, Lookups: ginx.ComparisonLookups, Sortable: true},
+		{
+			Name: "categoryID", Column: "category_id", Type: ginx.FieldTypeInt,
+			Lookups: []ginx.Lookup{ginx.LookupExact, ginx.LookupIn},
+		},
+		{Name: "name", Column: "name", Lookups: []ginx.Lookup{ginx.LookupExact, ginx.LookupContains}, Sortable: true},
+		{Name: "desc"},
+		{Name: "price", Column: "price", Type: ginx.FieldTypeFloat, Lookups: ginx.ComparisonLookups, Sortable: true},
+		{Name: "updatedAt", Column: "updated_at", Type: ginx.FieldTypeTime, Lookups: ginx.ComparisonLookups},
+	},
+	SearchColumns:   []string{"name", "desc"},
+	DefaultOrdering: "-id",
+}
+
+func newQueryContext(query string) *gin.Context {
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request, _ = http.NewRequest(http.MethodGet, "/entries?"+query, nil)
+	return c
+}
+
+// 仅生成 SQL，不连接 DB
+func dryRunDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(
+		mysql.New(mysql.Config{DSN: "user:pass@tcp(127.0.0.1:3306)/db", SkipInitializeWithVersion: true}),
+		&gorm.Config{DryRun: true, DisableAutomaticPing: true},
+	)
+	assert.NoError(t, err)
+	return db
+}
+
+// 生成列表查询的 SQL（不执行）
+func listSQL(t *testing.T, query *ginx.ListQuery) string {
+	return dryRunDB(t).ToSQL(func(tx *gorm.DB) *gorm.DB {
+		return tx.Model(&entry{}).Where("category_id = ?", 1).Scopes(query.Scope).Find(&[]entry{})
+	})
+}
+
+func toJSON(t *testing.T, data any) string {
+	raw, err := json.Marshal(data)
+	assert.NoError(t, err)
+	return string(raw)
+}
+
+func TestParseListQuery(t *testing.T) {
+	query, err := ginx.ParseListQuery(newQueryContext(
+		"price__gte=1.5&price__lt=10&categoryID__in=1,2&name__contains=50%25&ordering=-price,name&page=2",
+	), entrySchema)
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM `entries` WHERE category_id = 1 AND "+
+		"(`category_id` IN (1,2) AND LOWER(`name`) LIKE '%50\\%%' AND `price` >= 1.5 AND `price` < 10) "+
+		"ORDER BY `price` DESC,`name`", listSQL(t, query))
+
+	// 关键字搜索的 OR 条件被括号包裹，不会绕过其他过滤条件
+	query, err = ginx.ParseListQuery(newQueryContext("keyword=Go&id=3"), entrySchema)
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM `entries` WHERE category_id = 1 AND "+
+		"(`id` = 3 AND (LOWER(`name`) LIKE '%go%' OR LOWER(`desc`) LIKE '%go%')) "+
+		"ORDER BY `id` DESC", listSQL(t, query))
+
+	// 时间字段
+	query, err = ginx.ParseListQuery(newQueryContext("updatedAt__lt=2026-10-01T00:00:00Z"), entrySchema)
+	assert.NoError(t, err)
+	assert.Contains(t, listSQL(t, query), "`updated_at` < '2026-10-01 00:00:00'")
+
+	for _, q := range []string{
+		// 不在白名单中的字段 / 操作
+		"password__contains=1",
+		"price__contains=1",
+		"desc__contains=1",
+		// 白名单中的字段不支持的过滤方式（未带 __ 即为 exact）
+		"desc=1",
+		"updatedAt__contains=2026",
+		// 值不合法
+		"price__gte=abc",
+		"categoryID__in=1,a",
+		"updatedAt__lt=yesterday",
+		// 不允许的排序 / 选择字段
+		"ordering=categoryID",
+		"ordering=-desc",
+		"fields=id,password",
+	} {
+		_, err = ginx.ParseListQuery(newQueryContext(q), entrySchema)
+		assert.Error(t, err, q)
+	}
+}
+
+func TestSelectFields(t *testing.T) {
+	entries := []entry{{ID: 1, Name: "go", Price: 1.5}, {ID: 2, Name: "python", Price: 2}}
+
+	query, err := ginx.ParseListQuery(newQueryContext("fields=id,name"), entrySchema)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"id", "name"}, query.Fields)
+
+	data, err := query.SelectFields(entries)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `[{"id": 1, "name": "go"}, {"id": 2, "name": "python"}]`, toJSON(t, data))
+
+	data, err = query.SelectFields(entries[0])
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"id": 1, "name": "go"}`, toJSON(t, data))
+
+	// 未选择字段时原样返回
+	query, err = ginx.ParseListQuery(newQueryContext(""), entrySchema)
+	assert.NoError(t, err)
+	data, err = query.SelectFields(entries)
+	assert.NoError(t, err)
+	assert.Equal(t, entries, data)
+}
@@ -59,3 +59,17 @@ type PaginatedResp struct {
 func NewPaginatedRespData(count int64, results any) PaginatedResp {
 	return PaginatedResp{Count: count, Results: results}
 }
+
+// CursorPaginatedResp 游标分页响应数据体
+type CursorPaginatedResp struct {
+	Results any `json:"results"`
+	// 下一页 / 上一页的游标（作为 cursor 参数请求），为空表示没有更多数据
+	Next string `json:"next"`
+	Prev string `json:"prev"`
+}
+
+// NewCursorPaginatedRespData 创建游标分页响应数据体
+// 注意：results 类型应该是 Slice / Array
+func NewCursorPaginatedRespData(results any, next, prev string) CursorPaginatedResp {
+	return CursorPaginatedResp{Results: results, Next: next, Prev: prev}
+}
@@ -65,6 +65,11 @@ func GetAsyncTaskPage(c *gin.Context) {
 	renderHTML(c, "async_task.html", nil)
 }
 
+// GetDeadLetterPage 死信任务页面
+func GetDeadLetterPage(c *gin.Context) {
+	renderHTML(c, "dead_letter.html", nil)
+}
+
 // GetObjStoragePage 对象存储示例页面
 func GetObjStoragePage(c *gin.Context) {
 	renderHTML(c, "obj_storage.html", gin.H{"objectStorageEnabled": objstorage.IsBkRepoAvailable()})
@@ -33,5 +33,6 @@ func Register(rg *gin.RouterGroup) {
 	rg.GET("cache", handler.GetCachePage)
 	rg.GET("cloud-api", handler.GetCloudAPIPage)
 	rg.GET("async-task", handler.GetAsyncTaskPage)
+	rg.GET("dead-letters", handler.GetDeadLetterPage)
 	rg.GET("obj-storage", handler.GetObjStoragePage)
 }